@@ -0,0 +1,69 @@
+// Command openapi regenerates static/openapi.json from the registered routes.
+//
+//	go run ./cmd/openapi            # write static/openapi.json
+//	go run ./cmd/openapi -check     # exit non-zero if the committed spec is stale
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/rs/zerolog"
+	"github.com/uttam282005/tasker/internal/config"
+	"github.com/uttam282005/tasker/internal/handler"
+	"github.com/uttam282005/tasker/internal/openapi"
+	"github.com/uttam282005/tasker/internal/router"
+	"github.com/uttam282005/tasker/internal/server"
+	"github.com/uttam282005/tasker/internal/service"
+)
+
+func main() {
+	out := flag.String("out", "static/openapi.json", "path of the generated OpenAPI document")
+	check := flag.Bool("check", false, "fail if the document at -out is out of date instead of writing it")
+	flag.Parse()
+
+	if err := run(*out, *check); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(out string, check bool) error {
+	// The router only needs enough of the server to register routes; no
+	// connections are opened and no handler is ever invoked.
+	logger := zerolog.Nop()
+	srv := &server.Server{
+		Config: &config.Config{},
+		Logger: &logger,
+	}
+	services := &service.Services{}
+	r := router.NewRouter(srv, handler.NewHandlers(srv, services), services)
+
+	doc, err := openapi.Generate(r.Routes())
+	if err != nil {
+		return err
+	}
+
+	data, err := openapi.Marshal(doc)
+	if err != nil {
+		return err
+	}
+
+	if check {
+		current, err := os.ReadFile(out)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", out, err)
+		}
+		if !bytes.Equal(current, data) {
+			return fmt.Errorf("%s is out of date, run `task openapi:generate`", out)
+		}
+		return nil
+	}
+
+	if err := os.WriteFile(out, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", out, err)
+	}
+	return nil
+}
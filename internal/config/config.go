@@ -73,6 +73,8 @@ type CronConfig struct {
 	BatchSize                   int `koanf:"batch_size"`
 	ReminderHours               int `koanf:"reminder_hours"`
 	MaxTodosPerUserNotification int `koanf:"max_todos_per_user_notification"`
+	RecurrenceHorizonDays       int `koanf:"recurrence_horizon_days"`
+	TrashRetentionDays          int `koanf:"trash_retention_days"`
 }
 
 func DefaultCronConfig() *CronConfig {
@@ -81,6 +83,8 @@ func DefaultCronConfig() *CronConfig {
 		BatchSize:                   100,
 		ReminderHours:               24,
 		MaxTodosPerUserNotification: 10,
+		RecurrenceHorizonDays:       14,
+		TrashRetentionDays:          30,
 	}
 }
 
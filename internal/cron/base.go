@@ -8,6 +8,7 @@ import (
 	"github.com/redis/go-redis/v9"
 	"github.com/uttam282005/tasker/internal/config"
 	"github.com/uttam282005/tasker/internal/database"
+	"github.com/uttam282005/tasker/internal/lib/aws"
 	"github.com/uttam282005/tasker/internal/logger"
 	"github.com/uttam282005/tasker/internal/repository"
 	"github.com/uttam282005/tasker/internal/server"
@@ -18,6 +19,7 @@ type JobContext struct {
 	Server        *server.Server
 	JobClient     *asynq.Client
 	Repositories  *repository.Repositories
+	AWS           *aws.AWS
 	LoggerService *logger.LoggerService
 }
 
@@ -56,11 +58,17 @@ func NewJobContext() (*JobContext, error) {
 
 	repositories := repository.NewRepositories(srv)
 
+	awsClient, err := aws.NewAWS(srv)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create AWS client: %w", err)
+	}
+
 	return &JobContext{
 		Config:        cfg,
 		Server:        srv,
 		JobClient:     jobClient,
 		Repositories:  repositories,
+		AWS:           awsClient,
 		LoggerService: loggerService,
 	}, nil
 }
@@ -38,34 +38,36 @@ func (j *DueDateRemindersJob) Run(ctx context.Context, jobCtx *JobContext) error
 	enqueuedCount := 0
 
 	for _, todo := range todos {
-		if len(userTodos[todo.UserID]) < jobCtx.Config.Cron.MaxTodosPerUserNotification {
-			userTodos[todo.UserID] = append(userTodos[todo.UserID], todo.Title)
-		}
-
-		reminderTask := &job.ReminderEmailTask{
-			UserID:    todo.UserID,
-			TodoID:    todo.ID,
-			TodoTitle: todo.Title,
-			DueDate:   *todo.DueDate,
-			TaskType:  "due_date_reminder",
-		}
-
-		err := job.EnqueueReminderEmail(jobCtx.JobClient, reminderTask)
-		if err != nil {
-			jobCtx.Server.Logger.Error().
-				Err(err).
+		for _, userID := range reminderRecipients(todo) {
+			if len(userTodos[userID]) < jobCtx.Config.Cron.MaxTodosPerUserNotification {
+				userTodos[userID] = append(userTodos[userID], todo.Title)
+			}
+
+			reminderTask := &job.ReminderEmailTask{
+				UserID:    userID,
+				TodoID:    todo.ID,
+				TodoTitle: todo.Title,
+				DueDate:   *todo.DueDate,
+				TaskType:  "due_date_reminder",
+			}
+
+			err := job.EnqueueReminderEmail(jobCtx.JobClient, reminderTask)
+			if err != nil {
+				jobCtx.Server.Logger.Error().
+					Err(err).
+					Str("todo_id", todo.ID.String()).
+					Str("user_id", userID).
+					Msg("Failed to enqueue reminder email")
+				continue
+			}
+
+			enqueuedCount++
+			jobCtx.Server.Logger.Info().
 				Str("todo_id", todo.ID.String()).
-				Str("user_id", todo.UserID).
-				Msg("Failed to enqueue reminder email")
-			continue
+				Str("todo_title", todo.Title).
+				Str("user_id", userID).
+				Msg("Enqueued reminder for todo")
 		}
-
-		enqueuedCount++
-		jobCtx.Server.Logger.Info().
-			Str("todo_id", todo.ID.String()).
-			Str("todo_title", todo.Title).
-			Str("user_id", todo.UserID).
-			Msg("Enqueued reminder for todo")
 	}
 
 	jobCtx.Server.Logger.Info().
@@ -82,6 +84,15 @@ func (j *DueDateRemindersJob) Run(ctx context.Context, jobCtx *JobContext) error
 	return nil
 }
 
+// reminderRecipients returns who hears about a todo's due date: its creator
+// and, when someone else is responsible for it, its assignee.
+func reminderRecipients(t todo.Todo) []string {
+	if t.AssigneeID != nil && *t.AssigneeID != t.UserID {
+		return []string{t.UserID, *t.AssigneeID}
+	}
+	return []string{t.UserID}
+}
+
 // --------------------------
 
 type OverdueNotificationsJob struct{}
@@ -108,34 +119,36 @@ func (j *OverdueNotificationsJob) Run(ctx context.Context, jobCtx *JobContext) e
 	enqueuedCount := 0
 
 	for _, todo := range todos {
-		if len(userTodos[todo.UserID]) < jobCtx.Config.Cron.MaxTodosPerUserNotification {
-			userTodos[todo.UserID] = append(userTodos[todo.UserID], todo.Title)
-		}
-
-		overdueTask := &job.ReminderEmailTask{
-			UserID:    todo.UserID,
-			TodoID:    todo.ID,
-			TodoTitle: todo.Title,
-			DueDate:   *todo.DueDate,
-			TaskType:  "overdue_notification",
-		}
-
-		err := job.EnqueueReminderEmail(jobCtx.JobClient, overdueTask)
-		if err != nil {
-			jobCtx.Server.Logger.Error().
-				Err(err).
+		for _, userID := range reminderRecipients(todo) {
+			if len(userTodos[userID]) < jobCtx.Config.Cron.MaxTodosPerUserNotification {
+				userTodos[userID] = append(userTodos[userID], todo.Title)
+			}
+
+			overdueTask := &job.ReminderEmailTask{
+				UserID:    userID,
+				TodoID:    todo.ID,
+				TodoTitle: todo.Title,
+				DueDate:   *todo.DueDate,
+				TaskType:  "overdue_notification",
+			}
+
+			err := job.EnqueueReminderEmail(jobCtx.JobClient, overdueTask)
+			if err != nil {
+				jobCtx.Server.Logger.Error().
+					Err(err).
+					Str("todo_id", todo.ID.String()).
+					Str("user_id", userID).
+					Msg("Failed to enqueue overdue notification")
+				continue
+			}
+
+			enqueuedCount++
+			jobCtx.Server.Logger.Info().
 				Str("todo_id", todo.ID.String()).
-				Str("user_id", todo.UserID).
-				Msg("Failed to enqueue overdue notification")
-			continue
+				Str("todo_title", todo.Title).
+				Str("user_id", userID).
+				Msg("Enqueued overdue notification")
 		}
-
-		enqueuedCount++
-		jobCtx.Server.Logger.Info().
-			Str("todo_id", todo.ID.String()).
-			Str("todo_title", todo.Title).
-			Str("user_id", todo.UserID).
-			Msg("Enqueued overdue notification")
 	}
 
 	jobCtx.Server.Logger.Info().
@@ -293,3 +306,163 @@ func (j *AutoArchiveJob) Run(ctx context.Context, jobCtx *JobContext) error {
 
 	return nil
 }
+
+// --------
+
+type MaterializeRecurrencesJob struct{}
+
+func (j *MaterializeRecurrencesJob) Name() string {
+	return "materialize-recurrences"
+}
+
+func (j *MaterializeRecurrencesJob) Description() string {
+	return "Generate upcoming occurrences of recurring todos"
+}
+
+func (j *MaterializeRecurrencesJob) Run(ctx context.Context, jobCtx *JobContext) error {
+	horizon := time.Now().AddDate(0, 0, jobCtx.Config.Cron.RecurrenceHorizonDays)
+
+	jobCtx.Server.Logger.Info().
+		Time("horizon", horizon).
+		Msg("Materializing recurring todo occurrences")
+
+	createdCount := 0
+	seriesCount := 0
+	afterSeriesID := uuid.Nil
+
+	for {
+		latest, err := jobCtx.Repositories.Todo.GetLatestOccurrencesBefore(ctx, horizon, afterSeriesID, jobCtx.Config.Cron.BatchSize)
+		if err != nil {
+			return err
+		}
+
+		if len(latest) == 0 {
+			break
+		}
+
+		for _, occurrence := range latest {
+			seriesCount++
+			current := occurrence
+
+			// Generate occurrences until the series passes the horizon or ends
+			for current.RecurrenceDate != nil && !current.RecurrenceDate.After(horizon) {
+				next, err := jobCtx.Repositories.Todo.CreateNextOccurrence(ctx, &current)
+				if err != nil {
+					jobCtx.Server.Logger.Error().
+						Err(err).
+						Str("todo_id", current.ID.String()).
+						Str("user_id", current.UserID).
+						Msg("Failed to create next occurrence")
+					break
+				}
+
+				if next == nil {
+					break
+				}
+
+				createdCount++
+				current = *next
+			}
+		}
+
+		afterSeriesID = *latest[len(latest)-1].RecurrenceSeriesID
+	}
+
+	jobCtx.Server.Logger.Info().
+		Int("created_count", createdCount).
+		Int("series_count", seriesCount).
+		Msg("Recurring todo occurrences materialized")
+
+	return nil
+}
+
+// --------
+
+type PurgeTrashJob struct{}
+
+func (j *PurgeTrashJob) Name() string {
+	return "purge-trash"
+}
+
+func (j *PurgeTrashJob) Description() string {
+	return "Permanently delete todos, comments and categories left in the trash"
+}
+
+func (j *PurgeTrashJob) Run(ctx context.Context, jobCtx *JobContext) error {
+	cutoffDate := time.Now().AddDate(0, 0, -jobCtx.Config.Cron.TrashRetentionDays)
+
+	jobCtx.Server.Logger.Info().
+		Time("cutoff_date", cutoffDate).
+		Msg("Searching for trashed todos to purge")
+
+	todos, err := jobCtx.Repositories.Todo.GetTrashedTodosOlderThan(ctx, cutoffDate, jobCtx.Config.Cron.BatchSize)
+	if err != nil {
+		return err
+	}
+
+	jobCtx.Server.Logger.Info().
+		Int("todo_count", len(todos)).
+		Msg("Found trashed todos to purge")
+
+	// Attachment rows cascade with their todo, so the S3 objects go first. A
+	// todo whose objects couldn't all be deleted is kept for the next run.
+	todoIDs := make([]uuid.UUID, 0, len(todos))
+	deletedObjects := 0
+
+	for _, todo := range todos {
+		attachments, err := jobCtx.Repositories.Todo.GetTodoTreeAttachments(ctx, todo.ID)
+		if err != nil {
+			jobCtx.Server.Logger.Error().
+				Err(err).
+				Str("todo_id", todo.ID.String()).
+				Msg("Failed to fetch attachments of trashed todo")
+			continue
+		}
+
+		purgeable := true
+		for _, attachment := range attachments {
+			err := jobCtx.AWS.S3.DeleteObject(ctx, jobCtx.Config.AWS.UploadBucket, attachment.DownloadKey)
+			if err != nil {
+				jobCtx.Server.Logger.Error().
+					Err(err).
+					Str("todo_id", todo.ID.String()).
+					Str("s3_key", attachment.DownloadKey).
+					Msg("Failed to delete attachment from S3")
+				purgeable = false
+				continue
+			}
+			deletedObjects++
+		}
+
+		if purgeable {
+			todoIDs = append(todoIDs, todo.ID)
+		}
+	}
+
+	purgedTodos := int64(0)
+	if len(todoIDs) > 0 {
+		purgedTodos, err = jobCtx.Repositories.Todo.PurgeTodos(ctx, todoIDs)
+		if err != nil {
+			return err
+		}
+	}
+
+	purgedComments, err := jobCtx.Repositories.Comment.PurgeCommentsDeletedBefore(ctx, cutoffDate)
+	if err != nil {
+		return err
+	}
+
+	purgedCategories, err := jobCtx.Repositories.Category.PurgeCategoriesDeletedBefore(ctx, cutoffDate)
+	if err != nil {
+		return err
+	}
+
+	jobCtx.Server.Logger.Info().
+		Int64("purged_todos", purgedTodos).
+		Int64("purged_comments", purgedComments).
+		Int64("purged_categories", purgedCategories).
+		Int("deleted_objects", deletedObjects).
+		Msg("Trash purged")
+
+	return nil
+}
@@ -18,6 +18,8 @@ func NewJobRegistry() *JobRegistry {
 	registry.Register(&OverdueNotificationsJob{})
 	registry.Register(&WeeklyReportsJob{})
 	registry.Register(&AutoArchiveJob{})
+	registry.Register(&MaterializeRecurrencesJob{})
+	registry.Register(&PurgeTrashJob{})
 
 	return registry
 }
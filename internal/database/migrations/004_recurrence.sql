@@ -0,0 +1,12 @@
+ALTER TABLE todos
+    ADD COLUMN recurrence TEXT,
+    ADD COLUMN recurrence_series_id UUID,
+    ADD COLUMN recurrence_index INTEGER,
+    ADD COLUMN recurrence_date TIMESTAMPTZ;
+
+-- One row per occurrence; generating the same occurrence twice is a no-op
+CREATE UNIQUE INDEX idx_todos_recurrence_series_index ON todos(recurrence_series_id, recurrence_index);
+
+CREATE INDEX idx_todos_recurrence_date ON todos(recurrence_date)
+WHERE
+    recurrence IS NOT NULL;
@@ -0,0 +1,21 @@
+CREATE TABLE todo_dependencies (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    user_id TEXT NOT NULL,
+    -- blocking_todo_id must be completed before blocked_todo_id can be
+    blocking_todo_id UUID NOT NULL REFERENCES todos ON DELETE CASCADE,
+    blocked_todo_id UUID NOT NULL REFERENCES todos ON DELETE CASCADE,
+
+    CONSTRAINT no_self_dependency CHECK (blocking_todo_id != blocked_todo_id)
+);
+
+CREATE UNIQUE INDEX idx_todo_dependencies_edge ON todo_dependencies(blocking_todo_id, blocked_todo_id);
+CREATE INDEX idx_todo_dependencies_blocked_todo_id ON todo_dependencies(blocked_todo_id);
+CREATE INDEX idx_todo_dependencies_user_id ON todo_dependencies(user_id);
+
+CREATE TRIGGER set_updated_at_todo_dependencies
+    BEFORE UPDATE ON todo_dependencies
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_set_updated_at();
@@ -0,0 +1,14 @@
+-- sort_order becomes a gapped rank: values are spaced SortOrderGap apart so a
+-- move can usually take the midpoint between its new neighbours without
+-- renumbering the rest of the list.
+ALTER TABLE todos
+    ALTER COLUMN sort_order TYPE BIGINT;
+
+UPDATE todos
+SET
+    sort_order = sort_order * 1024;
+
+ALTER TABLE todos
+    ALTER COLUMN sort_order SET DEFAULT nextval('todos_sort_order_seq') * 1024;
+
+CREATE INDEX idx_todos_user_sort_order ON todos(user_id, sort_order);
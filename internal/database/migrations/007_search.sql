@@ -0,0 +1,13 @@
+ALTER TABLE todos
+    ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', COALESCE(title, '')), 'A') ||
+        setweight(to_tsvector('english', COALESCE(description, '')), 'B')
+    ) STORED;
+
+ALTER TABLE todo_comments
+    ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (
+        to_tsvector('english', COALESCE(content, ''))
+    ) STORED;
+
+CREATE INDEX idx_todos_search_vector ON todos USING GIN(search_vector);
+CREATE INDEX idx_todo_comments_search_vector ON todo_comments USING GIN(search_vector);
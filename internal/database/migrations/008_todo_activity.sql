@@ -0,0 +1,18 @@
+-- Append-only audit trail. todo_id deliberately has no foreign key so that
+-- entries describing a deleted todo outlive it.
+CREATE TABLE todo_activity (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    todo_id UUID,
+    user_id TEXT NOT NULL,
+    actor_id TEXT NOT NULL,
+    entity_type TEXT NOT NULL CHECK (entity_type IN ('todo', 'comment', 'attachment', 'category')),
+    entity_id UUID NOT NULL,
+    action TEXT NOT NULL CHECK (action IN ('created', 'updated', 'deleted')),
+    changes JSONB NOT NULL DEFAULT '{}'::JSONB
+);
+
+CREATE INDEX idx_todo_activity_todo_id_created_at ON todo_activity(todo_id, created_at DESC);
+CREATE INDEX idx_todo_activity_user_id ON todo_activity(user_id);
+CREATE INDEX idx_todo_activity_entity ON todo_activity(entity_type, entity_id);
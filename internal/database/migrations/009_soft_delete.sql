@@ -0,0 +1,32 @@
+ALTER TABLE todos
+    ADD COLUMN deleted_at TIMESTAMPTZ;
+
+ALTER TABLE todo_categories
+    ADD COLUMN deleted_at TIMESTAMPTZ;
+
+ALTER TABLE todo_comments
+    ADD COLUMN deleted_at TIMESTAMPTZ;
+
+-- Trash listings and the purge job only ever look at deleted rows
+CREATE INDEX idx_todos_deleted_at ON todos(user_id, deleted_at)
+WHERE
+    deleted_at IS NOT NULL;
+
+CREATE INDEX idx_todo_categories_deleted_at ON todo_categories(user_id, deleted_at)
+WHERE
+    deleted_at IS NOT NULL;
+
+CREATE INDEX idx_todo_comments_deleted_at ON todo_comments(user_id, deleted_at)
+WHERE
+    deleted_at IS NOT NULL;
+
+-- Trashed categories shouldn't keep their name reserved
+DROP INDEX idx_todo_categories_user_id_name;
+
+CREATE UNIQUE INDEX idx_todo_categories_user_id_name ON todo_categories(user_id, name)
+WHERE
+    deleted_at IS NULL;
+
+ALTER TABLE todo_activity
+    DROP CONSTRAINT todo_activity_action_check,
+    ADD CONSTRAINT todo_activity_action_check CHECK (action IN ('created', 'updated', 'deleted', 'restored'));
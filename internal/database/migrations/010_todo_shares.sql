@@ -0,0 +1,159 @@
+-- A share grants grantee_id a role on a single todo or on every todo in a
+-- category. Subtasks are covered by the grants of their parent.
+CREATE TABLE todo_shares (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    owner_id TEXT NOT NULL,
+    grantee_id TEXT NOT NULL,
+    todo_id UUID REFERENCES todos ON DELETE CASCADE,
+    category_id UUID REFERENCES todo_categories ON DELETE CASCADE,
+    role TEXT NOT NULL CHECK (role IN ('viewer', 'commenter', 'editor')),
+
+    CONSTRAINT share_single_target CHECK ((todo_id IS NULL) != (category_id IS NULL)),
+    CONSTRAINT share_not_self CHECK (owner_id != grantee_id)
+);
+
+CREATE UNIQUE INDEX idx_todo_shares_grantee_todo ON todo_shares(grantee_id, todo_id)
+WHERE
+    todo_id IS NOT NULL;
+
+CREATE UNIQUE INDEX idx_todo_shares_grantee_category ON todo_shares(grantee_id, category_id)
+WHERE
+    category_id IS NOT NULL;
+
+CREATE INDEX idx_todo_shares_todo_id ON todo_shares(todo_id);
+CREATE INDEX idx_todo_shares_category_id ON todo_shares(category_id);
+CREATE INDEX idx_todo_shares_owner_id ON todo_shares(owner_id);
+
+CREATE TRIGGER set_updated_at_todo_shares
+    BEFORE UPDATE ON todo_shares
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_set_updated_at();
+
+-- Higher ranks include every permission of the lower ones; NULL means no access
+CREATE FUNCTION share_role_rank(role TEXT) RETURNS INTEGER
+LANGUAGE sql IMMUTABLE AS $$
+    SELECT
+        CASE role
+            WHEN 'owner' THEN 4
+            WHEN 'editor' THEN 3
+            WHEN 'commenter' THEN 2
+            WHEN 'viewer' THEN 1
+            ELSE 0
+        END
+$$;
+
+-- The strongest role p_user_id holds on a todo, or NULL without access
+CREATE FUNCTION todo_role(p_todo_id UUID, p_user_id TEXT) RETURNS TEXT
+LANGUAGE sql STABLE AS $$
+    SELECT
+        grants.role
+    FROM
+        todos t
+        LEFT JOIN todos parent ON parent.id = t.parent_todo_id
+        CROSS JOIN LATERAL (
+            SELECT
+                'owner' AS role
+            WHERE
+                t.user_id = p_user_id
+            UNION ALL
+            SELECT
+                s.role
+            FROM
+                todo_shares s
+            WHERE
+                s.grantee_id = p_user_id
+                AND (
+                    s.todo_id IN (t.id, t.parent_todo_id)
+                    OR s.category_id IN (t.category_id, parent.category_id)
+                )
+        ) grants
+    WHERE
+        t.id = p_todo_id
+    ORDER BY
+        share_role_rank(grants.role) DESC
+    LIMIT
+        1
+$$;
+
+-- Every todo p_user_id can see, with their strongest role on it
+CREATE FUNCTION accessible_todos(p_user_id TEXT) RETURNS TABLE (todo_id UUID, role TEXT)
+LANGUAGE sql STABLE AS $$
+    SELECT DISTINCT ON (grants.todo_id)
+        grants.todo_id,
+        grants.role
+    FROM
+        (
+            SELECT
+                t.id AS todo_id,
+                'owner' AS role
+            FROM
+                todos t
+            WHERE
+                t.user_id = p_user_id
+            UNION ALL
+            SELECT
+                t.id,
+                s.role
+            FROM
+                todo_shares s
+                JOIN todos t ON t.id = s.todo_id
+            WHERE
+                s.grantee_id = p_user_id
+            UNION ALL
+            SELECT
+                t.id,
+                s.role
+            FROM
+                todo_shares s
+                JOIN todos t ON t.parent_todo_id = s.todo_id
+            WHERE
+                s.grantee_id = p_user_id
+            UNION ALL
+            SELECT
+                t.id,
+                s.role
+            FROM
+                todo_shares s
+                JOIN todos t ON t.category_id = s.category_id
+            WHERE
+                s.grantee_id = p_user_id
+            UNION ALL
+            SELECT
+                t.id,
+                s.role
+            FROM
+                todo_shares s
+                JOIN todos parent ON parent.category_id = s.category_id
+                JOIN todos t ON t.parent_todo_id = parent.id
+            WHERE
+                s.grantee_id = p_user_id
+        ) grants
+    ORDER BY
+        grants.todo_id,
+        share_role_rank(grants.role) DESC
+$$;
+
+-- The strongest role p_user_id holds on a category, or NULL without access
+CREATE FUNCTION category_role(p_category_id UUID, p_user_id TEXT) RETURNS TEXT
+LANGUAGE sql STABLE AS $$
+    SELECT
+        CASE
+            WHEN c.user_id = p_user_id THEN 'owner'
+            ELSE (
+                SELECT
+                    s.role
+                FROM
+                    todo_shares s
+                WHERE
+                    s.category_id = c.id
+                    AND s.grantee_id = p_user_id
+            )
+        END
+    FROM
+        todo_categories c
+    WHERE
+        c.id = p_category_id
+$$;
@@ -0,0 +1,170 @@
+-- Rows with an org_id belong to that organization's shared workspace; rows
+-- without one are the creator's personal todos and categories.
+ALTER TABLE todos
+    ADD COLUMN org_id TEXT;
+
+ALTER TABLE todo_categories
+    ADD COLUMN org_id TEXT;
+
+CREATE INDEX idx_todos_org_id ON todos(org_id)
+WHERE
+    org_id IS NOT NULL;
+
+CREATE INDEX idx_todo_categories_org_id ON todo_categories(org_id)
+WHERE
+    org_id IS NOT NULL;
+
+-- Category names are unique per workspace
+DROP INDEX idx_todo_categories_user_id_name;
+
+CREATE UNIQUE INDEX idx_todo_categories_user_id_name ON todo_categories(user_id, name)
+WHERE
+    deleted_at IS NULL
+    AND org_id IS NULL;
+
+CREATE UNIQUE INDEX idx_todo_categories_org_id_name ON todo_categories(org_id, name)
+WHERE
+    deleted_at IS NULL
+    AND org_id IS NOT NULL;
+
+-- The access functions now take the workspace the request runs in. Inside an
+-- organization every member has full access to its todos, and organization
+-- permissions decide what they may do. Personal todos are never visible from
+-- an organization and shares only apply to personal todos.
+DROP FUNCTION todo_role(UUID, TEXT);
+DROP FUNCTION accessible_todos(TEXT);
+DROP FUNCTION category_role(UUID, TEXT);
+
+CREATE FUNCTION todo_role(p_todo_id UUID, p_user_id TEXT, p_org_id TEXT) RETURNS TEXT
+LANGUAGE sql STABLE AS $$
+    SELECT
+        grants.role
+    FROM
+        todos t
+        LEFT JOIN todos parent ON parent.id = t.parent_todo_id
+        CROSS JOIN LATERAL (
+            SELECT
+                'owner' AS role
+            WHERE
+                (p_org_id IS NOT NULL AND t.org_id = p_org_id)
+                OR (p_org_id IS NULL AND t.org_id IS NULL AND t.user_id = p_user_id)
+            UNION ALL
+            SELECT
+                s.role
+            FROM
+                todo_shares s
+            WHERE
+                p_org_id IS NULL
+                AND t.org_id IS NULL
+                AND s.grantee_id = p_user_id
+                AND (
+                    s.todo_id IN (t.id, t.parent_todo_id)
+                    OR s.category_id IN (t.category_id, parent.category_id)
+                )
+        ) grants
+    WHERE
+        t.id = p_todo_id
+    ORDER BY
+        share_role_rank(grants.role) DESC
+    LIMIT
+        1
+$$;
+
+CREATE FUNCTION accessible_todos(p_user_id TEXT, p_org_id TEXT) RETURNS TABLE (todo_id UUID, role TEXT)
+LANGUAGE sql STABLE AS $$
+    SELECT DISTINCT ON (grants.todo_id)
+        grants.todo_id,
+        grants.role
+    FROM
+        (
+            SELECT
+                t.id AS todo_id,
+                'owner' AS role
+            FROM
+                todos t
+            WHERE
+                p_org_id IS NOT NULL
+                AND t.org_id = p_org_id
+            UNION ALL
+            SELECT
+                t.id,
+                'owner'
+            FROM
+                todos t
+            WHERE
+                p_org_id IS NULL
+                AND t.user_id = p_user_id
+                AND t.org_id IS NULL
+            UNION ALL
+            SELECT
+                t.id,
+                s.role
+            FROM
+                todo_shares s
+                JOIN todos t ON t.id = s.todo_id
+            WHERE
+                p_org_id IS NULL
+                AND s.grantee_id = p_user_id
+                AND t.org_id IS NULL
+            UNION ALL
+            SELECT
+                t.id,
+                s.role
+            FROM
+                todo_shares s
+                JOIN todos t ON t.parent_todo_id = s.todo_id
+            WHERE
+                p_org_id IS NULL
+                AND s.grantee_id = p_user_id
+                AND t.org_id IS NULL
+            UNION ALL
+            SELECT
+                t.id,
+                s.role
+            FROM
+                todo_shares s
+                JOIN todos t ON t.category_id = s.category_id
+            WHERE
+                p_org_id IS NULL
+                AND s.grantee_id = p_user_id
+                AND t.org_id IS NULL
+            UNION ALL
+            SELECT
+                t.id,
+                s.role
+            FROM
+                todo_shares s
+                JOIN todos parent ON parent.category_id = s.category_id
+                JOIN todos t ON t.parent_todo_id = parent.id
+            WHERE
+                p_org_id IS NULL
+                AND s.grantee_id = p_user_id
+                AND t.org_id IS NULL
+        ) grants
+    ORDER BY
+        grants.todo_id,
+        share_role_rank(grants.role) DESC
+$$;
+
+CREATE FUNCTION category_role(p_category_id UUID, p_user_id TEXT, p_org_id TEXT) RETURNS TEXT
+LANGUAGE sql STABLE AS $$
+    SELECT
+        CASE
+            WHEN p_org_id IS NOT NULL THEN 'owner'
+            WHEN c.user_id = p_user_id THEN 'owner'
+            ELSE (
+                SELECT
+                    s.role
+                FROM
+                    todo_shares s
+                WHERE
+                    s.category_id = c.id
+                    AND s.grantee_id = p_user_id
+            )
+        END
+    FROM
+        todo_categories c
+    WHERE
+        c.id = p_category_id
+        AND c.org_id IS NOT DISTINCT FROM p_org_id
+$$;
@@ -0,0 +1,26 @@
+-- The member responsible for a todo. Reminders go to the assignee as well as
+-- the creator.
+ALTER TABLE todos
+    ADD COLUMN assignee_id TEXT;
+
+CREATE INDEX idx_todos_assignee_id ON todos(assignee_id)
+WHERE
+    assignee_id IS NOT NULL;
+
+-- Users who are notified whenever a todo changes
+CREATE TABLE todo_watchers (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    todo_id UUID NOT NULL REFERENCES todos ON DELETE CASCADE,
+    user_id TEXT NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_todo_watchers_todo_id_user_id ON todo_watchers(todo_id, user_id);
+CREATE INDEX idx_todo_watchers_user_id ON todo_watchers(user_id);
+
+CREATE TRIGGER set_updated_at_todo_watchers
+    BEFORE UPDATE ON todo_watchers
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_set_updated_at();
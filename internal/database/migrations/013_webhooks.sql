@@ -0,0 +1,55 @@
+-- Endpoints that receive todo events. Personal webhooks see the creator's
+-- personal todos; organization webhooks see every todo in the organization.
+CREATE TABLE webhooks (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    user_id TEXT NOT NULL,
+    org_id TEXT,
+    url TEXT NOT NULL,
+    -- Shared secret used to sign each delivery with HMAC-SHA256
+    secret TEXT NOT NULL,
+    -- Event types the endpoint subscribes to, e.g. ["todo.created"]
+    events JSONB NOT NULL DEFAULT '[]'::JSONB,
+    active BOOLEAN NOT NULL DEFAULT TRUE
+);
+
+CREATE INDEX idx_webhooks_user_id ON webhooks(user_id)
+WHERE
+    org_id IS NULL;
+
+CREATE INDEX idx_webhooks_org_id ON webhooks(org_id)
+WHERE
+    org_id IS NOT NULL;
+
+CREATE TRIGGER set_updated_at_webhooks
+    BEFORE UPDATE ON webhooks
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_set_updated_at();
+
+-- One row per event sent to an endpoint. Retries update the row; a
+-- redelivery starts a new one.
+CREATE TABLE webhook_deliveries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    webhook_id UUID NOT NULL REFERENCES webhooks ON DELETE CASCADE,
+    event TEXT NOT NULL,
+    payload JSONB NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'succeeded', 'failed')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    response_code INTEGER,
+    response_body TEXT,
+    error TEXT,
+    duration_ms INTEGER,
+    delivered_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_webhook_deliveries_webhook_id_created_at ON webhook_deliveries(webhook_id, created_at DESC);
+
+CREATE TRIGGER set_updated_at_webhook_deliveries
+    BEFORE UPDATE ON webhook_deliveries
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_set_updated_at();
@@ -0,0 +1,33 @@
+-- Row versions back the ETags used for optimistic concurrency. Every update
+-- bumps the version, so an If-Match carrying an older one is refused.
+CREATE OR REPLACE FUNCTION trigger_increment_version()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.version = OLD.version + 1;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE todos
+    ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+
+ALTER TABLE todo_categories
+    ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+
+ALTER TABLE todo_comments
+    ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+
+CREATE TRIGGER increment_version_todos
+    BEFORE UPDATE ON todos
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_increment_version();
+
+CREATE TRIGGER increment_version_todo_categories
+    BEFORE UPDATE ON todo_categories
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_increment_version();
+
+CREATE TRIGGER increment_version_todo_comments
+    BEFORE UPDATE ON todo_comments
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_increment_version();
@@ -0,0 +1,9 @@
+-- Cursor pagination seeks to (sort key, id) instead of skipping rows, which
+-- only pays off with an index on that pair. Undated todos sort as if due at
+-- infinity, matching the expression the queries order by.
+CREATE INDEX idx_todos_created_at_id ON todos(created_at, id);
+CREATE INDEX idx_todos_updated_at_id ON todos(updated_at, id);
+CREATE INDEX idx_todos_due_date_id ON todos((COALESCE(due_date, 'infinity'::TIMESTAMPTZ)), id);
+
+CREATE INDEX idx_todo_categories_created_at_id ON todo_categories(created_at, id);
+CREATE INDEX idx_todo_categories_updated_at_id ON todo_categories(updated_at, id);
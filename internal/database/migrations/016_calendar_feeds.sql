@@ -0,0 +1,24 @@
+-- Calendar subscriptions. The token in a feed's URL is its only credential,
+-- so only its SHA-256 is kept, and deleting the feed revokes it.
+CREATE TABLE calendar_feeds (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    user_id TEXT NOT NULL,
+    org_id TEXT,
+    name TEXT NOT NULL,
+    token_hash TEXT NOT NULL UNIQUE,
+    -- Todos are rendered as VTODO or VEVENT; some calendars only show events
+    component TEXT NOT NULL DEFAULT 'vevent' CHECK (component IN ('vtodo', 'vevent')),
+    -- Categories the feed is limited to, e.g. ["<uuid>"]; empty for all
+    category_ids JSONB NOT NULL DEFAULT '[]'::JSONB,
+    last_fetched_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_calendar_feeds_user_id ON calendar_feeds(user_id, org_id);
+
+CREATE TRIGGER set_updated_at_calendar_feeds
+    BEFORE UPDATE ON calendar_feeds
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_set_updated_at();
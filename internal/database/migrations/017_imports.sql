@@ -0,0 +1,38 @@
+-- Todos imported from other apps by a background job. The uploaded file is
+-- kept in import_files until the job has read it, so polling an import's
+-- progress never loads it.
+CREATE TABLE imports (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    user_id TEXT NOT NULL,
+    org_id TEXT,
+    source TEXT NOT NULL CHECK (source IN ('todoist', 'trello', 'csv')),
+    file_name TEXT NOT NULL,
+    -- Source columns of a generic CSV, e.g. {"title": "Task", "dueDate": "Due"}
+    mapping JSONB,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'running', 'completed', 'failed')),
+    total_rows INTEGER NOT NULL DEFAULT 0,
+    processed_rows INTEGER NOT NULL DEFAULT 0,
+    imported_rows INTEGER NOT NULL DEFAULT 0,
+    failed_rows INTEGER NOT NULL DEFAULT 0,
+    -- One entry per row that wasn't imported, or was imported in part
+    row_errors JSONB NOT NULL DEFAULT '[]'::JSONB,
+    -- Why the whole import failed, e.g. the file couldn't be parsed
+    error TEXT,
+    started_at TIMESTAMPTZ,
+    completed_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_imports_user_id_created_at ON imports(user_id, created_at DESC);
+
+CREATE TRIGGER set_updated_at_imports
+    BEFORE UPDATE ON imports
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_set_updated_at();
+
+CREATE TABLE import_files (
+    import_id UUID PRIMARY KEY REFERENCES imports ON DELETE CASCADE,
+    data BYTEA NOT NULL
+);
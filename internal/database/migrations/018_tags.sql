@@ -0,0 +1,90 @@
+-- Tags belong to a workspace like categories do, and todos link to them
+-- through todo_tags, so renaming a tag renames it on every todo.
+CREATE TABLE tags (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    -- The creator; in an organization the tag is shared by every member
+    user_id TEXT NOT NULL,
+    org_id TEXT,
+    name TEXT NOT NULL,
+    color TEXT NOT NULL DEFAULT '#6b7280'
+);
+
+-- Tag names are unique per workspace
+CREATE UNIQUE INDEX idx_tags_user_id_name ON tags(user_id, name)
+WHERE
+    org_id IS NULL;
+
+CREATE UNIQUE INDEX idx_tags_org_id_name ON tags(org_id, name)
+WHERE
+    org_id IS NOT NULL;
+
+CREATE TRIGGER set_updated_at_tags
+    BEFORE UPDATE ON tags
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_set_updated_at();
+
+CREATE TABLE todo_tags (
+    todo_id UUID NOT NULL REFERENCES todos ON DELETE CASCADE,
+    tag_id UUID NOT NULL REFERENCES tags ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (todo_id, tag_id)
+);
+
+CREATE INDEX idx_todo_tags_tag_id ON todo_tags(tag_id);
+
+-- Move the tags out of todo metadata. A tag in an organization is credited
+-- to whoever first used it there.
+WITH
+    todo_tag_names AS (
+        SELECT DISTINCT
+            t.id AS todo_id,
+            t.user_id,
+            t.org_id,
+            t.created_at,
+            BTRIM(tag.name) AS name
+        FROM
+            todos t
+            CROSS JOIN LATERAL jsonb_array_elements_text(t.metadata -> 'tags') AS tag (name)
+        WHERE
+            jsonb_typeof(t.metadata -> 'tags')='array'
+            AND BTRIM(tag.name)<>''
+    )
+INSERT INTO
+    tags (user_id, org_id, name)
+SELECT DISTINCT ON (COALESCE(org_id, user_id), org_id IS NULL, name)
+    user_id,
+    org_id,
+    name
+FROM
+    todo_tag_names
+ORDER BY
+    COALESCE(org_id, user_id),
+    org_id IS NULL,
+    name,
+    created_at ASC;
+
+INSERT INTO
+    todo_tags (todo_id, tag_id)
+SELECT DISTINCT
+    t.id,
+    tg.id
+FROM
+    todos t
+    CROSS JOIN LATERAL jsonb_array_elements_text(t.metadata -> 'tags') AS tag (name)
+    JOIN tags tg ON tg.name=BTRIM(tag.name)
+    AND tg.org_id IS NOT DISTINCT FROM t.org_id
+    AND (
+        t.org_id IS NOT NULL
+        OR tg.user_id=t.user_id
+    )
+WHERE
+    jsonb_typeof(t.metadata -> 'tags')='array';
+
+UPDATE todos
+SET
+    metadata=metadata - 'tags'
+WHERE
+    metadata ? 'tags';
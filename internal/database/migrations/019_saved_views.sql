@@ -0,0 +1,36 @@
+-- Named todo list queries. A view stores the filters, sort and grouping of a
+-- todo list and is run for whoever opens it, so "assigned to me" means the
+-- viewer.
+CREATE TABLE saved_views (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    user_id TEXT NOT NULL,
+    org_id TEXT,
+    name TEXT NOT NULL,
+    filters JSONB NOT NULL DEFAULT '{}'::JSONB,
+    sort TEXT NOT NULL DEFAULT 'created_at',
+    sort_direction TEXT NOT NULL DEFAULT 'desc',
+    group_by TEXT NOT NULL DEFAULT 'none',
+    -- Shared views are listed for every member of the organization
+    shared BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE INDEX idx_saved_views_user_id ON saved_views(user_id);
+
+CREATE INDEX idx_saved_views_org_id ON saved_views(org_id)
+WHERE
+    org_id IS NOT NULL;
+
+CREATE TRIGGER set_updated_at_saved_views
+    BEFORE UPDATE ON saved_views
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_set_updated_at();
+
+-- Pins are per user, so members can each pin the shared views they use
+CREATE TABLE saved_view_pins (
+    view_id UUID NOT NULL REFERENCES saved_views ON DELETE CASCADE,
+    user_id TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (view_id, user_id)
+);
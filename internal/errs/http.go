@@ -7,6 +7,9 @@ import (
 type FieldError struct {
 	Field string `json:"field"`
 	Error string `json:"error"`
+	// Position is where in the field's value the error is, counting
+	// characters from 1, for values that are parsed such as filter queries
+	Position *int `json:"position,omitempty"`
 }
 
 type ActionType string
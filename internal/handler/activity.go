@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/uttam282005/tasker/internal/middleware"
+	"github.com/uttam282005/tasker/internal/model"
+	"github.com/uttam282005/tasker/internal/model/activity"
+	"github.com/uttam282005/tasker/internal/server"
+	"github.com/uttam282005/tasker/internal/service"
+)
+
+type ActivityHandler struct {
+	Handler
+	activityService *service.ActivityService
+}
+
+func NewActivityHandler(s *server.Server, activityService *service.ActivityService) *ActivityHandler {
+	return &ActivityHandler{
+		Handler:         NewHandler(s),
+		activityService: activityService,
+	}
+}
+
+func (h *ActivityHandler) GetTodoActivity(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, query *activity.GetTodoActivityQuery) (*model.PaginatedResponse[activity.Activity], error) {
+			userID := middleware.GetUserID(c)
+			return h.activityService.GetTodoActivity(c, userID, query)
+		},
+		http.StatusOK,
+		&activity.GetTodoActivityQuery{},
+	)(c)
+}
@@ -1,11 +1,15 @@
 package handler
 
 import (
+	"errors"
+	"io"
+	"net/http"
 	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/newrelic/go-agent/v3/integrations/nrpkgerrors"
 	"github.com/newrelic/go-agent/v3/newrelic"
+	"github.com/uttam282005/tasker/internal/lib/etag"
 	"github.com/uttam282005/tasker/internal/middleware"
 	"github.com/uttam282005/tasker/internal/server"
 	"github.com/uttam282005/tasker/internal/validation"
@@ -40,6 +44,10 @@ type JSONResponseHandler struct {
 }
 
 func (h JSONResponseHandler) Handle(c echo.Context, result interface{}) error {
+	// Single resources carry an ETag for conditional requests
+	if versioned, ok := result.(etag.Versioned); ok {
+		return etag.Render(c, h.status, versioned)
+	}
 	return c.JSON(h.status, result)
 }
 
@@ -68,19 +76,68 @@ func (h NoContentResponseHandler) AddAttributes(txn *newrelic.Transaction, resul
 	// http.status_code is already set by tracing middleware
 }
 
+// FileStream is a file written straight to the response instead of being
+// built in memory first. Name and ContentType, when set, replace the ones
+// the handler was registered with.
+type FileStream struct {
+	Name        string
+	ContentType string
+	Write       func(w io.Writer) error
+}
+
+// FileContent is what a file handler returns: the whole file or a stream.
+type FileContent interface {
+	[]byte | *FileStream
+}
+
 // FileResponseHandler handles file responses
 type FileResponseHandler struct {
-	status      int
-	filename    string
-	contentType string
+	status       int
+	filename     string
+	contentType  string
+	writeTimeout time.Duration
 }
 
 func (h FileResponseHandler) Handle(c echo.Context, result interface{}) error {
+	if stream, ok := result.(*FileStream); ok {
+		return h.stream(c, stream)
+	}
+
 	data := result.([]byte)
 	c.Response().Header().Set("Content-Disposition", "attachment; filename="+h.filename)
 	return c.Blob(h.status, h.contentType, data)
 }
 
+// stream holds the headers back until the first write, so a stream failing
+// before it wrote anything still gets a regular error response. Failures
+// after that can only cut the file short.
+func (h FileResponseHandler) stream(c echo.Context, stream *FileStream) error {
+	w := &fileStreamWriter{
+		c:            c,
+		controller:   http.NewResponseController(c.Response()),
+		writeTimeout: h.writeTimeout,
+		status:       h.status,
+		filename:     h.filename,
+		contentType:  h.contentType,
+	}
+	if stream.Name != "" {
+		w.filename = stream.Name
+	}
+	if stream.ContentType != "" {
+		w.contentType = stream.ContentType
+	}
+
+	if err := stream.Write(w); err != nil {
+		return err
+	}
+
+	// Nothing was written, the file is empty
+	if !c.Response().Committed {
+		w.writeHeader()
+	}
+	return nil
+}
+
 func (h FileResponseHandler) GetOperation() string {
 	return "handler_file"
 }
@@ -96,6 +153,56 @@ func (h FileResponseHandler) AddAttributes(txn *newrelic.Transaction, result int
 	}
 }
 
+// fileStreamWriter writes a FileStream to the response. The server's
+// WriteTimeout is an absolute deadline for the whole response, so each write
+// pushes it forward instead, the same way event streams do.
+type fileStreamWriter struct {
+	c            echo.Context
+	controller   *http.ResponseController
+	writeTimeout time.Duration
+	status       int
+	filename     string
+	contentType  string
+}
+
+func (w *fileStreamWriter) writeHeader() {
+	header := w.c.Response().Header()
+	header.Set(echo.HeaderContentType, w.contentType)
+	header.Set("Content-Disposition", "attachment; filename="+w.filename)
+	w.c.Response().WriteHeader(w.status)
+}
+
+func (w *fileStreamWriter) Write(p []byte) (int, error) {
+	if !w.c.Response().Committed {
+		w.writeHeader()
+	}
+
+	if w.writeTimeout > 0 {
+		err := w.controller.SetWriteDeadline(time.Now().Add(w.writeTimeout))
+		if err != nil && !errors.Is(err, http.ErrNotSupported) {
+			return 0, err
+		}
+	}
+
+	return w.c.Response().Write(p)
+}
+
+// StreamResponseHandler is for handlers that write the response body
+// themselves, such as event streams, so there is nothing left to send.
+type StreamResponseHandler struct{}
+
+func (h StreamResponseHandler) Handle(c echo.Context, result interface{}) error {
+	return nil
+}
+
+func (h StreamResponseHandler) GetOperation() string {
+	return "handler_stream"
+}
+
+func (h StreamResponseHandler) AddAttributes(txn *newrelic.Transaction, result interface{}) {
+	// http.status_code is already set by tracing middleware
+}
+
 // handleRequest is the unified handler function that eliminates code duplication
 func handleRequest[Req validation.Validatable](
 	c echo.Context,
@@ -220,9 +327,11 @@ func Handle[Req validation.Validatable, Res any](
 	}
 }
 
-func HandleFile[Req validation.Validatable](
+// HandleFile wraps a handler returning a file, either whole or as a
+// FileStream, with validation, error handling, logging, metrics, and tracing
+func HandleFile[Req validation.Validatable, Res FileContent](
 	h Handler,
-	handler HandlerFunc[Req, []byte],
+	handler HandlerFunc[Req, Res],
 	status int,
 	req Req,
 	filename string,
@@ -232,9 +341,10 @@ func HandleFile[Req validation.Validatable](
 		return handleRequest(c, req, func(c echo.Context, req Req) (interface{}, error) {
 			return handler(c, req)
 		}, FileResponseHandler{
-			status:      status,
-			filename:    filename,
-			contentType: contentType,
+			status:       status,
+			filename:     filename,
+			contentType:  contentType,
+			writeTimeout: time.Duration(h.server.Config.Server.WriteTimeout) * time.Second,
 		})
 	}
 }
@@ -253,3 +363,17 @@ func HandleNoContent[Req validation.Validatable](
 		}, NoContentResponseHandler{status: status})
 	}
 }
+
+// HandleStream wraps a handler that streams its own response with validation, error handling, logging, metrics, and tracing
+func HandleStream[Req validation.Validatable](
+	h Handler,
+	handler HandlerFuncNoContent[Req],
+	req Req,
+) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return handleRequest(c, req, func(c echo.Context, req Req) (interface{}, error) {
+			err := handler(c, req)
+			return nil, err
+		}, StreamResponseHandler{})
+	}
+}
@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/uttam282005/tasker/internal/lib/ical"
+	"github.com/uttam282005/tasker/internal/middleware"
+	"github.com/uttam282005/tasker/internal/model/calendar"
+	"github.com/uttam282005/tasker/internal/server"
+	"github.com/uttam282005/tasker/internal/service"
+)
+
+type CalendarHandler struct {
+	Handler
+	calendarService *service.CalendarService
+}
+
+func NewCalendarHandler(s *server.Server, calendarService *service.CalendarService) *CalendarHandler {
+	return &CalendarHandler{
+		Handler:         NewHandler(s),
+		calendarService: calendarService,
+	}
+}
+
+func (h *CalendarHandler) CreateFeed(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *calendar.CreateFeedPayload) (*calendar.CreatedFeed, error) {
+			userID := middleware.GetUserID(c)
+			return h.calendarService.CreateFeed(c, userID, payload)
+		},
+		http.StatusCreated,
+		&calendar.CreateFeedPayload{},
+	)(c)
+}
+
+func (h *CalendarHandler) GetFeeds(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *calendar.GetFeedsPayload) ([]calendar.Feed, error) {
+			userID := middleware.GetUserID(c)
+			return h.calendarService.GetFeeds(c, userID)
+		},
+		http.StatusOK,
+		&calendar.GetFeedsPayload{},
+	)(c)
+}
+
+func (h *CalendarHandler) UpdateFeed(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *calendar.UpdateFeedPayload) (*calendar.Feed, error) {
+			userID := middleware.GetUserID(c)
+			return h.calendarService.UpdateFeed(c, userID, payload)
+		},
+		http.StatusOK,
+		&calendar.UpdateFeedPayload{},
+	)(c)
+}
+
+func (h *CalendarHandler) DeleteFeed(c echo.Context) error {
+	return HandleNoContent(
+		h.Handler,
+		func(c echo.Context, payload *calendar.DeleteFeedPayload) error {
+			userID := middleware.GetUserID(c)
+			return h.calendarService.DeleteFeed(c, userID, payload.ID)
+		},
+		http.StatusNoContent,
+		&calendar.DeleteFeedPayload{},
+	)(c)
+}
+
+// GetFeed serves a feed to calendar clients, which authenticate with the
+// token in the URL alone.
+func (h *CalendarHandler) GetFeed(c echo.Context) error {
+	return HandleFile(
+		h.Handler,
+		func(c echo.Context, query *calendar.GetFeedQuery) (*FileStream, error) {
+			feed, err := h.calendarService.GetFeedByToken(c, query.Token)
+			if err != nil {
+				return nil, err
+			}
+			return &FileStream{
+				Write: func(w io.Writer) error {
+					return h.calendarService.WriteFeed(c, feed, w)
+				},
+			}, nil
+		},
+		http.StatusOK,
+		&calendar.GetFeedQuery{},
+		"todos.ics",
+		ical.MIMETextCalendar,
+	)(c)
+}
@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/uttam282005/tasker/internal/middleware"
+	"github.com/uttam282005/tasker/internal/model"
+	"github.com/uttam282005/tasker/internal/model/category"
+	"github.com/uttam282005/tasker/internal/server"
+	"github.com/uttam282005/tasker/internal/service"
+)
+
+type CategoryHandler struct {
+	Handler
+	categoryService *service.CategoryService
+}
+
+func NewCategoryHandler(s *server.Server, categoryService *service.CategoryService) *CategoryHandler {
+	return &CategoryHandler{
+		Handler:         NewHandler(s),
+		categoryService: categoryService,
+	}
+}
+
+func (h *CategoryHandler) CreateCategory(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *category.CreateCategoryPayload) (*category.Category, error) {
+			userID := middleware.GetUserID(c)
+			return h.categoryService.CreateCategory(c, userID, payload)
+		},
+		http.StatusCreated,
+		&category.CreateCategoryPayload{},
+	)(c)
+}
+
+func (h *CategoryHandler) GetCategories(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, query *category.GetCategoriesQuery) (*model.CursorPaginatedResponse[category.Category], error) {
+			userID := middleware.GetUserID(c)
+			return h.categoryService.GetCategories(c, userID, query)
+		},
+		http.StatusOK,
+		&category.GetCategoriesQuery{},
+	)(c)
+}
+
+func (h *CategoryHandler) GetCategoryByID(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *category.GetCategoryByIDPayload) (*category.Category, error) {
+			userID := middleware.GetUserID(c)
+			return h.categoryService.GetCategoryByID(c, userID, payload.ID)
+		},
+		http.StatusOK,
+		&category.GetCategoryByIDPayload{},
+	)(c)
+}
+
+func (h *CategoryHandler) UpdateCategory(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *category.UpdateCategoryPayload) (*category.Category, error) {
+			userID := middleware.GetUserID(c)
+			return h.categoryService.UpdateCategory(c, userID, payload.ID, payload)
+		},
+		http.StatusOK,
+		&category.UpdateCategoryPayload{},
+	)(c)
+}
+
+func (h *CategoryHandler) PatchCategory(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *category.PatchCategoryPayload) (*category.Category, error) {
+			userID := middleware.GetUserID(c)
+			return h.categoryService.UpdateCategory(c, userID, payload.ID, &payload.UpdateCategoryPayload)
+		},
+		http.StatusOK,
+		&category.PatchCategoryPayload{},
+	)(c)
+}
+
+func (h *CategoryHandler) DeleteCategory(c echo.Context) error {
+	return HandleNoContent(
+		h.Handler,
+		func(c echo.Context, payload *category.DeleteCategoryPayload) error {
+			userID := middleware.GetUserID(c)
+			return h.categoryService.DeleteCategory(c, userID, payload.ID, payload.IfMatch)
+		},
+		http.StatusNoContent,
+		&category.DeleteCategoryPayload{},
+	)(c)
+}
+
+func (h *CategoryHandler) GetTrashedCategories(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, query *category.GetTrashedCategoriesQuery) (*model.PaginatedResponse[category.Category], error) {
+			userID := middleware.GetUserID(c)
+			return h.categoryService.GetTrashedCategories(c, userID, query)
+		},
+		http.StatusOK,
+		&category.GetTrashedCategoriesQuery{},
+	)(c)
+}
+
+func (h *CategoryHandler) RestoreCategory(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *category.RestoreCategoryPayload) (*category.Category, error) {
+			userID := middleware.GetUserID(c)
+			return h.categoryService.RestoreCategory(c, userID, payload.ID)
+		},
+		http.StatusOK,
+		&category.RestoreCategoryPayload{},
+	)(c)
+}
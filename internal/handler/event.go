@@ -0,0 +1,179 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/uttam282005/tasker/internal/lib/pubsub"
+	"github.com/uttam282005/tasker/internal/middleware"
+	"github.com/uttam282005/tasker/internal/model/event"
+	"github.com/uttam282005/tasker/internal/server"
+	"github.com/uttam282005/tasker/internal/service"
+)
+
+// maxHeartbeatInterval bounds how long a stream stays silent, so proxies
+// with their own idle timeouts keep the connection open.
+const maxHeartbeatInterval = 30 * time.Second
+
+// eventStreamRetry is the reconnect delay, in milliseconds, suggested to clients.
+const eventStreamRetry = 3000
+
+type EventHandler struct {
+	Handler
+	eventService *service.EventService
+}
+
+func NewEventHandler(s *server.Server, eventService *service.EventService) *EventHandler {
+	return &EventHandler{
+		Handler:      NewHandler(s),
+		eventService: eventService,
+	}
+}
+
+func (h *EventHandler) StreamEvents(c echo.Context) error {
+	return HandleStream(
+		h.Handler,
+		func(c echo.Context, query *event.StreamEventsQuery) error {
+			userID := middleware.GetUserID(c)
+			logger := middleware.GetLogger(c)
+
+			lastEventID := c.Request().Header.Get("Last-Event-ID")
+			if lastEventID == "" && query.LastEventID != nil {
+				lastEventID = *query.LastEventID
+			}
+
+			sub, backlog, gap, err := h.eventService.Subscribe(c, userID, lastEventID)
+			if err != nil {
+				logger.Error().Err(err).Msg("failed to subscribe to events")
+				return err
+			}
+			defer sub.Close()
+
+			stream := newEventStream(c, time.Duration(h.server.Config.Server.WriteTimeout)*time.Second)
+			if err := stream.open(); err != nil {
+				return nil
+			}
+
+			if gap {
+				if err := stream.reset(); err != nil {
+					return nil
+				}
+			}
+
+			for _, message := range backlog {
+				if err := stream.send(message); err != nil {
+					return nil
+				}
+			}
+
+			heartbeat := time.NewTicker(stream.heartbeatInterval())
+			defer heartbeat.Stop()
+
+			for {
+				select {
+				case <-c.Request().Context().Done():
+					return nil
+				case <-h.server.Done():
+					return nil
+				case <-heartbeat.C:
+					if err := stream.heartbeat(); err != nil {
+						return nil
+					}
+				case message, ok := <-sub.Messages():
+					if !ok {
+						logger.Warn().Msg("event subscription closed")
+						return nil
+					}
+					if err := stream.send(message); err != nil {
+						return nil
+					}
+				}
+			}
+		},
+		&event.StreamEventsQuery{},
+	)(c)
+}
+
+// eventStream writes Server-Sent Events. The server's WriteTimeout is an
+// absolute deadline for the whole response, so each write pushes it forward
+// instead, and heartbeats come often enough that an idle stream never gets
+// near it.
+type eventStream struct {
+	c            echo.Context
+	controller   *http.ResponseController
+	writeTimeout time.Duration
+	lastID       string
+}
+
+func newEventStream(c echo.Context, writeTimeout time.Duration) *eventStream {
+	return &eventStream{
+		c:            c,
+		controller:   http.NewResponseController(c.Response()),
+		writeTimeout: writeTimeout,
+	}
+}
+
+func (s *eventStream) heartbeatInterval() time.Duration {
+	interval := s.writeTimeout / 2
+	if interval <= 0 || interval > maxHeartbeatInterval {
+		interval = maxHeartbeatInterval
+	}
+	return interval
+}
+
+func (s *eventStream) open() error {
+	header := s.c.Response().Header()
+	header.Set(echo.HeaderContentType, "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	// Stop nginx from buffering the stream
+	header.Set("X-Accel-Buffering", "no")
+	s.c.Response().WriteHeader(http.StatusOK)
+
+	return s.write(fmt.Sprintf("retry: %d\n\n", eventStreamRetry))
+}
+
+// send writes a message unless an earlier one already covered it, which
+// happens when a live message was published while the backlog was read.
+func (s *eventStream) send(message pubsub.Message) error {
+	if s.lastID != "" && pubsub.CompareIDs(message.ID, s.lastID) <= 0 {
+		return nil
+	}
+	if err := s.write(fmt.Sprintf("id: %s\ndata: %s\n\n", message.ID, message.Payload)); err != nil {
+		return err
+	}
+	s.lastID = message.ID
+	return nil
+}
+
+// reset clears the client's last event ID, so it won't try to resume from
+// the expired position again, and tells it to refetch.
+func (s *eventStream) reset() error {
+	data, err := json.Marshal(map[string]event.Type{"type": event.TypeReset})
+	if err != nil {
+		return err
+	}
+	return s.write(fmt.Sprintf("id\ndata: %s\n\n", data))
+}
+
+func (s *eventStream) heartbeat() error {
+	return s.write(": heartbeat\n\n")
+}
+
+func (s *eventStream) write(frame string) error {
+	if s.writeTimeout > 0 {
+		err := s.controller.SetWriteDeadline(time.Now().Add(s.writeTimeout))
+		if err != nil && !errors.Is(err, http.ErrNotSupported) {
+			return err
+		}
+	}
+
+	if _, err := s.c.Response().Write([]byte(frame)); err != nil {
+		return err
+	}
+	return s.controller.Flush()
+}
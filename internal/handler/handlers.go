@@ -6,13 +6,37 @@ import (
 )
 
 type Handlers struct {
-	Health  *HealthHandler
-	OpenAPI *OpenAPIHandler
+	Health   *HealthHandler
+	OpenAPI  *OpenAPIHandler
+	Todo     *TodoHandler
+	Category *CategoryHandler
+	Comment  *CommentHandler
+	Search   *SearchHandler
+	Activity *ActivityHandler
+	Share    *ShareHandler
+	Webhook  *WebhookHandler
+	Event    *EventHandler
+	Calendar *CalendarHandler
+	Import   *ImportHandler
+	Tag      *TagHandler
+	View     *ViewHandler
 }
 
 func NewHandlers(s *server.Server, services *service.Services) *Handlers {
 	return &Handlers{
-		Health:  NewHealthHandler(s),
-		OpenAPI: NewOpenAPIHandler(s),
+		Health:   NewHealthHandler(s),
+		OpenAPI:  NewOpenAPIHandler(s),
+		Todo:     NewTodoHandler(s, services.Todo),
+		Category: NewCategoryHandler(s, services.Category),
+		Comment:  NewCommentHandler(s, services.Comment),
+		Search:   NewSearchHandler(s, services.Search),
+		Activity: NewActivityHandler(s, services.Activity),
+		Share:    NewShareHandler(s, services.Share),
+		Webhook:  NewWebhookHandler(s, services.Webhook),
+		Event:    NewEventHandler(s, services.Event),
+		Calendar: NewCalendarHandler(s, services.Calendar),
+		Import:   NewImportHandler(s, services.Import),
+		Tag:      NewTagHandler(s, services.Tag),
+		View:     NewViewHandler(s, services.View),
 	}
 }
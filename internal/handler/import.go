@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/uttam282005/tasker/internal/errs"
+	"github.com/uttam282005/tasker/internal/middleware"
+	"github.com/uttam282005/tasker/internal/model/imports"
+	"github.com/uttam282005/tasker/internal/server"
+	"github.com/uttam282005/tasker/internal/service"
+)
+
+type ImportHandler struct {
+	Handler
+	importService *service.ImportService
+}
+
+func NewImportHandler(s *server.Server, importService *service.ImportService) *ImportHandler {
+	return &ImportHandler{
+		Handler:       NewHandler(s),
+		importService: importService,
+	}
+}
+
+// CreateImport accepts the file and answers 202 right away; the import runs
+// in the background and is polled with GetImportByID.
+func (h *ImportHandler) CreateImport(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *imports.CreateImportPayload) (*imports.Import, error) {
+			userID := middleware.GetUserID(c)
+
+			form, err := c.MultipartForm()
+			if err != nil {
+				return nil, errs.NewBadRequestError("multipart form not found", false, nil, nil, nil)
+			}
+
+			files := form.File["file"]
+			if len(files) == 0 {
+				return nil, errs.NewBadRequestError("no file found", false, nil, nil, nil)
+			}
+
+			if len(files) > 1 {
+				return nil, errs.NewBadRequestError("only one file allowed per import", false, nil, nil, nil)
+			}
+
+			return h.importService.CreateImport(c, userID, payload, files[0])
+		},
+		http.StatusAccepted,
+		&imports.CreateImportPayload{},
+	)(c)
+}
+
+func (h *ImportHandler) GetImportByID(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *imports.GetImportByIDPayload) (*imports.Import, error) {
+			userID := middleware.GetUserID(c)
+			return h.importService.GetImportByID(c, userID, payload.ID)
+		},
+		http.StatusOK,
+		&imports.GetImportByIDPayload{},
+	)(c)
+}
@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/uttam282005/tasker/internal/middleware"
+	"github.com/uttam282005/tasker/internal/model"
+	"github.com/uttam282005/tasker/internal/model/search"
+	"github.com/uttam282005/tasker/internal/server"
+	"github.com/uttam282005/tasker/internal/service"
+)
+
+type SearchHandler struct {
+	Handler
+	searchService *service.SearchService
+}
+
+func NewSearchHandler(s *server.Server, searchService *service.SearchService) *SearchHandler {
+	return &SearchHandler{
+		Handler:       NewHandler(s),
+		searchService: searchService,
+	}
+}
+
+func (h *SearchHandler) Search(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, query *search.SearchQuery) (*model.PaginatedResponse[search.Hit], error) {
+			userID := middleware.GetUserID(c)
+			return h.searchService.Search(c, userID, query)
+		},
+		http.StatusOK,
+		&search.SearchQuery{},
+	)(c)
+}
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/uttam282005/tasker/internal/middleware"
+	"github.com/uttam282005/tasker/internal/model/tag"
+	"github.com/uttam282005/tasker/internal/server"
+	"github.com/uttam282005/tasker/internal/service"
+)
+
+type TagHandler struct {
+	Handler
+	tagService *service.TagService
+}
+
+func NewTagHandler(s *server.Server, tagService *service.TagService) *TagHandler {
+	return &TagHandler{
+		Handler:    NewHandler(s),
+		tagService: tagService,
+	}
+}
+
+func (h *TagHandler) CreateTag(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *tag.CreateTagPayload) (*tag.Tag, error) {
+			userID := middleware.GetUserID(c)
+			return h.tagService.CreateTag(c, userID, payload)
+		},
+		http.StatusCreated,
+		&tag.CreateTagPayload{},
+	)(c)
+}
+
+func (h *TagHandler) GetTags(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, query *tag.GetTagsQuery) ([]tag.TagUsage, error) {
+			userID := middleware.GetUserID(c)
+			return h.tagService.GetTags(c, userID, query)
+		},
+		http.StatusOK,
+		&tag.GetTagsQuery{},
+	)(c)
+}
+
+func (h *TagHandler) GetTagByID(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *tag.GetTagByIDPayload) (*tag.TagUsage, error) {
+			userID := middleware.GetUserID(c)
+			return h.tagService.GetTagByID(c, userID, payload.ID)
+		},
+		http.StatusOK,
+		&tag.GetTagByIDPayload{},
+	)(c)
+}
+
+func (h *TagHandler) UpdateTag(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *tag.UpdateTagPayload) (*tag.Tag, error) {
+			userID := middleware.GetUserID(c)
+			return h.tagService.UpdateTag(c, userID, payload)
+		},
+		http.StatusOK,
+		&tag.UpdateTagPayload{},
+	)(c)
+}
+
+func (h *TagHandler) DeleteTag(c echo.Context) error {
+	return HandleNoContent(
+		h.Handler,
+		func(c echo.Context, payload *tag.DeleteTagPayload) error {
+			userID := middleware.GetUserID(c)
+			return h.tagService.DeleteTag(c, userID, payload.ID)
+		},
+		http.StatusNoContent,
+		&tag.DeleteTagPayload{},
+	)(c)
+}
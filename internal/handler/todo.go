@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"io"
 	"net/http"
 
 	"github.com/labstack/echo/v4"
@@ -51,7 +52,7 @@ func (h *TodoHandler) GetTodoByID(c echo.Context) error {
 func (h *TodoHandler) GetTodos(c echo.Context) error {
 	return Handle(
 		h.Handler,
-		func(c echo.Context, query *todo.GetTodosQuery) (*model.PaginatedResponse[todo.PopulatedTodo], error) {
+		func(c echo.Context, query *todo.GetTodosQuery) (*model.CursorPaginatedResponse[todo.PopulatedTodo], error) {
 			userID := middleware.GetUserID(c)
 			return h.todoService.GetTodos(c, userID, query)
 		},
@@ -60,6 +61,26 @@ func (h *TodoHandler) GetTodos(c echo.Context) error {
 	)(c)
 }
 
+func (h *TodoHandler) ExportTodos(c echo.Context) error {
+	return HandleFile(
+		h.Handler,
+		func(c echo.Context, query *todo.ExportTodosQuery) (*FileStream, error) {
+			userID := middleware.GetUserID(c)
+			return &FileStream{
+				Name:        "todos." + string(query.Format),
+				ContentType: query.Format.ContentType(),
+				Write: func(w io.Writer) error {
+					return h.todoService.ExportTodos(c, userID, query, w)
+				},
+			}, nil
+		},
+		http.StatusOK,
+		&todo.ExportTodosQuery{},
+		"todos.json",
+		echo.MIMEApplicationJSON,
+	)(c)
+}
+
 func (h *TodoHandler) UpdateTodo(c echo.Context) error {
 	return Handle(
 		h.Handler,
@@ -72,18 +93,82 @@ func (h *TodoHandler) UpdateTodo(c echo.Context) error {
 	)(c)
 }
 
+func (h *TodoHandler) PatchTodo(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *todo.PatchTodoPayload) (*todo.Todo, error) {
+			userID := middleware.GetUserID(c)
+			return h.todoService.UpdateTodo(c, userID, &payload.UpdateTodoPayload)
+		},
+		http.StatusOK,
+		&todo.PatchTodoPayload{},
+	)(c)
+}
+
+func (h *TodoHandler) BulkUpdateTodos(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *todo.BulkTodosPayload) (*todo.BulkResponse, error) {
+			if payload.Action == todo.BulkActionDelete && !middleware.HasPermission(c, middleware.PermissionTodosDelete) {
+				return nil, errs.NewForbiddenError("Missing organization permission "+middleware.PermissionTodosDelete, false)
+			}
+
+			userID := middleware.GetUserID(c)
+			return h.todoService.BulkUpdateTodos(c, userID, payload)
+		},
+		http.StatusOK,
+		&todo.BulkTodosPayload{},
+	)(c)
+}
+
+func (h *TodoHandler) MoveTodo(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *todo.MoveTodoPayload) (*todo.Todo, error) {
+			userID := middleware.GetUserID(c)
+			return h.todoService.MoveTodo(c, userID, payload)
+		},
+		http.StatusOK,
+		&todo.MoveTodoPayload{},
+	)(c)
+}
+
 func (h *TodoHandler) DeleteTodo(c echo.Context) error {
 	return HandleNoContent(
 		h.Handler,
 		func(c echo.Context, payload *todo.DeleteTodoPayload) error {
 			userID := middleware.GetUserID(c)
-			return h.todoService.DeleteTodo(c, userID, payload.ID)
+			return h.todoService.DeleteTodo(c, userID, payload.ID, payload.IfMatch)
 		},
 		http.StatusNoContent,
 		&todo.DeleteTodoPayload{},
 	)(c)
 }
 
+func (h *TodoHandler) GetTrashedTodos(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, query *todo.GetTrashedTodosQuery) (*model.PaginatedResponse[todo.Todo], error) {
+			userID := middleware.GetUserID(c)
+			return h.todoService.GetTrashedTodos(c, userID, query)
+		},
+		http.StatusOK,
+		&todo.GetTrashedTodosQuery{},
+	)(c)
+}
+
+func (h *TodoHandler) RestoreTodo(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *todo.RestoreTodoPayload) (*todo.Todo, error) {
+			userID := middleware.GetUserID(c)
+			return h.todoService.RestoreTodo(c, userID, payload.ID)
+		},
+		http.StatusOK,
+		&todo.RestoreTodoPayload{},
+	)(c)
+}
+
 func (h *TodoHandler) GetTodoStats(c echo.Context) error {
 	return Handle(
 		h.Handler,
@@ -155,3 +240,51 @@ func (h *TodoHandler) GetAttachmentPresignedURL(c echo.Context) error {
 		&todo.GetAttachmentPresignedURLPayload{},
 	)(c)
 }
+
+func (h *TodoHandler) AddTodoBlocker(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *todo.AddTodoBlockerPayload) (*todo.TodoDependency, error) {
+			userID := middleware.GetUserID(c)
+			return h.todoService.AddTodoBlocker(c, userID, payload)
+		},
+		http.StatusCreated,
+		&todo.AddTodoBlockerPayload{},
+	)(c)
+}
+
+func (h *TodoHandler) RemoveTodoBlocker(c echo.Context) error {
+	return HandleNoContent(
+		h.Handler,
+		func(c echo.Context, payload *todo.RemoveTodoBlockerPayload) error {
+			userID := middleware.GetUserID(c)
+			return h.todoService.RemoveTodoBlocker(c, userID, payload)
+		},
+		http.StatusNoContent,
+		&todo.RemoveTodoBlockerPayload{},
+	)(c)
+}
+
+func (h *TodoHandler) AddTodoWatcher(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *todo.AddTodoWatcherPayload) (*todo.TodoWatcher, error) {
+			userID := middleware.GetUserID(c)
+			return h.todoService.AddTodoWatcher(c, userID, payload)
+		},
+		http.StatusCreated,
+		&todo.AddTodoWatcherPayload{},
+	)(c)
+}
+
+func (h *TodoHandler) RemoveTodoWatcher(c echo.Context) error {
+	return HandleNoContent(
+		h.Handler,
+		func(c echo.Context, payload *todo.RemoveTodoWatcherPayload) error {
+			userID := middleware.GetUserID(c)
+			return h.todoService.RemoveTodoWatcher(c, userID, payload)
+		},
+		http.StatusNoContent,
+		&todo.RemoveTodoWatcherPayload{},
+	)(c)
+}
@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/uttam282005/tasker/internal/middleware"
+	"github.com/uttam282005/tasker/internal/model"
+	"github.com/uttam282005/tasker/internal/model/todo"
+	"github.com/uttam282005/tasker/internal/model/view"
+	"github.com/uttam282005/tasker/internal/server"
+	"github.com/uttam282005/tasker/internal/service"
+)
+
+type ViewHandler struct {
+	Handler
+	viewService *service.ViewService
+}
+
+func NewViewHandler(s *server.Server, viewService *service.ViewService) *ViewHandler {
+	return &ViewHandler{
+		Handler:     NewHandler(s),
+		viewService: viewService,
+	}
+}
+
+func (h *ViewHandler) CreateView(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *view.CreateViewPayload) (*view.View, error) {
+			userID := middleware.GetUserID(c)
+			return h.viewService.CreateView(c, userID, payload)
+		},
+		http.StatusCreated,
+		&view.CreateViewPayload{},
+	)(c)
+}
+
+func (h *ViewHandler) GetViews(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *view.GetViewsPayload) ([]view.View, error) {
+			userID := middleware.GetUserID(c)
+			return h.viewService.GetViews(c, userID)
+		},
+		http.StatusOK,
+		&view.GetViewsPayload{},
+	)(c)
+}
+
+func (h *ViewHandler) GetSystemViews(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *view.GetSystemViewsPayload) ([]view.SystemView, error) {
+			return h.viewService.GetSystemViews(c), nil
+		},
+		http.StatusOK,
+		&view.GetSystemViewsPayload{},
+	)(c)
+}
+
+func (h *ViewHandler) GetViewByID(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *view.GetViewByIDPayload) (*view.View, error) {
+			userID := middleware.GetUserID(c)
+			return h.viewService.GetViewByID(c, userID, payload.ID)
+		},
+		http.StatusOK,
+		&view.GetViewByIDPayload{},
+	)(c)
+}
+
+func (h *ViewHandler) UpdateView(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *view.UpdateViewPayload) (*view.View, error) {
+			userID := middleware.GetUserID(c)
+			return h.viewService.UpdateView(c, userID, payload)
+		},
+		http.StatusOK,
+		&view.UpdateViewPayload{},
+	)(c)
+}
+
+func (h *ViewHandler) DeleteView(c echo.Context) error {
+	return HandleNoContent(
+		h.Handler,
+		func(c echo.Context, payload *view.DeleteViewPayload) error {
+			userID := middleware.GetUserID(c)
+			return h.viewService.DeleteView(c, userID, payload.ID)
+		},
+		http.StatusNoContent,
+		&view.DeleteViewPayload{},
+	)(c)
+}
+
+func (h *ViewHandler) PinView(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *view.PinViewPayload) (*view.View, error) {
+			userID := middleware.GetUserID(c)
+			return h.viewService.PinView(c, userID, payload.ID)
+		},
+		http.StatusOK,
+		&view.PinViewPayload{},
+	)(c)
+}
+
+func (h *ViewHandler) UnpinView(c echo.Context) error {
+	return HandleNoContent(
+		h.Handler,
+		func(c echo.Context, payload *view.UnpinViewPayload) error {
+			userID := middleware.GetUserID(c)
+			return h.viewService.UnpinView(c, userID, payload.ID)
+		},
+		http.StatusNoContent,
+		&view.UnpinViewPayload{},
+	)(c)
+}
+
+func (h *ViewHandler) GetViewTodos(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, query *view.GetViewTodosQuery) (*model.CursorPaginatedResponse[todo.PopulatedTodo], error) {
+			userID := middleware.GetUserID(c)
+			return h.viewService.GetViewTodos(c, userID, query)
+		},
+		http.StatusOK,
+		&view.GetViewTodosQuery{},
+	)(c)
+}
+
+func (h *ViewHandler) GetSystemViewTodos(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, query *view.GetSystemViewTodosQuery) (*model.CursorPaginatedResponse[todo.PopulatedTodo], error) {
+			userID := middleware.GetUserID(c)
+			return h.viewService.GetSystemViewTodos(c, userID, query)
+		},
+		http.StatusOK,
+		&view.GetSystemViewTodosQuery{},
+	)(c)
+}
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/uttam282005/tasker/internal/middleware"
+	"github.com/uttam282005/tasker/internal/model"
+	"github.com/uttam282005/tasker/internal/model/webhook"
+	"github.com/uttam282005/tasker/internal/server"
+	"github.com/uttam282005/tasker/internal/service"
+)
+
+type WebhookHandler struct {
+	Handler
+	webhookService *service.WebhookService
+}
+
+func NewWebhookHandler(s *server.Server, webhookService *service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		Handler:        NewHandler(s),
+		webhookService: webhookService,
+	}
+}
+
+func (h *WebhookHandler) CreateWebhook(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *webhook.CreateWebhookPayload) (*webhook.CreatedWebhook, error) {
+			userID := middleware.GetUserID(c)
+			return h.webhookService.CreateWebhook(c, userID, payload)
+		},
+		http.StatusCreated,
+		&webhook.CreateWebhookPayload{},
+	)(c)
+}
+
+func (h *WebhookHandler) GetWebhooks(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *webhook.GetWebhooksPayload) ([]webhook.Webhook, error) {
+			userID := middleware.GetUserID(c)
+			return h.webhookService.GetWebhooks(c, userID)
+		},
+		http.StatusOK,
+		&webhook.GetWebhooksPayload{},
+	)(c)
+}
+
+func (h *WebhookHandler) GetWebhookByID(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *webhook.GetWebhookByIDPayload) (*webhook.Webhook, error) {
+			userID := middleware.GetUserID(c)
+			return h.webhookService.GetWebhookByID(c, userID, payload.ID)
+		},
+		http.StatusOK,
+		&webhook.GetWebhookByIDPayload{},
+	)(c)
+}
+
+func (h *WebhookHandler) UpdateWebhook(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *webhook.UpdateWebhookPayload) (*webhook.Webhook, error) {
+			userID := middleware.GetUserID(c)
+			return h.webhookService.UpdateWebhook(c, userID, payload)
+		},
+		http.StatusOK,
+		&webhook.UpdateWebhookPayload{},
+	)(c)
+}
+
+func (h *WebhookHandler) DeleteWebhook(c echo.Context) error {
+	return HandleNoContent(
+		h.Handler,
+		func(c echo.Context, payload *webhook.DeleteWebhookPayload) error {
+			userID := middleware.GetUserID(c)
+			return h.webhookService.DeleteWebhook(c, userID, payload.ID)
+		},
+		http.StatusNoContent,
+		&webhook.DeleteWebhookPayload{},
+	)(c)
+}
+
+func (h *WebhookHandler) GetDeliveries(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, query *webhook.GetDeliveriesQuery) (*model.PaginatedResponse[webhook.Delivery], error) {
+			userID := middleware.GetUserID(c)
+			return h.webhookService.GetDeliveries(c, userID, query)
+		},
+		http.StatusOK,
+		&webhook.GetDeliveriesQuery{},
+	)(c)
+}
+
+func (h *WebhookHandler) Redeliver(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *webhook.RedeliverPayload) (*webhook.Delivery, error) {
+			userID := middleware.GetUserID(c)
+			return h.webhookService.Redeliver(c, userID, payload)
+		},
+		http.StatusAccepted,
+		&webhook.RedeliverPayload{},
+	)(c)
+}
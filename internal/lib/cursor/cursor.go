@@ -0,0 +1,62 @@
+// Package cursor encodes the opaque cursors used for keyset pagination.
+//
+// A cursor marks a position in a sorted list by the sort key and id of the
+// row next to it, so a page starts exactly where the previous one ended even
+// when rows are added or removed in between.
+package cursor
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+type Direction string
+
+const (
+	// DirectionNext reads the rows after the position
+	DirectionNext Direction = "next"
+	// DirectionPrev reads the rows before the position
+	DirectionPrev Direction = "prev"
+)
+
+// ErrInvalid is returned for cursors that weren't produced by Encode.
+var ErrInvalid = errors.New("invalid cursor")
+
+type Cursor struct {
+	// Sort and Order are the sort the cursor was issued for; it means
+	// nothing under any other
+	Sort  string `json:"s"`
+	Order string `json:"o"`
+	// Value is the row's sort key, formatted as Postgres reads it back
+	Value     string    `json:"v"`
+	ID        uuid.UUID `json:"i"`
+	Direction Direction `json:"d"`
+}
+
+// Encode returns the cursor as an opaque, URL safe string.
+func (c Cursor) Encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// Decode parses a cursor produced by Encode.
+func Decode(encoded string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalid
+	}
+
+	var c Cursor
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return nil, ErrInvalid
+	}
+
+	if c.Direction != DirectionNext && c.Direction != DirectionPrev {
+		return nil, ErrInvalid
+	}
+
+	return &c, nil
+}
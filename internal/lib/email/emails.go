@@ -76,3 +76,25 @@ func (c *Client) SendWeeklyReportEmail(to string, weekStart, weekEnd time.Time,
 		data,
 	)
 }
+
+func (c *Client) SendTodoWatcherEmail(to, todoTitle string, todoID uuid.UUID, event string, fields []string) error {
+	subject := fmt.Sprintf("'%s' was %s", todoTitle, event)
+	if event == "commented" {
+		subject = fmt.Sprintf("New comment on '%s'", todoTitle)
+	}
+
+	data := map[string]interface{}{
+		"TodoTitle": todoTitle,
+		"TodoID":    todoID.String(),
+		"Event":     event,
+		"Fields":    fields,
+		"HasFields": len(fields) > 0,
+	}
+
+	return c.SendEmail(
+		to,
+		subject,
+		TemplateTodoWatcher,
+		data,
+	)
+}
@@ -7,4 +7,5 @@ const (
 	TemplateDueDateReminder     Template = "due-date-reminder"
 	TemplateOverdueNotification Template = "overdue-notification"
 	TemplateWeeklyReport        Template = "weekly-report"
+	TemplateTodoWatcher         Template = "todo-watcher"
 )
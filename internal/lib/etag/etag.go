@@ -0,0 +1,137 @@
+// Package etag implements the entity tags used for conditional requests.
+//
+// A tag looks like "<version>-<digest>". The version is the row's version
+// column and is all If-Match compares, so an edit is only refused when the
+// resource itself was written in the meantime. The digest covers the whole
+// representation and is what If-None-Match compares, so a populated todo's
+// tag also changes when its subtasks, comments or attachments do.
+package etag
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	HeaderETag        = "ETag"
+	HeaderIfMatch     = "If-Match"
+	HeaderIfNoneMatch = "If-None-Match"
+)
+
+// Versioned is implemented by resources that carry a row version.
+type Versioned interface {
+	ETagVersion() int
+}
+
+// New returns the tag for a representation of a resource at version.
+func New(version int, body []byte) string {
+	digest := sha256.Sum256(body)
+	return fmt.Sprintf(`"%d-%s"`, version, hex.EncodeToString(digest[:8]))
+}
+
+// MatchesVersion reports whether an If-Match header accepts the resource at
+// version. A missing header, or "*", accepts any version. Weak tags never
+// match, as If-Match requires a strong comparison.
+func MatchesVersion(ifMatch *string, version int) bool {
+	if ifMatch == nil {
+		return true
+	}
+
+	for _, tag := range splitList(*ifMatch) {
+		if tag == "*" {
+			return true
+		}
+		if strings.HasPrefix(tag, "W/") {
+			continue
+		}
+
+		value := strings.Trim(tag, `"`)
+		versionPart, _, _ := strings.Cut(value, "-")
+		if v, err := strconv.Atoi(versionPart); err == nil && v == version {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Matches reports whether an If-None-Match header lists tag, using the weak
+// comparison that header calls for.
+func Matches(ifNoneMatch, tag string) bool {
+	for _, candidate := range splitList(ifNoneMatch) {
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == tag {
+			return true
+		}
+	}
+	return false
+}
+
+func splitList(header string) []string {
+	var tags []string
+	for _, tag := range strings.Split(header, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// PreconditionFailedError is returned when an If-Match no longer matches.
+// It is answered with 412 and the current representation, so the client can
+// reconcile without another round trip.
+type PreconditionFailedError struct {
+	Current Versioned
+}
+
+func (e *PreconditionFailedError) Error() string {
+	return fmt.Sprintf("precondition failed: resource is at version %d", e.Current.ETagVersion())
+}
+
+// Check returns a PreconditionFailedError carrying current when ifMatch does
+// not accept its version.
+func Check(ifMatch *string, current Versioned) error {
+	if MatchesVersion(ifMatch, current.ETagVersion()) {
+		return nil
+	}
+	return &PreconditionFailedError{Current: current}
+}
+
+// ExpectedVersion returns the version a write must still find for an If-Match
+// that Check accepted against current, or nil when the header accepts any
+// version. Repositories add it to their UPDATE, so a writer that slipped in
+// after the check makes the write fail instead of being overwritten.
+func ExpectedVersion(ifMatch *string, current Versioned) *int {
+	if ifMatch == nil || slices.Contains(splitList(*ifMatch), "*") {
+		return nil
+	}
+
+	version := current.ETagVersion()
+	return &version
+}
+
+// Render writes v as JSON with its ETag. A GET whose If-None-Match already
+// lists the tag gets 304 Not Modified instead of the body.
+func Render(c echo.Context, status int, v Versioned) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	tag := New(v.ETagVersion(), body)
+	c.Response().Header().Set(HeaderETag, tag)
+
+	if c.Request().Method == http.MethodGet && status == http.StatusOK &&
+		Matches(c.Request().Header.Get(HeaderIfNoneMatch), tag) {
+		return c.NoContent(http.StatusNotModified)
+	}
+
+	return c.JSONBlob(status, body)
+}
@@ -0,0 +1,585 @@
+// Package filterql implements the todo filter language, queries such as
+//
+//	priority:high OR (category:work AND due<7d) -tag:someday
+//
+// Terms side by side must all match, as if joined by AND. AND, OR and NOT
+// are written in capitals, AND binds tighter than OR, parentheses group and
+// a leading - negates the term or group it's in front of. A term is a field,
+// an operator and a value, or bare text searched for in titles and
+// descriptions; quoted text is searched for as a phrase.
+//
+// Fields are status, priority, due, created, completed, category, tag and
+// text. Every field takes :, = and !=, while priority and the dates also
+// take <, <=, > and >=, priorities ranking low < medium < high. A date is
+// YYYY-MM-DD, an RFC 3339 time, today, tomorrow, yesterday, now, or an
+// offset from today such as 3d, -2w or 1m (days, weeks and months) or from
+// now such as 12h. Dates other than times and hour offsets stand for the
+// whole day, so due:today is any time today and due<7d is before the start
+// of the seventh day from today. Category and tag names match ignoring
+// case, so tag:Someday finds a tag named someday; a category can also be
+// given by ID. The dates, category and tag also take none and any, for
+// having no value or some value.
+package filterql
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxDepth bounds how deeply groups and negations nest.
+const MaxDepth = 32
+
+// Error is a query that doesn't parse, with where it goes wrong.
+type Error struct {
+	// Pos is the position of the offending character, counting characters
+	// from 1
+	Pos     int
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("position %d: %s", e.Pos, e.Message)
+}
+
+type Op string
+
+const (
+	OpEqual          Op = "="
+	OpNotEqual       Op = "!="
+	OpLess           Op = "<"
+	OpLessOrEqual    Op = "<="
+	OpGreater        Op = ">"
+	OpGreaterOrEqual Op = ">="
+)
+
+// Node is a parsed query: an And, Or, Not or Term.
+type Node interface {
+	node()
+}
+
+type And struct {
+	Left, Right Node
+}
+
+type Or struct {
+	Left, Right Node
+}
+
+type Not struct {
+	Operand Node
+}
+
+// Term compares a field to a value. Bare text is a term of the text field.
+type Term struct {
+	Pos   int
+	Field string
+	Op    Op
+	// Value is the value as written, without quotes
+	Value  string
+	Quoted bool
+
+	// date is set for date fields compared to a date rather than to none or
+	// any
+	date *date
+	// categoryID is set for a category compared by ID rather than by name
+	categoryID *uuid.UUID
+}
+
+func (And) node()  {}
+func (Or) node()   {}
+func (Not) node()  {}
+func (Term) node() {}
+
+type fieldKind int
+
+const (
+	kindStatus fieldKind = iota
+	kindPriority
+	kindDate
+	kindCategory
+	kindTag
+	kindText
+)
+
+var fields = map[string]fieldKind{
+	"status":    kindStatus,
+	"priority":  kindPriority,
+	"due":       kindDate,
+	"created":   kindDate,
+	"completed": kindDate,
+	"category":  kindCategory,
+	"tag":       kindTag,
+	"text":      kindText,
+}
+
+var (
+	statuses   = []string{"draft", "active", "completed", "archived"}
+	priorities = []string{"low", "medium", "high"}
+)
+
+// Parse parses a query. The error, if any, is an *Error.
+func Parse(input string) (Node, error) {
+	tokens, err := lex([]rune(input))
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	if p.peek().kind == tokenEOF {
+		return nil, &Error{Pos: 1, Message: "query is empty"}
+	}
+
+	node, err := p.parseOr(0)
+	if err != nil {
+		return nil, err
+	}
+
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, &Error{Pos: t.pos, Message: "unexpected " + t.describe()}
+	}
+
+	return node, nil
+}
+
+// ------------------------------------------------------------
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenLParen
+	tokenRParen
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenTerm
+)
+
+type token struct {
+	kind tokenKind
+	pos  int
+	term *Term
+}
+
+func (t token) describe() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of query"
+	case tokenLParen:
+		return `"("`
+	case tokenRParen:
+		return `")"`
+	case tokenAnd:
+		return "AND"
+	case tokenOr:
+		return "OR"
+	case tokenNot:
+		return "NOT"
+	}
+	return "term"
+}
+
+func isSpace(r rune) bool {
+	return r == ' ' || r == '\t' || r == '\n' || r == '\r'
+}
+
+// isWordEnd tells whether r ends a field name or bare text.
+func isWordEnd(r rune) bool {
+	return isSpace(r) || r == '(' || r == ')' || r == '"' || r == ':' || r == '=' || r == '<' || r == '>'
+}
+
+func lex(input []rune) ([]token, error) {
+	var tokens []token
+	i := 0
+	for i < len(input) {
+		r := input[i]
+		pos := i + 1
+
+		switch {
+		case isSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenLParen, pos: pos})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenRParen, pos: pos})
+			i++
+		case r == '-' && i+1 < len(input) && !isSpace(input[i+1]) && input[i+1] != ')':
+			tokens = append(tokens, token{kind: tokenNot, pos: pos})
+			i++
+		case r == '"':
+			value, next, err := lexQuoted(input, i)
+			if err != nil {
+				return nil, err
+			}
+			term, err := newTerm(pos, "text", OpEqual, value, true, pos)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenTerm, pos: pos, term: term})
+			i = next
+		default:
+			start := i
+			for i < len(input) && !isWordEnd(input[i]) && !(input[i] == '!' && i+1 < len(input) && input[i+1] == '=') {
+				i++
+			}
+			word := string(input[start:i])
+
+			if i < len(input) && isOpStart(input, i) {
+				term, next, err := lexTerm(input, start, word, i)
+				if err != nil {
+					return nil, err
+				}
+				tokens = append(tokens, token{kind: tokenTerm, pos: pos, term: term})
+				i = next
+				continue
+			}
+
+			switch word {
+			case "AND":
+				tokens = append(tokens, token{kind: tokenAnd, pos: pos})
+			case "OR":
+				tokens = append(tokens, token{kind: tokenOr, pos: pos})
+			case "NOT":
+				tokens = append(tokens, token{kind: tokenNot, pos: pos})
+			default:
+				term, err := newTerm(pos, "text", OpEqual, word, false, pos)
+				if err != nil {
+					return nil, err
+				}
+				tokens = append(tokens, token{kind: tokenTerm, pos: pos, term: term})
+			}
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, pos: len(input) + 1}), nil
+}
+
+func isOpStart(input []rune, i int) bool {
+	switch input[i] {
+	case ':', '=', '<', '>':
+		return true
+	case '!':
+		return i+1 < len(input) && input[i+1] == '='
+	}
+	return false
+}
+
+// lexTerm reads the operator and value of a field term whose field name
+// runs from start up to i.
+func lexTerm(input []rune, start int, word string, i int) (*Term, int, error) {
+	if word == "" {
+		return nil, 0, &Error{Pos: i + 1, Message: fmt.Sprintf("missing field before %q", string(input[i]))}
+	}
+
+	field := strings.ToLower(word)
+	if _, ok := fields[field]; !ok {
+		return nil, 0, &Error{
+			Pos:     start + 1,
+			Message: fmt.Sprintf("unknown field %q, expected one of: status, priority, due, created, completed, category, tag, text", word),
+		}
+	}
+
+	opPos := i + 1
+	var op Op
+	switch {
+	case input[i] == ':' || input[i] == '=':
+		op = OpEqual
+		i++
+	case input[i] == '!':
+		op = OpNotEqual
+		i += 2
+	case i+1 < len(input) && input[i+1] == '=':
+		op = Op(string(input[i : i+2]))
+		i += 2
+	default:
+		op = Op(string(input[i]))
+		i++
+	}
+
+	valuePos := i + 1
+	if i < len(input) && input[i] == '"' {
+		value, next, err := lexQuoted(input, i)
+		if err != nil {
+			return nil, 0, err
+		}
+		term, err := newTerm(start+1, field, op, value, true, valuePos)
+		if err != nil {
+			return nil, 0, err
+		}
+		return term, next, nil
+	}
+
+	valueStart := i
+	for i < len(input) && !isSpace(input[i]) && input[i] != '(' && input[i] != ')' {
+		i++
+	}
+	if i == valueStart {
+		return nil, 0, &Error{Pos: valuePos, Message: fmt.Sprintf("missing value after %s%s", word, string(input[opPos-1:valueStart]))}
+	}
+
+	term, err := newTerm(start+1, field, op, string(input[valueStart:i]), false, valuePos)
+	if err != nil {
+		return nil, 0, err
+	}
+	return term, i, nil
+}
+
+// lexQuoted reads the quoted string starting at i, where a backslash
+// escapes the character after it, and returns it with the index after it.
+func lexQuoted(input []rune, i int) (string, int, error) {
+	start := i
+	var b strings.Builder
+	for i++; i < len(input); i++ {
+		switch input[i] {
+		case '\\':
+			if i+1 < len(input) {
+				i++
+				b.WriteRune(input[i])
+			}
+		case '"':
+			return b.String(), i + 1, nil
+		default:
+			b.WriteRune(input[i])
+		}
+	}
+	return "", 0, &Error{Pos: start + 1, Message: "unterminated quote"}
+}
+
+// newTerm checks the operator and value make sense for the field. pos is
+// where the term starts and valuePos where its value does.
+func newTerm(pos int, field string, op Op, value string, quoted bool, valuePos int) (*Term, error) {
+	term := &Term{Pos: pos, Field: field, Op: op, Value: value, Quoted: quoted}
+	kind := fields[field]
+
+	ordered := kind == kindPriority || kind == kindDate
+	if !ordered && op != OpEqual && op != OpNotEqual {
+		return nil, &Error{Pos: valuePos - len(op), Message: fmt.Sprintf("%s can only be compared with :, = or !=", field)}
+	}
+
+	if strings.TrimSpace(value) == "" {
+		return nil, &Error{Pos: valuePos, Message: fmt.Sprintf("%s needs a value", field)}
+	}
+
+	presence := !quoted && (value == "none" || value == "any")
+
+	switch kind {
+	case kindStatus:
+		if !contains(statuses, strings.ToLower(value)) {
+			return nil, &Error{Pos: valuePos, Message: fmt.Sprintf("unknown status %q, expected one of: %s", value, strings.Join(statuses, ", "))}
+		}
+		term.Value = strings.ToLower(value)
+	case kindPriority:
+		if !contains(priorities, strings.ToLower(value)) {
+			return nil, &Error{Pos: valuePos, Message: fmt.Sprintf("unknown priority %q, expected one of: %s", value, strings.Join(priorities, ", "))}
+		}
+		term.Value = strings.ToLower(value)
+	case kindDate:
+		if presence {
+			if op != OpEqual && op != OpNotEqual {
+				return nil, &Error{Pos: valuePos, Message: fmt.Sprintf("%s can only be compared with :, = or != to %s", field, value)}
+			}
+			break
+		}
+		d, ok := parseDate(value)
+		if !ok {
+			return nil, &Error{
+				Pos:     valuePos,
+				Message: fmt.Sprintf("%q isn't a date, expected YYYY-MM-DD, a time, today, tomorrow, yesterday, now, an offset such as 3d, -2w, 1m or 12h, none or any", value),
+			}
+		}
+		term.date = d
+	case kindCategory:
+		if !presence {
+			if id, err := uuid.Parse(value); err == nil {
+				term.categoryID = &id
+			}
+		}
+	}
+
+	return term, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// ------------------------------------------------------------
+
+// date is a date value. Instants are a moment, anything else a whole day.
+type date struct {
+	// absolute is an RFC 3339 time, or midnight UTC of a YYYY-MM-DD day
+	absolute *time.Time
+	day      bool
+	// offset counts units from now, for hours, or from today otherwise
+	offset int
+	unit   byte
+}
+
+var offsetPattern = regexp.MustCompile(`^([+-]?)(\d{1,4})([hdwm])$`)
+
+func parseDate(value string) (*date, bool) {
+	switch strings.ToLower(value) {
+	case "today":
+		return &date{unit: 'd'}, true
+	case "tomorrow":
+		return &date{offset: 1, unit: 'd'}, true
+	case "yesterday":
+		return &date{offset: -1, unit: 'd'}, true
+	case "now":
+		return &date{unit: 'h'}, true
+	}
+
+	if m := offsetPattern.FindStringSubmatch(strings.ToLower(value)); m != nil {
+		n, _ := strconv.Atoi(m[2])
+		if m[1] == "-" {
+			n = -n
+		}
+		return &date{offset: n, unit: m[3][0]}, true
+	}
+
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return &date{absolute: &t, day: true}, true
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &date{absolute: &t}, true
+	}
+
+	return nil, false
+}
+
+// bounds returns the start and end of the date as of now, the end being
+// the first moment after it. Days are counted in now's location.
+func (d *date) bounds(now time.Time) (time.Time, time.Time) {
+	if d.absolute != nil && !d.day {
+		return *d.absolute, d.absolute.Add(time.Microsecond)
+	}
+
+	if d.absolute != nil {
+		start := time.Date(d.absolute.Year(), d.absolute.Month(), d.absolute.Day(), 0, 0, 0, 0, now.Location())
+		return start, start.AddDate(0, 0, 1)
+	}
+
+	if d.unit == 'h' {
+		t := now.Add(time.Duration(d.offset) * time.Hour)
+		return t, t.Add(time.Microsecond)
+	}
+
+	start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch d.unit {
+	case 'd':
+		start = start.AddDate(0, 0, d.offset)
+	case 'w':
+		start = start.AddDate(0, 0, 7*d.offset)
+	case 'm':
+		start = start.AddDate(0, d.offset, 0)
+	}
+	return start, start.AddDate(0, 0, 1)
+}
+
+// ------------------------------------------------------------
+
+type parser struct {
+	tokens []token
+	i      int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.i]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.i]
+	if t.kind != tokenEOF {
+		p.i++
+	}
+	return t
+}
+
+func (p *parser) parseOr(depth int) (Node, error) {
+	left, err := p.parseAnd(depth)
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().kind == tokenOr {
+		p.next()
+		right, err := p.parseAnd(depth)
+		if err != nil {
+			return nil, err
+		}
+		left = Or{Left: left, Right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseAnd(depth int) (Node, error) {
+	left, err := p.parseUnary(depth)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		switch p.peek().kind {
+		case tokenAnd:
+			p.next()
+		case tokenTerm, tokenLParen, tokenNot:
+		default:
+			return left, nil
+		}
+
+		right, err := p.parseUnary(depth)
+		if err != nil {
+			return nil, err
+		}
+		left = And{Left: left, Right: right}
+	}
+}
+
+func (p *parser) parseUnary(depth int) (Node, error) {
+	if depth >= MaxDepth {
+		return nil, &Error{Pos: p.peek().pos, Message: "query is nested too deeply"}
+	}
+
+	if p.peek().kind == tokenNot {
+		p.next()
+		operand, err := p.parseUnary(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		return Not{Operand: operand}, nil
+	}
+
+	return p.parsePrimary(depth)
+}
+
+func (p *parser) parsePrimary(depth int) (Node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenTerm:
+		return *t.term, nil
+	case tokenLParen:
+		node, err := p.parseOr(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if p.peek().kind != tokenRParen {
+			return nil, &Error{Pos: t.pos, Message: `missing ")" to close this "("`}
+		}
+		p.next()
+		return node, nil
+	}
+
+	return nil, &Error{Pos: t.pos, Message: "expected a term, found " + t.describe()}
+}
@@ -0,0 +1,121 @@
+package filterql
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// render writes a parsed query out with every group in parentheses.
+func render(node Node) string {
+	switch n := node.(type) {
+	case And:
+		return "(" + render(n.Left) + " AND " + render(n.Right) + ")"
+	case Or:
+		return "(" + render(n.Left) + " OR " + render(n.Right) + ")"
+	case Not:
+		return "NOT " + render(n.Operand)
+	case Term:
+		value := n.Value
+		if n.Quoted {
+			value = strconv.Quote(value)
+		}
+		return n.Field + string(n.Op) + value
+	}
+	return "?"
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		// Precedence and grouping
+		{"a b", "(text=a AND text=b)"},
+		{"a AND b", "(text=a AND text=b)"},
+		{"a OR b c", "(text=a OR (text=b AND text=c))"},
+		{"a b OR c", "((text=a AND text=b) OR text=c)"},
+		{"a AND b OR c AND d", "((text=a AND text=b) OR (text=c AND text=d))"},
+		{"a OR b OR c", "((text=a OR text=b) OR text=c)"},
+		{"(a OR b) c", "((text=a OR text=b) AND text=c)"},
+		{"a (b OR (c d))", "(text=a AND (text=b OR (text=c AND text=d)))"},
+		{
+			"priority:high OR (category:work AND due<7d) -tag:someday",
+			"(priority=high OR ((category=work AND due<7d) AND NOT tag=someday))",
+		},
+
+		// Negation
+		{"NOT a OR b", "(NOT text=a OR text=b)"},
+		{"-a b", "(NOT text=a AND text=b)"},
+		{"-(a OR b)", "NOT (text=a OR text=b)"},
+		{"NOT -a", "NOT NOT text=a"},
+		{"-tag:someday", "NOT tag=someday"},
+		{"a - b", "((text=a AND text=-) AND text=b)"},
+
+		// Operators and values
+		{"status:ACTIVE", "status=active"},
+		{"Priority=High", "priority=high"},
+		{"status!=completed", "status!=completed"},
+		{"priority>=medium", "priority>=medium"},
+		{"due<=2026-01-01", "due<=2026-01-01"},
+		{"tag:OR", "tag=OR"},
+		{"and or", "(text=and AND text=or)"},
+
+		// Quoting
+		{`"buy milk"`, `text="buy milk"`},
+		{`tag:"some day"`, `tag="some day"`},
+		{`"say \"hi\""`, `text="say \"hi\""`},
+		{`category:"none"`, `category="none"`},
+		{`"OR"`, `text="OR"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			node, err := Parse(tt.query)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, render(node))
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		query   string
+		pos     int
+		message string
+	}{
+		{"", 1, "query is empty"},
+		{"   ", 1, "query is empty"},
+		{"foo:bar", 1, `unknown field "foo"`},
+		{":x", 1, `missing field before ":"`},
+		{"priority:urgent", 10, `unknown priority "urgent"`},
+		{"due<soon", 5, `"soon" isn't a date`},
+		{"due:", 5, "missing value after due:"},
+		{"tag<x", 4, "tag can only be compared with :, = or !="},
+		{"due>none", 5, "due can only be compared with :, = or != to none"},
+		{`text:"unterminated`, 6, "unterminated quote"},
+		{"status:active (priority:high", 15, `missing ")" to close this "("`},
+		{"status:active )", 15, `unexpected ")"`},
+		{"AND status:active", 1, "expected a term, found AND"},
+		{"status:active OR", 17, "expected a term, found end of query"},
+		{"a ()", 4, `expected a term, found ")"`},
+		// Positions count characters, not bytes
+		{"café priority:urgent", 15, `unknown priority "urgent"`},
+		{strings.Repeat("(", MaxDepth+1) + "a" + strings.Repeat(")", MaxDepth+1), MaxDepth + 1, "query is nested too deeply"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			_, err := Parse(tt.query)
+			require.Error(t, err)
+
+			var queryErr *Error
+			require.ErrorAs(t, err, &queryErr)
+			assert.Equal(t, tt.pos, queryErr.Pos)
+			assert.Contains(t, queryErr.Message, tt.message)
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package filterql
+
+import (
+	"strconv"
+	"time"
+)
+
+// columns are the todo columns of the date fields.
+var columns = map[string]string{
+	"due":       "t.due_date",
+	"created":   "t.created_at",
+	"completed": "t.completed_at",
+}
+
+// priorityRank orders priorities for <, <=, > and >=.
+const priorityRank = "CASE t.priority WHEN 'low' THEN 1 WHEN 'medium' THEN 2 WHEN 'high' THEN 3 END"
+
+// Compile returns the SQL condition selecting the todos t that match a
+// parsed query. Values are never written into the SQL but added to args
+// as q_1, q_2 and so on. Relative dates count from now, and days are
+// counted in now's location.
+func Compile(node Node, now time.Time, args map[string]any) string {
+	c := &compiler{now: now, args: args}
+	return c.compile(node)
+}
+
+type compiler struct {
+	now  time.Time
+	args map[string]any
+	n    int
+}
+
+// param adds a value to the arguments and returns its placeholder.
+func (c *compiler) param(value any) string {
+	c.n++
+	name := "q_" + strconv.Itoa(c.n)
+	c.args[name] = value
+	return "@" + name
+}
+
+func (c *compiler) compile(node Node) string {
+	switch n := node.(type) {
+	case And:
+		return "(" + c.compile(n.Left) + " AND " + c.compile(n.Right) + ")"
+	case Or:
+		return "(" + c.compile(n.Left) + " OR " + c.compile(n.Right) + ")"
+	case Not:
+		return "(NOT " + c.compile(n.Operand) + ")"
+	case Term:
+		if n.Op == OpNotEqual {
+			return "(NOT " + c.term(n) + ")"
+		}
+		return c.term(n)
+	}
+	return "FALSE"
+}
+
+// term compiles a term, reading != as =. Its condition is never NULL, so
+// negating it keeps the todos without a value.
+func (c *compiler) term(t Term) string {
+	switch fields[t.Field] {
+	case kindStatus:
+		return "t.status = " + c.param(t.Value)
+
+	case kindPriority:
+		switch t.Op {
+		case OpEqual, OpNotEqual:
+			return "t.priority = " + c.param(t.Value)
+		}
+		rank := 1
+		for i, p := range priorities {
+			if p == t.Value {
+				rank = i + 1
+			}
+		}
+		return priorityRank + " " + string(t.Op) + " " + c.param(rank)
+
+	case kindDate:
+		column := columns[t.Field]
+		if t.date == nil {
+			return presence(column, t.Value)
+		}
+
+		start, end := t.date.bounds(c.now)
+		var condition string
+		switch t.Op {
+		case OpLess:
+			condition = column + " < " + c.param(start)
+		case OpLessOrEqual:
+			condition = column + " < " + c.param(end)
+		case OpGreater:
+			condition = column + " >= " + c.param(end)
+		case OpGreaterOrEqual:
+			condition = column + " >= " + c.param(start)
+		default:
+			condition = column + " >= " + c.param(start) + " AND " + column + " < " + c.param(end)
+		}
+		return "COALESCE(" + condition + ", FALSE)"
+
+	case kindCategory:
+		if !t.Quoted && (t.Value == "none" || t.Value == "any") {
+			return presence("t.category_id", t.Value)
+		}
+		if t.categoryID != nil {
+			return "COALESCE(t.category_id = " + c.param(*t.categoryID) + ", FALSE)"
+		}
+		return `EXISTS (
+			SELECT 1
+			FROM todo_categories c
+			WHERE c.id=t.category_id
+				AND LOWER(c.name)=LOWER(` + c.param(t.Value) + `)
+		)`
+
+	case kindTag:
+		if !t.Quoted && (t.Value == "none" || t.Value == "any") {
+			tagged := "EXISTS (SELECT 1 FROM todo_tags tt WHERE tt.todo_id=t.id)"
+			if t.Value == "none" {
+				return "NOT " + tagged
+			}
+			return tagged
+		}
+		return `EXISTS (
+			SELECT 1
+			FROM todo_tags tt
+			JOIN tags tg ON tg.id=tt.tag_id
+			WHERE tt.todo_id=t.id
+				AND LOWER(tg.name)=LOWER(` + c.param(t.Value) + `)
+		)`
+
+	case kindText:
+		function := "plainto_tsquery"
+		if t.Quoted {
+			function = "phraseto_tsquery"
+		}
+		return "COALESCE(t.search_vector @@ " + function + "('english', " + c.param(t.Value) + "), FALSE)"
+	}
+
+	return "FALSE"
+}
+
+// presence checks whether a column has a value, for none and any.
+func presence(column, value string) string {
+	if value == "none" {
+		return column + " IS NULL"
+	}
+	return column + " IS NOT NULL"
+}
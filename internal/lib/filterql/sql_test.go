@@ -0,0 +1,110 @@
+package filterql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompileDates(t *testing.T) {
+	zone := time.FixedZone("UTC+2", 2*60*60)
+	now := time.Date(2026, 10, 17, 15, 4, 5, 0, zone)
+	day := func(month time.Month, d int) time.Time {
+		return time.Date(2026, month, d, 0, 0, 0, 0, zone)
+	}
+
+	tests := []struct {
+		query string
+		want  string
+		args  map[string]any
+	}{
+		{
+			"due<7d",
+			"COALESCE(t.due_date < @q_1, FALSE)",
+			map[string]any{"q_1": day(10, 24)},
+		},
+		{
+			"due:today",
+			"COALESCE(t.due_date >= @q_1 AND t.due_date < @q_2, FALSE)",
+			map[string]any{"q_1": day(10, 17), "q_2": day(10, 18)},
+		},
+		{
+			"due<=tomorrow",
+			"COALESCE(t.due_date < @q_1, FALSE)",
+			map[string]any{"q_1": day(10, 19)},
+		},
+		{
+			"created>-2w",
+			"COALESCE(t.created_at >= @q_1, FALSE)",
+			map[string]any{"q_1": day(10, 4)},
+		},
+		{
+			"due>=yesterday",
+			"COALESCE(t.due_date >= @q_1, FALSE)",
+			map[string]any{"q_1": day(10, 16)},
+		},
+		{
+			"due<1m",
+			"COALESCE(t.due_date < @q_1, FALSE)",
+			map[string]any{"q_1": day(11, 17)},
+		},
+		{
+			"due<12h",
+			"COALESCE(t.due_date < @q_1, FALSE)",
+			map[string]any{"q_1": now.Add(12 * time.Hour)},
+		},
+		{
+			"completed:2026-01-01",
+			"COALESCE(t.completed_at >= @q_1 AND t.completed_at < @q_2, FALSE)",
+			map[string]any{"q_1": day(1, 1), "q_2": day(1, 2)},
+		},
+		{
+			"due:none",
+			"t.due_date IS NULL",
+			map[string]any{},
+		},
+		{
+			"-due:any",
+			"(NOT t.due_date IS NOT NULL)",
+			map[string]any{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			node, err := Parse(tt.query)
+			require.NoError(t, err)
+
+			args := map[string]any{}
+			assert.Equal(t, tt.want, Compile(node, now, args))
+			assert.Equal(t, tt.args, args)
+		})
+	}
+}
+
+func TestCompileNamesIgnoreCase(t *testing.T) {
+	for _, query := range []string{"category:Work", "tag:Someday"} {
+		t.Run(query, func(t *testing.T) {
+			node, err := Parse(query)
+			require.NoError(t, err)
+
+			args := map[string]any{}
+			sql := Compile(node, time.Now(), args)
+			assert.Regexp(t, `LOWER\(\w+\.name\)=LOWER\(@q_1\)`, sql)
+			assert.Len(t, args, 1)
+		})
+	}
+}
+
+func TestCompileCombinesTerms(t *testing.T) {
+	node, err := Parse("status:active OR -priority:low status!=draft")
+	require.NoError(t, err)
+
+	args := map[string]any{}
+	assert.Equal(t,
+		"(t.status = @q_1 OR ((NOT t.priority = @q_2) AND (NOT t.status = @q_3)))",
+		Compile(node, time.Now(), args))
+	assert.Equal(t, map[string]any{"q_1": "active", "q_2": "low", "q_3": "draft"}, args)
+}
@@ -0,0 +1,105 @@
+// Package ical writes iCalendar (RFC 5545) data.
+package ical
+
+import (
+	"io"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// MIMETextCalendar is the media type of iCalendar data.
+const MIMETextCalendar = "text/calendar; charset=utf-8"
+
+// maxLineOctets is the longest a content line may be before it is folded.
+const maxLineOctets = 75
+
+var textEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	";", `\;`,
+	",", `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+	"\r", "",
+)
+
+// durationPattern matches RFC 5545 durations such as -PT15M or P1DT2H.
+var durationPattern = regexp.MustCompile(`^[+-]?P(\d+W|\d+D(T(\d+H(\d+M)?(\d+S)?|\d+M(\d+S)?|\d+S))?|T(\d+H(\d+M)?(\d+S)?|\d+M(\d+S)?|\d+S))$`)
+
+// IsDuration reports whether s is an RFC 5545 duration.
+func IsDuration(s string) bool {
+	return durationPattern.MatchString(s)
+}
+
+// Writer writes content lines, folding long ones. The first error is kept
+// and returned by Err; writes after it do nothing.
+type Writer struct {
+	w   io.Writer
+	err error
+}
+
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+// Begin opens a component such as VCALENDAR or VTODO.
+func (w *Writer) Begin(component string) {
+	w.Property("BEGIN", component)
+}
+
+// End closes a component opened with Begin.
+func (w *Writer) End(component string) {
+	w.Property("END", component)
+}
+
+// Property writes a property whose value is already in iCalendar form. name
+// may carry parameters, as in "TRIGGER;RELATED=END".
+func (w *Writer) Property(name, value string) {
+	w.line(name + ":" + value)
+}
+
+// Text writes a TEXT property, escaping the value.
+func (w *Writer) Text(name, value string) {
+	w.Property(name, textEscaper.Replace(value))
+}
+
+// Time writes a DATE-TIME property in UTC.
+func (w *Writer) Time(name string, t time.Time) {
+	w.Property(name, FormatTime(t))
+}
+
+func (w *Writer) Err() error {
+	return w.err
+}
+
+// line writes a content line, folding it into lines of at most
+// maxLineOctets without splitting a UTF-8 sequence.
+func (w *Writer) line(s string) {
+	if w.err != nil {
+		return
+	}
+
+	var b strings.Builder
+	limit := maxLineOctets
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		b.WriteString(s[:cut])
+		b.WriteString("\r\n ")
+		s = s[cut:]
+		// The leading space of a continuation line counts towards it
+		limit = maxLineOctets - 1
+	}
+	b.WriteString(s)
+	b.WriteString("\r\n")
+
+	_, w.err = io.WriteString(w.w, b.String())
+}
+
+// FormatTime returns t as a UTC DATE-TIME value.
+func FormatTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
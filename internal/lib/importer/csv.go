@@ -0,0 +1,98 @@
+package importer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/uttam282005/tasker/internal/model/imports"
+	"github.com/uttam282005/tasker/internal/model/todo"
+)
+
+// parseCSV reads any CSV through a mapping of its columns. Values of the
+// priority and status columns are matched to tasker's regardless of case.
+func parseCSV(data []byte, mapping *imports.ColumnMapping) ([]Item, error) {
+	header, records, err := readCSV(data)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, h := range header {
+		columns[strings.ToLower(h)] = i
+	}
+
+	var missing []string
+	col := func(name string) int {
+		if name == "" {
+			return -1
+		}
+		i, ok := columns[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			missing = append(missing, strconv.Quote(name))
+			return -1
+		}
+		return i
+	}
+
+	titleCol := col(mapping.Title)
+	descriptionCol := col(mapping.Description)
+	dueDateCol := col(mapping.DueDate)
+	priorityCol := col(mapping.Priority)
+	statusCol := col(mapping.Status)
+	categoryCol := col(mapping.Category)
+	tagsCol := col(mapping.Tags)
+	idCol := col(mapping.ID)
+	parentIDCol := col(mapping.ParentID)
+
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("the file has no column named %s", strings.Join(missing, ", "))
+	}
+
+	items := make([]Item, 0, len(records))
+	for i, record := range records {
+		row := i + 2
+		item := Item{
+			Row:         row,
+			Ref:         strconv.Itoa(row),
+			Title:       field(record, titleCol),
+			Description: optionalString(field(record, descriptionCol)),
+			Category:    field(record, categoryCol),
+			Tags:        splitTags(field(record, tagsCol)),
+			ParentRef:   field(record, parentIDCol),
+		}
+		if id := field(record, idCol); id != "" {
+			item.Ref = id
+		}
+
+		if p := field(record, priorityCol); p != "" {
+			switch priority := todo.Priority(strings.ToLower(p)); priority {
+			case todo.PriorityLow, todo.PriorityMedium, todo.PriorityHigh:
+				item.Priority = &priority
+			default:
+				item.Problems = append(item.Problems, fmt.Sprintf("unknown priority %q", p))
+			}
+		}
+
+		if s := field(record, statusCol); s != "" {
+			switch status := todo.Status(strings.ToLower(s)); status {
+			case todo.StatusDraft, todo.StatusActive, todo.StatusCompleted, todo.StatusArchived:
+				item.Status = status
+			default:
+				item.Problems = append(item.Problems, fmt.Sprintf("unknown status %q", s))
+			}
+		}
+
+		if d := field(record, dueDateCol); d != "" {
+			if due, ok := parseDate(d); ok {
+				item.DueDate = due
+			} else {
+				item.Problems = append(item.Problems, fmt.Sprintf("due date %q isn't a date", d))
+			}
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
@@ -0,0 +1,142 @@
+// Package importer reads the todos out of files exported from other apps.
+//
+// Every source is read into the same Items, so the import job doesn't need
+// to know where a file came from. Problems with a single item are recorded
+// on the item and the rest of the file is still read; only a file that
+// can't be read at all is an error.
+package importer
+
+import (
+	"errors"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/uttam282005/tasker/internal/model/imports"
+	"github.com/uttam282005/tasker/internal/model/todo"
+)
+
+// MaxItems caps how many todos a single file may hold.
+const MaxItems = 10000
+
+// ErrTooManyItems is returned for files holding more than MaxItems todos.
+var ErrTooManyItems = errors.New("file holds more than 10000 todos")
+
+// Item is a todo read from an export.
+type Item struct {
+	// Row is the item's row in a CSV, the header being row 1, or its
+	// position in a JSON export, counting from 1
+	Row int
+	// Ref identifies the item within the file, for ParentRef to point at.
+	// Items without a ParentRef are top-level.
+	Ref       string
+	ParentRef string
+
+	Title       string
+	Description *string
+	Priority    *todo.Priority
+	// Status is empty for the default status
+	Status   todo.Status
+	DueDate  *time.Time
+	Category string
+	Tags     []string
+
+	// Problems are the parts of the item that couldn't be read; the rest is
+	// still imported
+	Problems []string
+}
+
+// Parse reads the items out of a file exported from source. fileName is the
+// name it was uploaded with, which some exports take the project name from.
+func Parse(source imports.Source, fileName string, data []byte, mapping *imports.ColumnMapping) ([]Item, error) {
+	var (
+		items []Item
+		err   error
+	)
+
+	switch source {
+	case imports.SourceTodoist:
+		if isJSON(data) {
+			items, err = parseTodoistJSON(data)
+		} else {
+			items, err = parseTodoistCSV(data, projectName(fileName))
+		}
+	case imports.SourceTrello:
+		items, err = parseTrello(data)
+	case imports.SourceCSV:
+		if mapping == nil {
+			return nil, errors.New("csv imports need a column mapping")
+		}
+		items, err = parseCSV(data, mapping)
+	default:
+		return nil, errors.New("unknown import source " + string(source))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(items) > MaxItems {
+		return nil, ErrTooManyItems
+	}
+	return items, nil
+}
+
+func isJSON(data []byte) bool {
+	trimmed := strings.TrimSpace(strings.TrimPrefix(string(data[:min(len(data), 512)]), "\ufeff"))
+	return strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[")
+}
+
+// projectName returns the project a file is named after, e.g. "Work" for
+// "Work.csv".
+func projectName(fileName string) string {
+	base := path.Base(strings.ReplaceAll(fileName, `\`, "/"))
+	return strings.TrimSpace(strings.TrimSuffix(base, path.Ext(base)))
+}
+
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// parseDate reads the date formats exports commonly use. Times without a
+// zone are taken as UTC.
+func parseDate(s string) (*time.Time, bool) {
+	s = strings.TrimSpace(s)
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return &t, true
+		}
+	}
+	return nil, false
+}
+
+// splitTags splits a list of tags on commas and semicolons, dropping blanks
+// and repeats.
+func splitTags(s string) []string {
+	var tags []string
+	seen := map[string]bool{}
+	for _, tag := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ';' }) {
+		tag = strings.TrimSpace(tag)
+		if tag != "" && !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+func optionalString(s string) *string {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func priorityPtr(p todo.Priority) *todo.Priority {
+	return &p
+}
@@ -0,0 +1,249 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/uttam282005/tasker/internal/model/todo"
+)
+
+// todoistLabel matches the @labels Todoist writes into a task's content in
+// its CSV exports.
+var todoistLabel = regexp.MustCompile(`(?:^|\s)@([^\s@]+)`)
+
+// parseTodoistCSV reads a project exported as CSV from Todoist. The export
+// has one file per project, so every task goes into the category named
+// after the file. Subtasks are marked by INDENT, one deeper than their
+// parent.
+func parseTodoistCSV(data []byte, project string) ([]Item, error) {
+	header, records, err := readCSV(data)
+	if err != nil {
+		return nil, err
+	}
+
+	col := func(name string) int {
+		for i, h := range header {
+			if strings.EqualFold(h, name) {
+				return i
+			}
+		}
+		return -1
+	}
+	typeCol, contentCol := col("TYPE"), col("CONTENT")
+	if typeCol < 0 || contentCol < 0 {
+		return nil, errors.New("not a Todoist CSV export: missing the TYPE and CONTENT columns")
+	}
+	descriptionCol, priorityCol, indentCol, dateCol := col("DESCRIPTION"), col("PRIORITY"), col("INDENT"), col("DATE")
+
+	var items []Item
+	// parents holds the last task seen at each indent, for the tasks
+	// under it to point at
+	var parents []string
+
+	for i, record := range records {
+		if !strings.EqualFold(field(record, typeCol), "task") {
+			continue
+		}
+
+		row := i + 2
+		item := Item{
+			Row:      row,
+			Ref:      strconv.Itoa(row),
+			Category: project,
+		}
+
+		item.Title, item.Tags = todoistContent(field(record, contentCol))
+		item.Description = optionalString(field(record, descriptionCol))
+
+		if p := field(record, priorityCol); p != "" {
+			// The CSV numbers priorities the way Todoist shows them, p1 first
+			switch p {
+			case "1":
+				item.Priority = priorityPtr(todo.PriorityHigh)
+			case "2":
+				item.Priority = priorityPtr(todo.PriorityMedium)
+			case "3":
+				item.Priority = priorityPtr(todo.PriorityLow)
+			case "4":
+			default:
+				item.Problems = append(item.Problems, fmt.Sprintf("unknown priority %q", p))
+			}
+		}
+
+		// Todoist writes due dates as typed ("every monday", "tomorrow")
+		// besides plain dates, and only the plain ones can be read
+		if d := field(record, dateCol); d != "" {
+			if due, ok := parseDate(d); ok {
+				item.DueDate = due
+			} else {
+				item.Problems = append(item.Problems, fmt.Sprintf("due date %q isn't a date", d))
+			}
+		}
+
+		indent := 1
+		if s := field(record, indentCol); s != "" {
+			if n, err := strconv.Atoi(s); err == nil && n > 0 {
+				indent = n
+			}
+		}
+		if indent > len(parents)+1 {
+			indent = len(parents) + 1
+		}
+		parents = append(parents[:indent-1], item.Ref)
+		if indent > 1 {
+			item.ParentRef = parents[indent-2]
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// todoistContent splits the @labels out of a task's content.
+func todoistContent(content string) (string, []string) {
+	var tags []string
+	for _, m := range todoistLabel.FindAllStringSubmatch(content, -1) {
+		tags = append(tags, m[1])
+	}
+	title := todoistLabel.ReplaceAllString(content, "")
+	return strings.Join(strings.Fields(title), " "), tags
+}
+
+type todoistBackup struct {
+	Projects []struct {
+		ID   json.RawMessage `json:"id"`
+		Name string          `json:"name"`
+	} `json:"projects"`
+	Items []struct {
+		ID          json.RawMessage `json:"id"`
+		ProjectID   json.RawMessage `json:"project_id"`
+		ParentID    json.RawMessage `json:"parent_id"`
+		Content     string          `json:"content"`
+		Description string          `json:"description"`
+		Priority    int             `json:"priority"`
+		Labels      []string        `json:"labels"`
+		Checked     bool            `json:"checked"`
+		IsDeleted   bool            `json:"is_deleted"`
+		Due         *struct {
+			Date string `json:"date"`
+		} `json:"due"`
+	} `json:"items"`
+}
+
+// parseTodoistJSON reads a Todoist JSON backup, as returned by its sync API,
+// with every project in one file.
+func parseTodoistJSON(data []byte) ([]Item, error) {
+	var backup todoistBackup
+	if err := json.Unmarshal(data, &backup); err != nil {
+		return nil, fmt.Errorf("not a Todoist JSON backup: %w", err)
+	}
+	if backup.Items == nil {
+		return nil, errors.New("not a Todoist JSON backup: missing items")
+	}
+
+	projects := make(map[string]string, len(backup.Projects))
+	for _, p := range backup.Projects {
+		projects[todoistID(p.ID)] = p.Name
+	}
+
+	items := make([]Item, 0, len(backup.Items))
+	for i, t := range backup.Items {
+		if t.IsDeleted {
+			continue
+		}
+
+		title, tags := todoistContent(t.Content)
+		item := Item{
+			Row:         i + 1,
+			Ref:         todoistID(t.ID),
+			ParentRef:   todoistID(t.ParentID),
+			Title:       title,
+			Description: optionalString(t.Description),
+			Category:    projects[todoistID(t.ProjectID)],
+			Tags:        append(t.Labels, tags...),
+		}
+
+		// The API numbers priorities the other way round from the app, 4
+		// being p1
+		switch t.Priority {
+		case 4:
+			item.Priority = priorityPtr(todo.PriorityHigh)
+		case 3:
+			item.Priority = priorityPtr(todo.PriorityMedium)
+		case 2:
+			item.Priority = priorityPtr(todo.PriorityLow)
+		}
+
+		if t.Checked {
+			item.Status = todo.StatusCompleted
+		}
+
+		if t.Due != nil && t.Due.Date != "" {
+			if due, ok := parseDate(t.Due.Date); ok {
+				item.DueDate = due
+			} else {
+				item.Problems = append(item.Problems, fmt.Sprintf("due date %q isn't a date", t.Due.Date))
+			}
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// todoistID reads an ID that older backups write as a number and newer ones
+// as a string. A missing or null ID is empty.
+func todoistID(raw json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	if string(raw) == "null" {
+		return ""
+	}
+	return string(raw)
+}
+
+// readCSV reads a CSV file's header and the records after it. Records may
+// have fewer fields than the header.
+func readCSV(data []byte) ([]string, [][]string, error) {
+	r := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+
+	header, err := r.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil, errors.New("the file is empty")
+		}
+		return nil, nil, fmt.Errorf("failed to read the CSV header: %w", err)
+	}
+	for i := range header {
+		header[i] = strings.TrimSpace(header[i])
+	}
+
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read the CSV file: %w", err)
+	}
+
+	return header, records, nil
+}
+
+// field returns the trimmed value of a record's column, or "" if the column
+// is missing.
+func field(record []string, col int) string {
+	if col < 0 || col >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[col])
+}
@@ -0,0 +1,127 @@
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/uttam282005/tasker/internal/model/todo"
+)
+
+type trelloBoard struct {
+	Lists []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"lists"`
+	Cards []struct {
+		ID          string  `json:"id"`
+		Name        string  `json:"name"`
+		Desc        string  `json:"desc"`
+		IDList      string  `json:"idList"`
+		Due         *string `json:"due"`
+		DueComplete bool    `json:"dueComplete"`
+		Closed      bool    `json:"closed"`
+		Labels      []struct {
+			Name  string `json:"name"`
+			Color string `json:"color"`
+		} `json:"labels"`
+	} `json:"cards"`
+	Checklists []struct {
+		IDCard     string `json:"idCard"`
+		CheckItems []struct {
+			ID    string  `json:"id"`
+			Name  string  `json:"name"`
+			State string  `json:"state"`
+			Due   *string `json:"due"`
+		} `json:"checkItems"`
+	} `json:"checklists"`
+}
+
+// parseTrello reads a board exported as JSON from Trello. Lists become
+// categories, labels become tags, and the items of a card's checklists
+// become its subtasks. Archived cards stay archived.
+func parseTrello(data []byte) ([]Item, error) {
+	var board trelloBoard
+	if err := json.Unmarshal(data, &board); err != nil {
+		return nil, fmt.Errorf("not a Trello board export: %w", err)
+	}
+	if board.Cards == nil {
+		return nil, errors.New("not a Trello board export: missing cards")
+	}
+
+	lists := make(map[string]string, len(board.Lists))
+	for _, l := range board.Lists {
+		lists[l.ID] = l.Name
+	}
+
+	checklists := map[string][]int{}
+	for i, c := range board.Checklists {
+		checklists[c.IDCard] = append(checklists[c.IDCard], i)
+	}
+
+	var items []Item
+	row := 0
+
+	for _, card := range board.Cards {
+		row++
+		item := Item{
+			Row:         row,
+			Ref:         card.ID,
+			Title:       card.Name,
+			Description: optionalString(card.Desc),
+			Category:    lists[card.IDList],
+		}
+
+		for _, label := range card.Labels {
+			// Trello labels may be just a colour
+			name := label.Name
+			if name == "" {
+				name = label.Color
+			}
+			if name != "" {
+				item.Tags = append(item.Tags, name)
+			}
+		}
+
+		switch {
+		case card.Closed:
+			item.Status = todo.StatusArchived
+		case card.DueComplete:
+			item.Status = todo.StatusCompleted
+		}
+
+		item.DueDate, item.Problems = trelloDue(card.Due)
+		items = append(items, item)
+
+		for _, i := range checklists[card.ID] {
+			for _, checkItem := range board.Checklists[i].CheckItems {
+				row++
+				subtask := Item{
+					Row:       row,
+					Ref:       checkItem.ID,
+					ParentRef: card.ID,
+					Title:     checkItem.Name,
+					Category:  item.Category,
+				}
+				if checkItem.State == "complete" {
+					subtask.Status = todo.StatusCompleted
+				}
+				subtask.DueDate, subtask.Problems = trelloDue(checkItem.Due)
+				items = append(items, subtask)
+			}
+		}
+	}
+
+	return items, nil
+}
+
+func trelloDue(due *string) (*time.Time, []string) {
+	if due == nil || *due == "" {
+		return nil, nil
+	}
+	if t, ok := parseDate(*due); ok {
+		return t, nil
+	}
+	return nil, []string{fmt.Sprintf("due date %q isn't a date", *due)}
+}
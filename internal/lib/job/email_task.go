@@ -13,6 +13,14 @@ const (
 	TaskWelcome           = "email:welcome"
 	TaskReminderEmail     = "email:reminder"
 	TaskWeeklyReportEmail = "email:weekly_report"
+	TaskTodoWatcherEmail  = "email:todo_watcher"
+)
+
+// Events a todo's watchers are notified about
+const (
+	WatcherEventUpdated   = "updated"
+	WatcherEventCompleted = "completed"
+	WatcherEventCommented = "commented"
 )
 
 type WelcomeEmailPayload struct {
@@ -83,3 +91,27 @@ func EnqueueWeeklyReportEmail(client *asynq.Client, task *WeeklyReportEmailTask)
 	_, err = client.Enqueue(asynqTask)
 	return err
 }
+
+type TodoWatcherEmailTask struct {
+	UserID    string    `json:"user_id"`
+	TodoID    uuid.UUID `json:"todo_id"`
+	TodoTitle string    `json:"todo_title"`
+	ActorID   string    `json:"actor_id"`
+	Event     string    `json:"event"`
+	Fields    []string  `json:"fields"` // changed fields for "updated" events
+}
+
+func EnqueueTodoWatcherEmail(client *asynq.Client, task *TodoWatcherEmailTask) error {
+	payload, err := json.Marshal(task)
+	if err != nil {
+		return err
+	}
+
+	asynqTask := asynq.NewTask(TaskTodoWatcherEmail, payload,
+		asynq.MaxRetry(3),
+		asynq.Queue("low"),
+		asynq.Timeout(30*time.Second))
+
+	_, err = client.Enqueue(asynqTask)
+	return err
+}
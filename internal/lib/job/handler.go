@@ -155,3 +155,114 @@ func (j *JobService) handleWeeklyReportEmailTask(ctx context.Context, t *asynq.T
 		Msg("Successfully sent weekly report email")
 	return nil
 }
+
+func (j *JobService) handleTodoWatcherEmailTask(ctx context.Context, t *asynq.Task) error {
+	var p TodoWatcherEmailTask
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		return fmt.Errorf("failed to unmarshal todo watcher email payload: %w", err)
+	}
+
+	j.logger.Info().
+		Str("type", "todo_watcher").
+		Str("event", p.Event).
+		Str("user_id", p.UserID).
+		Str("todo_id", p.TodoID.String()).
+		Msg("Processing todo watcher email task")
+
+	userEmail, err := j.authService.GetUserEmail(ctx, p.UserID)
+	if err != nil {
+		j.logger.Error().
+			Str("type", "todo_watcher").
+			Str("user_id", p.UserID).
+			Err(err).
+			Msg("Failed to resolve user email")
+		return fmt.Errorf("failed to resolve user email for user %s: %w", p.UserID, err)
+	}
+
+	err = j.emailClient.SendTodoWatcherEmail(
+		userEmail,
+		p.TodoTitle,
+		p.TodoID,
+		p.Event,
+		p.Fields,
+	)
+	if err != nil {
+		j.logger.Error().
+			Str("type", "todo_watcher").
+			Str("user_id", p.UserID).
+			Str("todo_id", p.TodoID.String()).
+			Err(err).
+			Msg("Failed to send todo watcher email")
+		return err
+	}
+
+	j.logger.Info().
+		Str("type", "todo_watcher").
+		Str("user_id", p.UserID).
+		Str("todo_id", p.TodoID.String()).
+		Msg("Successfully sent todo watcher email")
+	return nil
+}
+
+func (j *JobService) handleWebhookDeliveryTask(ctx context.Context, t *asynq.Task) error {
+	var p WebhookDeliveryTask
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		return fmt.Errorf("failed to unmarshal webhook delivery payload: %w", err)
+	}
+
+	retried, _ := asynq.GetRetryCount(ctx)
+	maxRetry, _ := asynq.GetMaxRetry(ctx)
+
+	j.logger.Info().
+		Str("type", "webhook_delivery").
+		Str("delivery_id", p.DeliveryID.String()).
+		Int("retried", retried).
+		Msg("Processing webhook delivery task")
+
+	retryable, err := j.webhooks.DeliverWebhook(ctx, p.DeliveryID, retried >= maxRetry)
+	if err != nil {
+		j.logger.Warn().
+			Str("type", "webhook_delivery").
+			Str("delivery_id", p.DeliveryID.String()).
+			Bool("retryable", retryable).
+			Err(err).
+			Msg("Webhook delivery attempt failed")
+		if !retryable {
+			return fmt.Errorf("%v: %w", err, asynq.SkipRetry)
+		}
+		return err
+	}
+
+	j.logger.Info().
+		Str("type", "webhook_delivery").
+		Str("delivery_id", p.DeliveryID.String()).
+		Msg("Successfully delivered webhook")
+	return nil
+}
+
+func (j *JobService) handleImportTask(ctx context.Context, t *asynq.Task) error {
+	var p ImportRunTask
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		return fmt.Errorf("failed to unmarshal import payload: %w", err)
+	}
+
+	j.logger.Info().
+		Str("type", "import").
+		Str("import_id", p.ImportID.String()).
+		Msg("Processing import task")
+
+	if err := j.imports.RunImport(ctx, p.ImportID); err != nil {
+		j.logger.Error().
+			Str("type", "import").
+			Str("import_id", p.ImportID.String()).
+			Err(err).
+			Msg("Failed to run import")
+		return err
+	}
+
+	j.logger.Info().
+		Str("type", "import").
+		Str("import_id", p.ImportID.String()).
+		Msg("Successfully ran import")
+	return nil
+}
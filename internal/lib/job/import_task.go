@@ -0,0 +1,32 @@
+package job
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+)
+
+const TaskImportRun = "import:run"
+
+type ImportRunTask struct {
+	ImportID uuid.UUID `json:"import_id"`
+}
+
+func EnqueueImport(client *asynq.Client, importID uuid.UUID) error {
+	payload, err := json.Marshal(ImportRunTask{ImportID: importID})
+	if err != nil {
+		return err
+	}
+
+	// A retry only gets past claiming the import if the first attempt never
+	// claimed it, so retrying never imports a file twice
+	asynqTask := asynq.NewTask(TaskImportRun, payload,
+		asynq.MaxRetry(3),
+		asynq.Queue("default"),
+		asynq.Timeout(10*time.Minute))
+
+	_, err = client.Enqueue(asynqTask)
+	return err
+}
@@ -2,6 +2,9 @@ package job
 
 import (
 	"context"
+	"time"
+
+	"github.com/google/uuid"
 
 	"github.com/hibiken/asynq"
 	"github.com/rs/zerolog"
@@ -14,6 +17,8 @@ type JobService struct {
 	server      *asynq.Server
 	logger      *zerolog.Logger
 	authService AuthServiceInterface
+	webhooks    WebhookDelivererInterface
+	imports     ImportRunnerInterface
 	emailClient *email.Client
 }
 
@@ -21,6 +26,18 @@ type AuthServiceInterface interface {
 	GetUserEmail(ctx context.Context, userID string) (string, error)
 }
 
+// WebhookDelivererInterface sends one attempt of a webhook delivery. final is
+// set on the last attempt asynq will make. retryable is false when another
+// attempt can't succeed, e.g. the endpoint rejected the payload.
+type WebhookDelivererInterface interface {
+	DeliverWebhook(ctx context.Context, deliveryID uuid.UUID, final bool) (retryable bool, err error)
+}
+
+// ImportRunnerInterface imports the file of a pending import.
+type ImportRunnerInterface interface {
+	RunImport(ctx context.Context, importID uuid.UUID) error
+}
+
 func NewJobService(logger *zerolog.Logger, cfg *config.Config) *JobService {
 	redisAddr := cfg.Redis.Address
 
@@ -39,6 +56,12 @@ func NewJobService(logger *zerolog.Logger, cfg *config.Config) *JobService {
 				"default":  3, // Default priority for most emails
 				"low":      1, // Lower priority for non-urgent emails
 			},
+			RetryDelayFunc: func(retried int, err error, task *asynq.Task) time.Duration {
+				if task.Type() == TaskWebhookDelivery {
+					return webhookRetryDelay(retried)
+				}
+				return asynq.DefaultRetryDelayFunc(retried, err, task)
+			},
 		},
 	)
 
@@ -53,12 +76,23 @@ func (j *JobService) SetAuthService(authService AuthServiceInterface) {
 	j.authService = authService
 }
 
+func (j *JobService) SetWebhookDeliverer(webhooks WebhookDelivererInterface) {
+	j.webhooks = webhooks
+}
+
+func (j *JobService) SetImportRunner(imports ImportRunnerInterface) {
+	j.imports = imports
+}
+
 func (j *JobService) Start() error {
 	// Register task handlers
 	mux := asynq.NewServeMux()
 	mux.HandleFunc(TaskWelcome, j.handleWelcomeEmailTask)
 	mux.HandleFunc(TaskReminderEmail, j.handleReminderEmailTask)
 	mux.HandleFunc(TaskWeeklyReportEmail, j.handleWeeklyReportEmailTask)
+	mux.HandleFunc(TaskTodoWatcherEmail, j.handleTodoWatcherEmailTask)
+	mux.HandleFunc(TaskWebhookDelivery, j.handleWebhookDeliveryTask)
+	mux.HandleFunc(TaskImportRun, j.handleImportTask)
 
 	j.logger.Info().Msg("Starting background job server")
 	if err := j.server.Start(mux); err != nil {
@@ -0,0 +1,38 @@
+package job
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+)
+
+const TaskWebhookDelivery = "webhook:deliver"
+
+// webhookMaxRetry and webhookRetryDelay spread a failing delivery's attempts
+// over roughly eight and a half hours.
+const webhookMaxRetry = 10
+
+func webhookRetryDelay(retried int) time.Duration {
+	return 30 * time.Second << min(retried, 9)
+}
+
+type WebhookDeliveryTask struct {
+	DeliveryID uuid.UUID `json:"delivery_id"`
+}
+
+func EnqueueWebhookDelivery(client *asynq.Client, deliveryID uuid.UUID) error {
+	payload, err := json.Marshal(WebhookDeliveryTask{DeliveryID: deliveryID})
+	if err != nil {
+		return err
+	}
+
+	asynqTask := asynq.NewTask(TaskWebhookDelivery, payload,
+		asynq.MaxRetry(webhookMaxRetry),
+		asynq.Queue("default"),
+		asynq.Timeout(30*time.Second))
+
+	_, err = client.Enqueue(asynqTask)
+	return err
+}
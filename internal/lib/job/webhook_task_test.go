@@ -0,0 +1,79 @@
+package job
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeDeliverer struct {
+	retryable bool
+	err       error
+	delivered []uuid.UUID
+}
+
+func (f *fakeDeliverer) DeliverWebhook(ctx context.Context, deliveryID uuid.UUID, final bool) (bool, error) {
+	f.delivered = append(f.delivered, deliveryID)
+	return f.retryable, f.err
+}
+
+func TestWebhookRetryDelay(t *testing.T) {
+	assert.Equal(t, 30*time.Second, webhookRetryDelay(0))
+	assert.Equal(t, time.Minute, webhookRetryDelay(1))
+	assert.Equal(t, 2*time.Minute, webhookRetryDelay(2))
+	assert.Equal(t, 256*time.Minute, webhookRetryDelay(9))
+	// The delay stops growing after the ninth retry
+	assert.Equal(t, webhookRetryDelay(9), webhookRetryDelay(10))
+	assert.Equal(t, webhookRetryDelay(9), webhookRetryDelay(100))
+
+	var total time.Duration
+	for retried := range webhookMaxRetry {
+		total += webhookRetryDelay(retried)
+	}
+	assert.Equal(t, 8*time.Hour+31*time.Minute+30*time.Second, total)
+}
+
+func TestHandleWebhookDeliveryTask(t *testing.T) {
+	deliveryID := uuid.New()
+	endpointErr := errors.New("endpoint responded with status 500")
+
+	tests := []struct {
+		name      string
+		deliverer *fakeDeliverer
+		wantErr   bool
+		skipRetry bool
+	}{
+		{"delivered", &fakeDeliverer{}, false, false},
+		{"retryable failure", &fakeDeliverer{retryable: true, err: endpointErr}, true, false},
+		{"permanent failure", &fakeDeliverer{retryable: false, err: endpointErr}, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := zerolog.Nop()
+			j := &JobService{logger: &logger}
+			j.SetWebhookDeliverer(tt.deliverer)
+
+			payload, err := json.Marshal(WebhookDeliveryTask{DeliveryID: deliveryID})
+			require.NoError(t, err)
+
+			err = j.handleWebhookDeliveryTask(context.Background(), asynq.NewTask(TaskWebhookDelivery, payload))
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.ErrorContains(t, err, endpointErr.Error())
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.skipRetry, errors.Is(err, asynq.SkipRetry))
+			assert.Equal(t, []uuid.UUID{deliveryID}, tt.deliverer.delivered)
+		})
+	}
+}
@@ -0,0 +1,75 @@
+// Package mergepatch implements JSON Merge Patch (RFC 7396).
+//
+// A merge patch is a JSON object shaped like the resource it changes. Members
+// it leaves out are kept, members set to null are removed, and nested objects
+// are merged recursively rather than replaced.
+package mergepatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+)
+
+// MIMEApplicationMergePatchJSON is the media type merge patches are sent as.
+const MIMEApplicationMergePatchJSON = "application/merge-patch+json"
+
+// ErrNotObject is returned for patches that aren't a JSON object. Any other
+// patch would replace the whole resource, which no endpoint allows.
+var ErrNotObject = errors.New("merge patch must be a JSON object")
+
+var null = []byte("null")
+
+// Members splits a patch into its top-level members.
+func Members(patch []byte) (map[string]json.RawMessage, error) {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(patch, &members); err != nil || members == nil {
+		return nil, ErrNotObject
+	}
+	return members, nil
+}
+
+// IsNull reports whether a member's value is null, meaning the patch removes
+// it.
+func IsNull(value json.RawMessage) bool {
+	return bytes.Equal(bytes.TrimSpace(value), null)
+}
+
+// Apply returns target with patch merged into it. An empty target is treated
+// as null.
+func Apply(target, patch []byte) ([]byte, error) {
+	var targetValue, patchValue any
+
+	if len(bytes.TrimSpace(target)) > 0 {
+		if err := json.Unmarshal(target, &targetValue); err != nil {
+			return nil, err
+		}
+	}
+	if err := json.Unmarshal(patch, &patchValue); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(merge(targetValue, patchValue))
+}
+
+func merge(target, patch any) any {
+	patchObject, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	targetObject, ok := target.(map[string]any)
+	if !ok {
+		targetObject = map[string]any{}
+	}
+
+	for name, value := range patchObject {
+		if value == nil {
+			delete(targetObject, name)
+		} else {
+			targetObject[name] = merge(targetObject[name], value)
+		}
+	}
+
+	return targetObject
+}
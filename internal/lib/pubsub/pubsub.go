@@ -0,0 +1,198 @@
+// Package pubsub fans messages out to every tasker instance over Redis. Each
+// topic is backed by a capped Redis stream, so a subscriber that reconnects
+// can replay what it missed, and by a pub/sub channel for live delivery.
+package pubsub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog"
+)
+
+const (
+	// backlogSize caps each topic's stream; older messages are trimmed.
+	backlogSize = 1000
+	// backlogTTL expires the stream of a topic nobody has published to lately.
+	backlogTTL = time.Hour
+)
+
+// Message is a published payload together with the stream ID that orders it
+// within its topic.
+type Message struct {
+	ID      string          `json:"id"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+type Broker struct {
+	client *redis.Client
+	logger *zerolog.Logger
+}
+
+func NewBroker(client *redis.Client, logger *zerolog.Logger) *Broker {
+	return &Broker{
+		client: client,
+		logger: logger,
+	}
+}
+
+func streamKey(topic string) string {
+	return "events:stream:" + topic
+}
+
+func channelName(topic string) string {
+	return "events:" + topic
+}
+
+// Publish appends payload to the topic's backlog and broadcasts it to live
+// subscribers, returning the message ID.
+func (b *Broker) Publish(ctx context.Context, topic string, payload []byte) (string, error) {
+	id, err := b.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: streamKey(topic),
+		MaxLen: backlogSize,
+		Approx: true,
+		Values: map[string]any{"payload": payload},
+	}).Result()
+	if err != nil {
+		return "", fmt.Errorf("failed to append to stream for topic=%s: %w", topic, err)
+	}
+
+	message, err := json.Marshal(Message{ID: id, Payload: payload})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	pipe := b.client.Pipeline()
+	pipe.Expire(ctx, streamKey(topic), backlogTTL)
+	pipe.Publish(ctx, channelName(topic), message)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return "", fmt.Errorf("failed to publish to topic=%s: %w", topic, err)
+	}
+
+	return id, nil
+}
+
+// Since returns the backlog published after lastID, oldest first. gap reports
+// that lastID has already been trimmed or expired, so messages may be missing
+// and the subscriber should resynchronise from scratch.
+func (b *Broker) Since(ctx context.Context, topic, lastID string) (messages []Message, gap bool, err error) {
+	if _, _, ok := parseID(lastID); !ok {
+		return nil, true, nil
+	}
+
+	oldest, err := b.client.XRangeN(ctx, streamKey(topic), "-", "+", 1).Result()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read stream for topic=%s: %w", topic, err)
+	}
+	if len(oldest) == 0 || CompareIDs(oldest[0].ID, lastID) > 0 {
+		return nil, true, nil
+	}
+
+	entries, err := b.client.XRange(ctx, streamKey(topic), "("+lastID, "+").Result()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read stream for topic=%s: %w", topic, err)
+	}
+
+	messages = make([]Message, 0, len(entries))
+	for _, entry := range entries {
+		payload, ok := entry.Values["payload"].(string)
+		if !ok {
+			continue
+		}
+		messages = append(messages, Message{ID: entry.ID, Payload: json.RawMessage(payload)})
+	}
+
+	return messages, false, nil
+}
+
+// Subscription delivers a topic's live messages until it is closed.
+type Subscription struct {
+	pubsub   *redis.PubSub
+	messages chan Message
+}
+
+// Subscribe starts listening on the topic. It returns once Redis has
+// confirmed the subscription, so a Since call made afterwards cannot miss a
+// message published in between.
+func (b *Broker) Subscribe(ctx context.Context, topic string) (*Subscription, error) {
+	ps := b.client.Subscribe(ctx, channelName(topic))
+	if _, err := ps.Receive(ctx); err != nil {
+		_ = ps.Close()
+		return nil, fmt.Errorf("failed to subscribe to topic=%s: %w", topic, err)
+	}
+
+	sub := &Subscription{
+		pubsub:   ps,
+		messages: make(chan Message),
+	}
+
+	go func() {
+		defer close(sub.messages)
+		for raw := range ps.Channel() {
+			var message Message
+			if err := json.Unmarshal([]byte(raw.Payload), &message); err != nil {
+				b.logger.Error().Err(err).Str("topic", topic).Msg("failed to decode published message")
+				continue
+			}
+			sub.messages <- message
+		}
+	}()
+
+	return sub, nil
+}
+
+// Messages is closed once the subscription is closed.
+func (s *Subscription) Messages() <-chan Message {
+	return s.messages
+}
+
+func (s *Subscription) Close() error {
+	err := s.pubsub.Close()
+	// Drain so the forwarding goroutine is not left blocked on a send
+	for range s.messages {
+	}
+	return err
+}
+
+// CompareIDs orders two stream IDs, returning -1, 0 or 1. Malformed IDs sort
+// first.
+func CompareIDs(a, b string) int {
+	aMs, aSeq, _ := parseID(a)
+	bMs, bSeq, _ := parseID(b)
+
+	switch {
+	case aMs < bMs:
+		return -1
+	case aMs > bMs:
+		return 1
+	case aSeq < bSeq:
+		return -1
+	case aSeq > bSeq:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func parseID(id string) (ms, seq uint64, ok bool) {
+	msPart, seqPart, found := strings.Cut(id, "-")
+	if !found {
+		return 0, 0, false
+	}
+
+	ms, err := strconv.ParseUint(msPart, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	seq, err = strconv.ParseUint(seqPart, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	return ms, seq, true
+}
@@ -0,0 +1,359 @@
+// Package rrule implements the subset of RFC 5545 recurrence rules supported
+// for recurring todos: FREQ (DAILY, WEEKLY, MONTHLY, YEARLY), INTERVAL,
+// BYDAY, COUNT and UNTIL.
+package rrule
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Frequency string
+
+// maxSkippedPeriods bounds the search for a period that contains a valid
+// occurrence, e.g. the next February 29th for a yearly rule.
+const maxSkippedPeriods = 48
+
+const (
+	FrequencyDaily   Frequency = "DAILY"
+	FrequencyWeekly  Frequency = "WEEKLY"
+	FrequencyMonthly Frequency = "MONTHLY"
+	FrequencyYearly  Frequency = "YEARLY"
+)
+
+// WeekdayNum is a BYDAY entry. Ordinal is only meaningful for MONTHLY rules,
+// e.g. 2TU (second Tuesday) or -1FR (last Friday); zero means every such day.
+type WeekdayNum struct {
+	Ordinal int
+	Weekday time.Weekday
+}
+
+type Rule struct {
+	Freq     Frequency
+	Interval int
+	ByDay    []WeekdayNum
+	Count    int
+	Until    *time.Time
+}
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// Parse parses a rule such as "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH". An optional
+// "RRULE:" prefix is accepted.
+func Parse(s string) (*Rule, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "RRULE:")
+	if s == "" {
+		return nil, fmt.Errorf("rule is empty")
+	}
+
+	rule := &Rule{Interval: 1}
+	seen := map[string]bool{}
+
+	for _, part := range strings.Split(s, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return nil, fmt.Errorf("invalid rule part %q", part)
+		}
+		key = strings.ToUpper(key)
+		if seen[key] {
+			return nil, fmt.Errorf("duplicate rule part %s", key)
+		}
+		seen[key] = true
+
+		switch key {
+		case "FREQ":
+			switch Frequency(strings.ToUpper(value)) {
+			case FrequencyDaily, FrequencyWeekly, FrequencyMonthly, FrequencyYearly:
+				rule.Freq = Frequency(strings.ToUpper(value))
+			default:
+				return nil, fmt.Errorf("unsupported FREQ %q", value)
+			}
+		case "INTERVAL":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("INTERVAL must be a positive integer")
+			}
+			rule.Interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("COUNT must be a positive integer")
+			}
+			rule.Count = n
+		case "UNTIL":
+			until, err := parseUntil(value)
+			if err != nil {
+				return nil, err
+			}
+			rule.Until = &until
+		case "BYDAY":
+			for _, day := range strings.Split(value, ",") {
+				wd, err := parseWeekdayNum(strings.ToUpper(day))
+				if err != nil {
+					return nil, err
+				}
+				rule.ByDay = append(rule.ByDay, wd)
+			}
+		default:
+			return nil, fmt.Errorf("unsupported rule part %s", key)
+		}
+	}
+
+	if rule.Freq == "" {
+		return nil, fmt.Errorf("FREQ is required")
+	}
+	if rule.Count > 0 && rule.Until != nil {
+		return nil, fmt.Errorf("COUNT and UNTIL cannot both be set")
+	}
+	if rule.Freq == FrequencyYearly && len(rule.ByDay) > 0 {
+		return nil, fmt.Errorf("BYDAY is not supported with FREQ=YEARLY")
+	}
+	for _, wd := range rule.ByDay {
+		if wd.Ordinal != 0 && rule.Freq != FrequencyMonthly {
+			return nil, fmt.Errorf("BYDAY ordinals are only supported with FREQ=MONTHLY")
+		}
+	}
+
+	return rule, nil
+}
+
+func parseUntil(value string) (time.Time, error) {
+	for _, layout := range []string{"20060102T150405Z", "20060102T150405", "20060102"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			if layout == "20060102" {
+				// A date-only UNTIL includes the whole day
+				t = t.Add(24*time.Hour - time.Second)
+			}
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid UNTIL %q", value)
+}
+
+func parseWeekdayNum(s string) (WeekdayNum, error) {
+	if len(s) < 2 {
+		return WeekdayNum{}, fmt.Errorf("invalid BYDAY %q", s)
+	}
+
+	wd, ok := weekdays[s[len(s)-2:]]
+	if !ok {
+		return WeekdayNum{}, fmt.Errorf("invalid BYDAY %q", s)
+	}
+
+	ordinal := 0
+	if prefix := s[:len(s)-2]; prefix != "" {
+		n, err := strconv.Atoi(prefix)
+		if err != nil || n == 0 || n < -5 || n > 5 {
+			return WeekdayNum{}, fmt.Errorf("invalid BYDAY %q", s)
+		}
+		ordinal = n
+	}
+
+	return WeekdayNum{Ordinal: ordinal, Weekday: wd}, nil
+}
+
+// String renders the rule in canonical form.
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, wd := range r.ByDay {
+			days[i] = wd.String()
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	return strings.Join(parts, ";")
+}
+
+func (w WeekdayNum) String() string {
+	for name, wd := range weekdays {
+		if wd == w.Weekday {
+			if w.Ordinal != 0 {
+				return strconv.Itoa(w.Ordinal) + name
+			}
+			return name
+		}
+	}
+	return ""
+}
+
+// Next returns the occurrence that follows prev, the scheduled date of the
+// occurrence with zero-based position index in the series. It returns false
+// once the rule's COUNT or UNTIL limit is reached. The time of day of prev is
+// kept for every generated occurrence.
+func (r *Rule) Next(prev time.Time, index int) (time.Time, bool) {
+	if r.Count > 0 && index+1 >= r.Count {
+		return time.Time{}, false
+	}
+
+	var next time.Time
+	switch r.Freq {
+	case FrequencyDaily:
+		next = r.nextDaily(prev)
+	case FrequencyWeekly:
+		next = r.nextWeekly(prev)
+	case FrequencyMonthly:
+		next = r.nextMonthly(prev)
+	case FrequencyYearly:
+		next = r.nextYearly(prev)
+	}
+
+	if next.IsZero() || (r.Until != nil && next.After(*r.Until)) {
+		return time.Time{}, false
+	}
+	return next, true
+}
+
+func (r *Rule) matchesDay(t time.Time) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+	for _, wd := range r.ByDay {
+		if wd.Weekday == t.Weekday() {
+			return true
+		}
+	}
+	return false
+}
+
+// nextDaily returns the zero time when no step ever lands on a BYDAY day,
+// which happens when INTERVAL is a multiple of 7.
+func (r *Rule) nextDaily(prev time.Time) time.Time {
+	// BYDAY narrows a daily rule. Weekdays repeat after at most seven steps,
+	// so a match is either among them or never comes.
+	next := prev
+	for range 7 {
+		next = next.AddDate(0, 0, r.Interval)
+		if r.matchesDay(next) {
+			return next
+		}
+	}
+	return time.Time{}
+}
+
+func (r *Rule) nextWeekly(prev time.Time) time.Time {
+	if len(r.ByDay) == 0 {
+		return prev.AddDate(0, 0, 7*r.Interval)
+	}
+
+	offsets := r.weekOffsets()
+	prevOffset := mondayOffset(prev.Weekday())
+	for _, offset := range offsets {
+		if offset > prevOffset {
+			return prev.AddDate(0, 0, offset-prevOffset)
+		}
+	}
+
+	// Jump to the first matching day of the next active week (weeks start on Monday)
+	weekStart := prev.AddDate(0, 0, -prevOffset)
+	return weekStart.AddDate(0, 0, 7*r.Interval+offsets[0])
+}
+
+// weekOffsets returns the sorted BYDAY days as offsets from Monday.
+func (r *Rule) weekOffsets() []int {
+	offsets := make([]int, 0, len(r.ByDay))
+	for _, wd := range r.ByDay {
+		offsets = append(offsets, mondayOffset(wd.Weekday))
+	}
+	sort.Ints(offsets)
+	return offsets
+}
+
+func mondayOffset(wd time.Weekday) int {
+	return (int(wd) + 6) % 7
+}
+
+func (r *Rule) nextMonthly(prev time.Time) time.Time {
+	if len(r.ByDay) == 0 {
+		// Months without the day (e.g. the 31st) are skipped, as in RFC 5545
+		for i := 1; i <= maxSkippedPeriods; i++ {
+			candidate := addMonthsNoOverflow(prev, i*r.Interval)
+			if !candidate.IsZero() {
+				return candidate
+			}
+		}
+		return time.Time{}
+	}
+
+	for _, day := range r.monthDays(prev) {
+		if day.After(prev) {
+			return day
+		}
+	}
+	for i := 1; i <= maxSkippedPeriods; i++ {
+		month := firstOfMonth(prev).AddDate(0, i*r.Interval, 0)
+		if days := r.monthDays(month); len(days) > 0 {
+			return days[0]
+		}
+	}
+	return time.Time{}
+}
+
+// monthDays expands BYDAY within the month of t, keeping t's time of day.
+func (r *Rule) monthDays(t time.Time) []time.Time {
+	first := firstOfMonth(t)
+	daysInMonth := first.AddDate(0, 1, -1).Day()
+
+	var days []time.Time
+	for d := 1; d <= daysInMonth; d++ {
+		day := first.AddDate(0, 0, d-1)
+		for _, wd := range r.ByDay {
+			if wd.Weekday != day.Weekday() {
+				continue
+			}
+			nth := (d-1)/7 + 1
+			nthFromEnd := -((daysInMonth-d)/7 + 1)
+			if wd.Ordinal == 0 || wd.Ordinal == nth || wd.Ordinal == nthFromEnd {
+				days = append(days, day)
+				break
+			}
+		}
+	}
+	return days
+}
+
+func firstOfMonth(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+}
+
+func (r *Rule) nextYearly(prev time.Time) time.Time {
+	// Feb 29 only recurs in leap years
+	for i := 1; i <= maxSkippedPeriods; i++ {
+		candidate := addMonthsNoOverflow(prev, 12*i*r.Interval)
+		if !candidate.IsZero() {
+			return candidate
+		}
+	}
+	return time.Time{}
+}
+
+// addMonthsNoOverflow adds months keeping the day of month, returning the zero
+// time when that day doesn't exist in the target month.
+func addMonthsNoOverflow(t time.Time, months int) time.Time {
+	candidate := time.Date(t.Year(), t.Month()+time.Month(months), t.Day(),
+		t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	if candidate.Day() != t.Day() {
+		return time.Time{}
+	}
+	return candidate
+}
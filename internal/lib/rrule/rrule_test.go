@@ -0,0 +1,115 @@
+package rrule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 9, 30, 0, 0, time.UTC)
+}
+
+func TestNext(t *testing.T) {
+	tests := []struct {
+		name  string
+		rule  string
+		prev  time.Time
+		index int
+		want  time.Time // zero when the series ends
+	}{
+		// DAILY
+		{"daily", "FREQ=DAILY", date(2026, 1, 1), 0, date(2026, 1, 2)},
+		{"daily interval", "FREQ=DAILY;INTERVAL=3", date(2026, 1, 1), 0, date(2026, 1, 4)},
+		{"daily byday next day", "FREQ=DAILY;BYDAY=MO,WE,FR", date(2026, 1, 1), 0, date(2026, 1, 2)},
+		{"daily byday over the weekend", "FREQ=DAILY;BYDAY=MO,WE,FR", date(2026, 1, 2), 0, date(2026, 1, 5)},
+		{"daily interval byday", "FREQ=DAILY;INTERVAL=2;BYDAY=MO", date(2026, 1, 1), 0, date(2026, 1, 5)},
+		{"daily weekly interval on byday", "FREQ=DAILY;INTERVAL=14;BYDAY=TU", date(2026, 1, 6), 0, date(2026, 1, 20)},
+		{"daily weekly interval never on byday", "FREQ=DAILY;INTERVAL=7;BYDAY=MO", date(2026, 1, 6), 0, time.Time{}},
+
+		// WEEKLY
+		{"weekly", "FREQ=WEEKLY", date(2026, 1, 1), 0, date(2026, 1, 8)},
+		{"weekly byday same week", "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH", date(2026, 1, 5), 0, date(2026, 1, 8)},
+		{"weekly byday next active week", "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH", date(2026, 1, 8), 0, date(2026, 1, 19)},
+		{"weekly byday from an unlisted day", "FREQ=WEEKLY;BYDAY=MO", date(2026, 1, 1), 0, date(2026, 1, 5)},
+
+		// MONTHLY
+		{"monthly", "FREQ=MONTHLY", date(2026, 1, 15), 0, date(2026, 2, 15)},
+		{"monthly skips short months", "FREQ=MONTHLY", date(2026, 1, 31), 0, date(2026, 3, 31)},
+		{"monthly interval", "FREQ=MONTHLY;INTERVAL=3", date(2026, 1, 15), 0, date(2026, 4, 15)},
+		{"monthly second tuesday", "FREQ=MONTHLY;BYDAY=2TU", date(2026, 1, 13), 0, date(2026, 2, 10)},
+		{"monthly last friday", "FREQ=MONTHLY;BYDAY=-1FR", date(2026, 1, 30), 0, date(2026, 2, 27)},
+		{"monthly fifth monday skips months", "FREQ=MONTHLY;BYDAY=5MO", date(2026, 3, 30), 0, date(2026, 6, 29)},
+		{"monthly every tuesday", "FREQ=MONTHLY;BYDAY=TU", date(2026, 1, 27), 0, date(2026, 2, 3)},
+
+		// YEARLY
+		{"yearly", "FREQ=YEARLY", date(2026, 3, 15), 0, date(2027, 3, 15)},
+		{"yearly interval", "FREQ=YEARLY;INTERVAL=2", date(2026, 6, 1), 0, date(2028, 6, 1)},
+		{"yearly leap day", "FREQ=YEARLY", date(2024, 2, 29), 0, date(2028, 2, 29)},
+
+		// COUNT
+		{"count not reached", "FREQ=DAILY;COUNT=3", date(2026, 1, 2), 1, date(2026, 1, 3)},
+		{"count reached", "FREQ=DAILY;COUNT=3", date(2026, 1, 3), 2, time.Time{}},
+		{"count of one", "FREQ=WEEKLY;BYDAY=MO;COUNT=1", date(2026, 1, 5), 0, time.Time{}},
+
+		// UNTIL
+		{"until date includes the day", "FREQ=WEEKLY;UNTIL=20260115", date(2026, 1, 8), 0, date(2026, 1, 15)},
+		{"until date passed", "FREQ=WEEKLY;UNTIL=20260115", date(2026, 1, 15), 1, time.Time{}},
+		{"until time", "FREQ=DAILY;UNTIL=20260102T090000Z", date(2026, 1, 1), 0, time.Time{}},
+		{"until past skipped month", "FREQ=MONTHLY;UNTIL=20260301T000000Z", date(2026, 1, 31), 0, time.Time{}},
+		{"until with byday", "FREQ=MONTHLY;BYDAY=-1FR;UNTIL=20260228", date(2026, 1, 30), 0, date(2026, 2, 27)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := Parse(tt.rule)
+			require.NoError(t, err)
+
+			got, ok := rule.Next(tt.prev, tt.index)
+			if tt.want.IsZero() {
+				assert.False(t, ok, "series should end, got %s", got)
+				assert.True(t, got.IsZero())
+				return
+			}
+			require.True(t, ok, "series ended early")
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		rule    string
+		want    string
+		wantErr string
+	}{
+		{rule: "RRULE:FREQ=weekly;byday=mo,th", want: "FREQ=WEEKLY;BYDAY=MO,TH"},
+		{rule: "FREQ=MONTHLY;INTERVAL=1;BYDAY=-1FR;COUNT=5", want: "FREQ=MONTHLY;BYDAY=-1FR;COUNT=5"},
+		{rule: "FREQ=DAILY;UNTIL=20260115", want: "FREQ=DAILY;UNTIL=20260115T235959Z"},
+		{rule: "", wantErr: "rule is empty"},
+		{rule: "INTERVAL=2", wantErr: "FREQ is required"},
+		{rule: "FREQ=HOURLY", wantErr: "unsupported FREQ"},
+		{rule: "FREQ=DAILY;INTERVAL=0", wantErr: "INTERVAL must be a positive integer"},
+		{rule: "FREQ=DAILY;COUNT=2;UNTIL=20260115", wantErr: "COUNT and UNTIL cannot both be set"},
+		{rule: "FREQ=DAILY;FREQ=WEEKLY", wantErr: "duplicate rule part FREQ"},
+		{rule: "FREQ=YEARLY;BYDAY=MO", wantErr: "BYDAY is not supported with FREQ=YEARLY"},
+		{rule: "FREQ=WEEKLY;BYDAY=2MO", wantErr: "BYDAY ordinals are only supported with FREQ=MONTHLY"},
+		{rule: "FREQ=MONTHLY;BYDAY=6MO", wantErr: "invalid BYDAY"},
+		{rule: "FREQ=DAILY;BYSETPOS=1", wantErr: "unsupported rule part BYSETPOS"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			rule, err := Parse(tt.rule)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, rule.String())
+		})
+	}
+}
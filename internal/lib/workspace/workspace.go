@@ -0,0 +1,25 @@
+// Package workspace carries the Clerk organization a request runs in, so
+// repositories can scope queries to it without every signature growing an
+// extra argument.
+package workspace
+
+import "context"
+
+type orgIDKey struct{}
+
+// WithOrgID returns a context scoped to the organization's shared workspace.
+// An empty orgID leaves the context in the personal workspace.
+func WithOrgID(ctx context.Context, orgID string) context.Context {
+	if orgID == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, orgIDKey{}, orgID)
+}
+
+// OrgID returns the active organization, or nil in the personal workspace.
+func OrgID(ctx context.Context) *string {
+	if orgID, ok := ctx.Value(orgIDKey{}).(string); ok {
+		return &orgID
+	}
+	return nil
+}
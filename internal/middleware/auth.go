@@ -9,6 +9,7 @@ import (
 	clerkhttp "github.com/clerk/clerk-sdk-go/v2/http"
 	"github.com/labstack/echo/v4"
 	"github.com/uttam282005/tasker/internal/errs"
+	"github.com/uttam282005/tasker/internal/lib/workspace"
 	"github.com/uttam282005/tasker/internal/server"
 )
 
@@ -58,9 +59,15 @@ func (auth *AuthMiddleware) RequireAuth(next echo.HandlerFunc) echo.HandlerFunc
 			return errs.NewUnauthorizedError("Unauthorized", false)
 		}
 
-		c.Set("user_id", claims.Subject)
-		c.Set("user_role", claims.ActiveOrganizationRole)
-		c.Set("permissions", claims.Claims.ActiveOrganizationPermissions)
+		c.Set(UserIDKey, claims.Subject)
+		c.Set(UserRoleKey, claims.ActiveOrganizationRole)
+		c.Set(OrgIDKey, claims.ActiveOrganizationID)
+		c.Set(PermissionsKey, claims.Claims.ActiveOrganizationPermissions)
+
+		// Repositories scope their queries to the organization's workspace
+		c.SetRequest(c.Request().WithContext(
+			workspace.WithOrgID(c.Request().Context(), claims.ActiveOrganizationID),
+		))
 
 		auth.server.Logger.Info().
 			Str("function", "RequireAuth").
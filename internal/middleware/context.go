@@ -11,9 +11,11 @@ import (
 )
 
 const (
-	UserIDKey   = "user_id"
-	UserRoleKey = "user_role"
-	LoggerKey   = "logger"
+	UserIDKey      = "user_id"
+	UserRoleKey    = "user_role"
+	OrgIDKey       = "org_id"
+	PermissionsKey = "permissions"
+	LoggerKey      = "logger"
 )
 
 type ContextEnhancer struct {
@@ -52,6 +54,10 @@ func (ce *ContextEnhancer) EnhanceContext() echo.MiddlewareFunc {
 				contextLogger = contextLogger.With().Str("user_role", userRole).Logger()
 			}
 
+			if orgID := GetOrgID(c); orgID != "" {
+				contextLogger = contextLogger.With().Str("org_id", orgID).Logger()
+			}
+
 			// Store the enhanced logger in context
 			c.Set(LoggerKey, &contextLogger)
 
@@ -87,6 +93,23 @@ func GetUserID(c echo.Context) string {
 	return ""
 }
 
+// GetOrgID returns the active Clerk organization, or "" when the request runs
+// in the user's personal workspace.
+func GetOrgID(c echo.Context) string {
+	if orgID, ok := c.Get(OrgIDKey).(string); ok {
+		return orgID
+	}
+	return ""
+}
+
+// GetPermissions returns the user's permissions in the active organization.
+func GetPermissions(c echo.Context) []string {
+	if permissions, ok := c.Get(PermissionsKey).([]string); ok {
+		return permissions
+	}
+	return nil
+}
+
 func GetLogger(c echo.Context) *zerolog.Logger {
 	if logger, ok := c.Get(LoggerKey).(*zerolog.Logger); ok {
 		return logger
@@ -8,6 +8,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
 	"github.com/uttam282005/tasker/internal/errs"
+	"github.com/uttam282005/tasker/internal/lib/etag"
 	"github.com/uttam282005/tasker/internal/server"
 	"github.com/uttam282005/tasker/internal/sqlerr"
 )
@@ -25,6 +26,8 @@ func NewGlobalMiddlewares(s *server.Server) *GlobalMiddlewares {
 func (global *GlobalMiddlewares) CORS() echo.MiddlewareFunc {
 	return middleware.CORSWithConfig(middleware.CORSConfig{
 		AllowOrigins: global.server.Config.Server.CORSAllowedOrigins,
+		// Let browser clients read the tag they send back in If-Match
+		ExposeHeaders: []string{etag.HeaderETag},
 	})
 }
 
@@ -100,6 +103,22 @@ func (global *GlobalMiddlewares) Secure() echo.MiddlewareFunc {
 }
 
 func (global *GlobalMiddlewares) GlobalErrorHandler(err error, c echo.Context) {
+	// A failed If-Match is answered with the current representation rather
+	// than an error body, so the client can reconcile without refetching
+	var preconditionErr *etag.PreconditionFailedError
+	if errors.As(err, &preconditionErr) {
+		logger := *GetLogger(c)
+		logger.Warn().
+			Int("status", http.StatusPreconditionFailed).
+			Int("current_version", preconditionErr.Current.ETagVersion()).
+			Msg("precondition failed")
+
+		if !c.Response().Committed {
+			_ = etag.Render(c, http.StatusPreconditionFailed, preconditionErr.Current)
+		}
+		return
+	}
+
 	// First try to handle database errors and convert them to appropriate HTTP errors
 	originalErr := err
 
@@ -0,0 +1,304 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
+	"github.com/uttam282005/tasker/internal/errs"
+	"github.com/uttam282005/tasker/internal/server"
+)
+
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotencyReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+	// idempotencyLockTTL bounds how long a request that never finishes, for
+	// instance because the instance serving it died, blocks its key.
+	idempotencyLockTTL = 5 * time.Minute
+	// idempotencyTTL is how long a finished response is replayed for.
+	idempotencyTTL = 24 * time.Hour
+	// idempotencyMemoryLimit is the largest body kept in memory while it is
+	// fingerprinted. Larger ones, like uploads, go to a temporary file.
+	idempotencyMemoryLimit = 1 << 20
+)
+
+type idempotencyState string
+
+const (
+	idempotencyStateInProgress idempotencyState = "in_progress"
+	idempotencyStateCompleted  idempotencyState = "completed"
+)
+
+// idempotencyRecord is what's kept in Redis for each user and key.
+type idempotencyRecord struct {
+	State       idempotencyState `json:"state"`
+	Fingerprint string           `json:"fingerprint"`
+	Status      int              `json:"status,omitempty"`
+	ContentType string           `json:"contentType,omitempty"`
+	Body        []byte           `json:"body,omitempty"`
+}
+
+type IdempotencyMiddleware struct {
+	server *server.Server
+}
+
+func NewIdempotencyMiddleware(s *server.Server) *IdempotencyMiddleware {
+	return &IdempotencyMiddleware{
+		server: s,
+	}
+}
+
+// Idempotent makes a route safe to retry with an Idempotency-Key header. The
+// first request with a key runs normally and its response is stored; a retry
+// with the same key gets that response replayed. A retry that arrives while
+// the first is still running, or that reuses the key for a different
+// request, is rejected with 409. Requests without the header are unaffected.
+// It must run after RequireAuth, since keys are scoped to the user.
+func (m *IdempotencyMiddleware) Idempotent(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		key := c.Request().Header.Get(IdempotencyKeyHeader)
+		if key == "" {
+			return next(c)
+		}
+
+		if len(key) > maxIdempotencyKeyLength {
+			code := "INVALID_IDEMPOTENCY_KEY"
+			return errs.NewBadRequestError("Idempotency-Key must be at most 255 characters", false, &code, nil, nil)
+		}
+
+		logger := GetLogger(c).With().Str("idempotency_key", key).Logger()
+		ctx := c.Request().Context()
+
+		fingerprint, cleanup, err := fingerprintRequest(c)
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+
+		redisKey := "idempotency:" + GetUserID(c) + ":" + key
+
+		lock, err := json.Marshal(idempotencyRecord{
+			State:       idempotencyStateInProgress,
+			Fingerprint: fingerprint,
+		})
+		if err != nil {
+			return err
+		}
+
+		// A key freed by a failed first request between SetNX and Get is
+		// reserved on the second pass
+		for attempt := 0; ; attempt++ {
+			acquired, err := m.server.Redis.SetNX(ctx, redisKey, lock, idempotencyLockTTL).Result()
+			if err != nil {
+				// Like the rest of the server, keep working without Redis
+				logger.Error().Err(err).Msg("failed to reserve idempotency key, handling request without it")
+				return next(c)
+			}
+			if acquired {
+				break
+			}
+
+			err = m.replay(c, redisKey, fingerprint)
+			if !errors.Is(err, redis.Nil) || attempt > 0 {
+				return err
+			}
+		}
+
+		// The outcome is stored even if the client has gone away meanwhile
+		storeCtx := context.WithoutCancel(ctx)
+
+		completed := false
+		defer func() {
+			// Free the key when there is nothing worth replaying, so the
+			// client can retry
+			if !completed {
+				if err := m.server.Redis.Del(storeCtx, redisKey).Err(); err != nil {
+					logger.Error().Err(err).Msg("failed to release idempotency key")
+				}
+			}
+		}()
+
+		recorder := &responseRecorder{ResponseWriter: c.Response().Writer}
+		c.Response().Writer = recorder
+
+		// Render errors here rather than in the router, so the error response
+		// is captured as well
+		if err := next(c); err != nil {
+			c.Error(err)
+		}
+
+		c.Response().Writer = recorder.ResponseWriter
+
+		// Server errors are usually transient and are left retryable
+		status := c.Response().Status
+		if status >= http.StatusInternalServerError {
+			return nil
+		}
+
+		record, err := json.Marshal(idempotencyRecord{
+			State:       idempotencyStateCompleted,
+			Fingerprint: fingerprint,
+			Status:      status,
+			ContentType: c.Response().Header().Get(echo.HeaderContentType),
+			Body:        recorder.body.Bytes(),
+		})
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to encode idempotent response")
+			return nil
+		}
+
+		if err := m.server.Redis.Set(storeCtx, redisKey, record, idempotencyTTL).Err(); err != nil {
+			logger.Error().Err(err).Msg("failed to store idempotent response")
+			return nil
+		}
+		completed = true
+
+		return nil
+	}
+}
+
+// replay answers a request whose key is already taken. It returns redis.Nil
+// when the key was released before it could be read.
+func (m *IdempotencyMiddleware) replay(c echo.Context, redisKey, fingerprint string) error {
+	raw, err := m.server.Redis.Get(c.Request().Context(), redisKey).Bytes()
+	if err != nil {
+		return err
+	}
+
+	var record idempotencyRecord
+	if err := json.Unmarshal(raw, &record); err != nil {
+		return err
+	}
+
+	if record.Fingerprint != fingerprint {
+		code := "IDEMPOTENCY_KEY_REUSED"
+		return errs.NewConflictError("Idempotency-Key was already used for a different request", false, &code)
+	}
+
+	if record.State == idempotencyStateInProgress {
+		code := "IDEMPOTENCY_KEY_IN_USE"
+		return errs.NewConflictError("A request with this Idempotency-Key is still being processed", false, &code)
+	}
+
+	c.Response().Header().Set(IdempotencyReplayedHeader, "true")
+	return c.Blob(record.Status, record.ContentType, record.Body)
+}
+
+// fingerprintRequest hashes the method, route and body, so a key reused for
+// a different request can be told apart from a retry. The body is hashed as
+// it is spooled and restored for the handler; the returned function removes
+// the spooled copy once the request is done.
+func fingerprintRequest(c echo.Context) (string, func(), error) {
+	hash := sha256.New()
+	hash.Write([]byte(c.Request().Method + " " + c.Request().URL.Path + "\n"))
+
+	// Clients pick a new multipart boundary on every attempt, so uploads are
+	// compared part by part instead of byte for byte
+	mediaType, params, err := mime.ParseMediaType(c.Request().Header.Get(echo.HeaderContentType))
+	isMultipart := err == nil && strings.HasPrefix(mediaType, "multipart/")
+
+	var bodyHash io.Writer = hash
+	if isMultipart {
+		bodyHash = io.Discard
+	}
+
+	body, cleanup, err := spoolBody(c.Request().Body, bodyHash)
+	if err != nil {
+		return "", nil, errs.NewBadRequestError("Failed to read request body", false, nil, nil, nil)
+	}
+
+	if isMultipart {
+		reader := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := reader.NextPart()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				cleanup()
+				return "", nil, errs.NewBadRequestError("Malformed multipart body", false, nil, nil, nil)
+			}
+			hash.Write([]byte(part.FormName() + "\n" + part.FileName() + "\n"))
+			if _, err := io.Copy(hash, part); err != nil {
+				cleanup()
+				return "", nil, errs.NewBadRequestError("Malformed multipart body", false, nil, nil, nil)
+			}
+		}
+
+		if _, err := body.Seek(0, io.SeekStart); err != nil {
+			cleanup()
+			return "", nil, err
+		}
+	}
+
+	c.Request().Body = io.NopCloser(body)
+
+	return hex.EncodeToString(hash.Sum(nil)), cleanup, nil
+}
+
+// spoolBody copies body somewhere it can be read again, writing it to hash
+// on the way: memory while it's small, a temporary file beyond
+// idempotencyMemoryLimit. The returned function removes the file.
+func spoolBody(body io.Reader, hash io.Writer) (io.ReadSeeker, func(), error) {
+	var buf bytes.Buffer
+	_, err := io.CopyN(io.MultiWriter(&buf, hash), body, idempotencyMemoryLimit+1)
+	if errors.Is(err, io.EOF) {
+		return bytes.NewReader(buf.Bytes()), func() {}, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	file, err := os.CreateTemp("", "idempotency-*")
+	if err != nil {
+		return nil, nil, err
+	}
+	cleanup := func() {
+		file.Close()
+		os.Remove(file.Name())
+	}
+
+	if _, err := file.Write(buf.Bytes()); err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	if _, err := io.Copy(io.MultiWriter(file, hash), body); err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+
+	return file, cleanup, nil
+}
+
+// responseRecorder keeps a copy of everything written to the client.
+type responseRecorder struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *responseRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
@@ -0,0 +1,98 @@
+package activity
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/google/uuid"
+	"github.com/uttam282005/tasker/internal/model"
+)
+
+type EntityType string
+
+const (
+	EntityTypeTodo       EntityType = "todo"
+	EntityTypeComment    EntityType = "comment"
+	EntityTypeAttachment EntityType = "attachment"
+	EntityTypeCategory   EntityType = "category"
+)
+
+type Action string
+
+const (
+	ActionCreated  Action = "created"
+	ActionUpdated  Action = "updated"
+	ActionDeleted  Action = "deleted"
+	ActionRestored Action = "restored"
+)
+
+// Change holds a field's JSON value before and after a mutation. From is nil
+// for created entities and To is nil for deleted ones.
+type Change struct {
+	From any `json:"from"`
+	To   any `json:"to"`
+}
+
+// Changes is keyed by the field's JSON name.
+type Changes map[string]Change
+
+// Activity is one entry in the audit trail. TodoID is nil for entities that
+// don't belong to a todo, such as categories.
+type Activity struct {
+	model.BaseWithID
+	model.BaseWithCreatedAt
+	TodoID     *uuid.UUID `json:"todoId" db:"todo_id"`
+	UserID     string     `json:"userId" db:"user_id"`
+	ActorID    string     `json:"actorId" db:"actor_id"`
+	EntityType EntityType `json:"entityType" db:"entity_type"`
+	EntityID   uuid.UUID  `json:"entityId" db:"entity_id"`
+	Action     Action     `json:"action" db:"action"`
+	Changes    Changes    `json:"changes" db:"changes"`
+}
+
+// ignoredFields change on every write and would only add noise.
+var ignoredFields = map[string]bool{
+	"createdAt": true,
+	"updatedAt": true,
+	"version":   true,
+}
+
+// Diff compares the JSON representation of two versions of an entity and
+// returns the fields that differ. Pass nil as before for a created entity and
+// nil as after for a deleted one.
+func Diff(before, after any) Changes {
+	beforeFields := jsonFields(before)
+	afterFields := jsonFields(after)
+
+	changes := Changes{}
+	for name, value := range afterFields {
+		if ignoredFields[name] {
+			continue
+		}
+		if previous, ok := beforeFields[name]; !ok || !reflect.DeepEqual(previous, value) {
+			changes[name] = Change{From: previous, To: value}
+		}
+	}
+	for name, previous := range beforeFields {
+		if _, ok := afterFields[name]; !ok && !ignoredFields[name] {
+			changes[name] = Change{From: previous}
+		}
+	}
+
+	return changes
+}
+
+func jsonFields(v any) map[string]any {
+	fields := map[string]any{}
+	if v == nil || (reflect.ValueOf(v).Kind() == reflect.Pointer && reflect.ValueOf(v).IsNil()) {
+		return fields
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fields
+	}
+	_ = json.Unmarshal(data, &fields)
+
+	return fields
+}
@@ -0,0 +1,36 @@
+package activity
+
+import (
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+/*
+ * GET /api/v1/todos/:id/activity -> audit trail of a todo, newest first
+ */
+
+type GetTodoActivityQuery struct {
+	TodoID uuid.UUID `param:"id" validate:"required,uuid"`
+	Page   *int      `query:"page" validate:"omitempty,min=1"`
+	Limit  *int      `query:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+func (q *GetTodoActivityQuery) Validate() error {
+	validate := validator.New()
+
+	if err := validate.Struct(q); err != nil {
+		return err
+	}
+
+	// Set defaults for pagination
+	if q.Page == nil {
+		defaultPage := 1
+		q.Page = &defaultPage
+	}
+	if q.Limit == nil {
+		defaultLimit := 20
+		q.Limit = &defaultLimit
+	}
+
+	return nil
+}
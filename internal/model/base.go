@@ -31,3 +31,16 @@ type PaginatedResponse[T any] struct {
 	Total      int `json:"total"`
 	TotalPages int `json:"totalPages"`
 }
+
+// CursorPaginatedResponse is a page of a list that can be paged by cursor or
+// by page number. Page and TotalPages are only set when paging by number;
+// Total only when it was counted.
+type CursorPaginatedResponse[T any] struct {
+	Data       []T     `json:"data"`
+	Limit      int     `json:"limit"`
+	Page       *int    `json:"page,omitempty"`
+	Total      *int    `json:"total,omitempty"`
+	TotalPages *int    `json:"totalPages,omitempty"`
+	NextCursor *string `json:"nextCursor"`
+	PrevCursor *string `json:"prevCursor"`
+}
@@ -0,0 +1,49 @@
+package calendar
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uttam282005/tasker/internal/model"
+	"github.com/uttam282005/tasker/internal/model/todo"
+)
+
+// Component is the iCalendar component todos are rendered as.
+type Component string
+
+const (
+	// ComponentTodo renders todos as tasks, with a due date and status
+	ComponentTodo Component = "vtodo"
+	// ComponentEvent renders todos as events at their due date, for
+	// calendars that don't show tasks
+	ComponentEvent Component = "vevent"
+)
+
+// Feed is a calendar subscription to the due todos of its owner, in the
+// workspace it was created in. The token is only returned once, when the
+// feed is created.
+type Feed struct {
+	model.Base
+	UserID        string      `json:"userId" db:"user_id"`
+	OrgID         *string     `json:"orgId" db:"org_id"`
+	Name          string      `json:"name" db:"name"`
+	TokenHash     string      `json:"-" db:"token_hash"`
+	Component     Component   `json:"component" db:"component"`
+	CategoryIDs   []uuid.UUID `json:"categoryIds" db:"category_ids"`
+	LastFetchedAt *time.Time  `json:"lastFetchedAt" db:"last_fetched_at"`
+}
+
+// CreatedFeed is returned when a feed is created and is the only response
+// that carries its token.
+type CreatedFeed struct {
+	Feed
+	Token string `json:"token"`
+	// URL is the address calendars subscribe to
+	URL string `json:"url"`
+}
+
+// Todo is a due todo as a feed renders it.
+type Todo struct {
+	todo.Todo
+	CategoryName *string `db:"category_name"`
+}
@@ -0,0 +1,78 @@
+package calendar
+
+import (
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+// ------------------------------------------------------------
+
+type CreateFeedPayload struct {
+	Name      string    `json:"name" validate:"required,min=1,max=100"`
+	Component Component `json:"component" validate:"omitempty,oneof=vtodo vevent"`
+	// CategoryIDs limits the feed to todos in these categories
+	CategoryIDs []uuid.UUID `json:"categoryIds" validate:"omitempty,max=50,unique"`
+}
+
+func (p *CreateFeedPayload) Validate() error {
+	validate := validator.New()
+
+	if err := validate.Struct(p); err != nil {
+		return err
+	}
+
+	if p.Component == "" {
+		p.Component = ComponentEvent
+	}
+	if p.CategoryIDs == nil {
+		p.CategoryIDs = []uuid.UUID{}
+	}
+
+	return nil
+}
+
+// ------------------------------------------------------------
+
+type GetFeedsPayload struct{}
+
+func (p *GetFeedsPayload) Validate() error {
+	return nil
+}
+
+// ------------------------------------------------------------
+
+type UpdateFeedPayload struct {
+	ID          uuid.UUID   `param:"id" validate:"required,uuid"`
+	Name        *string     `json:"name" validate:"omitempty,min=1,max=100"`
+	Component   *Component  `json:"component" validate:"omitempty,oneof=vtodo vevent"`
+	CategoryIDs []uuid.UUID `json:"categoryIds" validate:"omitempty,max=50,unique"`
+}
+
+func (p *UpdateFeedPayload) Validate() error {
+	validate := validator.New()
+	return validate.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+type DeleteFeedPayload struct {
+	ID uuid.UUID `param:"id" validate:"required,uuid"`
+}
+
+func (p *DeleteFeedPayload) Validate() error {
+	validate := validator.New()
+	return validate.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+// GetFeedQuery reads a feed by its token, with or without the .ics suffix
+// calendars expect.
+type GetFeedQuery struct {
+	Token string `param:"token" validate:"required,max=255"`
+}
+
+func (q *GetFeedQuery) Validate() error {
+	validate := validator.New()
+	return validate.Struct(q)
+}
@@ -1,12 +1,24 @@
 package category
 
-import "github.com/uttam282005/tasker/internal/model"
+import (
+	"time"
+
+	"github.com/uttam282005/tasker/internal/model"
+)
 
 type Category struct {
 	model.Base
 
-	UserID      string  `json:"userId" db:"user_id"`
-	Name        string  `json:"name" db:"name"`
-	Color       string  `json:"color" db:"color"`
-	Description *string `json:"description" db:"description"`
+	UserID      string     `json:"userId" db:"user_id"`
+	OrgID       *string    `json:"orgId" db:"org_id"`
+	Name        string     `json:"name" db:"name"`
+	Color       string     `json:"color" db:"color"`
+	Description *string    `json:"description" db:"description"`
+	DeletedAt   *time.Time `json:"deletedAt" db:"deleted_at"`
+	Version     int        `json:"version" db:"version"`
+}
+
+// ETagVersion implements etag.Versioned.
+func (c Category) ETagVersion() int {
+	return c.Version
 }
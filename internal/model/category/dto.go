@@ -1,8 +1,12 @@
 package category
 
 import (
+	"encoding/json"
+
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
+	"github.com/uttam282005/tasker/internal/lib/mergepatch"
+	"github.com/uttam282005/tasker/internal/validation"
 )
 
 // ------------------------------------------------------------
@@ -24,6 +28,10 @@ type UpdateCategoryPayload struct {
 	Name        *string   `json:"name" validate:"omitempty,min=1,max=100"`
 	Color       *string   `json:"color" validate:"omitempty,hexcolor"`
 	Description *string   `json:"description" validate:"omitempty,max=255"`
+	IfMatch     *string   `header:"If-Match" json:"-"`
+	// ClearDescription sets the description to null. Only merge patches set
+	// it, as a JSON body can't tell null from a missing member.
+	ClearDescription bool `json:"-"`
 }
 
 func (p *UpdateCategoryPayload) Validate() error {
@@ -33,12 +41,74 @@ func (p *UpdateCategoryPayload) Validate() error {
 
 // ------------------------------------------------------------
 
+// PatchCategoryPayload is a JSON Merge Patch (RFC 7396) for a category.
+// Members work as in UpdateCategoryPayload, except that a null description
+// clears it. Name and color can't be null.
+type PatchCategoryPayload struct {
+	UpdateCategoryPayload
+
+	// nulls holds the members that can't be cleared but were set to null
+	nulls []string
+}
+
+func (p *PatchCategoryPayload) UnmarshalJSON(data []byte) error {
+	members, err := mergepatch.Members(data)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(data, &p.UpdateCategoryPayload); err != nil {
+		return err
+	}
+
+	for _, name := range []string{"name", "color", "description"} {
+		if value, ok := members[name]; !ok || !mergepatch.IsNull(value) {
+			continue
+		}
+
+		if name == "description" {
+			p.ClearDescription = true
+		} else {
+			p.nulls = append(p.nulls, name)
+		}
+	}
+
+	return nil
+}
+
+func (p *PatchCategoryPayload) Validate() error {
+	if len(p.nulls) > 0 {
+		var validationErrors validation.CustomValidationErrors
+		for _, name := range p.nulls {
+			validationErrors = append(validationErrors, validation.CustomValidationError{
+				Field:   name,
+				Message: "cannot be null",
+			})
+		}
+		return validationErrors
+	}
+
+	return p.UpdateCategoryPayload.Validate()
+}
+
+// ------------------------------------------------------------
+
 type GetCategoriesQuery struct {
 	Page   *int    `query:"page" validate:"omitempty,min=1"`
 	Limit  *int    `query:"limit" validate:"omitempty,min=1,max=100"`
 	Sort   *string `query:"sort" validate:"omitempty,oneof=created_at updated_at name"`
 	Order  *string `query:"order" validate:"omitempty,oneof=asc desc"`
 	Search *string `query:"search" validate:"omitempty,min=1"`
+
+	// Cursor continues the list from a nextCursor or prevCursor, in place
+	// of page, and implies cursor pagination
+	Cursor *string `query:"cursor" validate:"omitempty,max=1024"`
+	// Pagination picks paging by page number (offset, the default) or by
+	// cursor, where the first page is requested without a cursor
+	Pagination *string `query:"pagination" validate:"omitempty,oneof=offset cursor"`
+	// Count adds the total to the response. It defaults to true when paging
+	// by page number and false when paging by cursor.
+	Count *bool `query:"count"`
 }
 
 func (q *GetCategoriesQuery) Validate() error {
@@ -48,8 +118,26 @@ func (q *GetCategoriesQuery) Validate() error {
 		return err
 	}
 
+	if q.Cursor != nil && (q.Page != nil || (q.Pagination != nil && *q.Pagination == "offset")) {
+		return validation.CustomValidationErrors{
+			{Field: "cursor", Message: "cannot be combined with page or offset pagination"},
+		}
+	}
+
+	if q.Pagination == nil {
+		defaultPagination := "offset"
+		if q.Cursor != nil {
+			defaultPagination = "cursor"
+		}
+		q.Pagination = &defaultPagination
+	}
+	if q.Count == nil {
+		defaultCount := *q.Pagination == "offset"
+		q.Count = &defaultCount
+	}
+
 	// Set defaults
-	if q.Page == nil {
+	if q.Page == nil && *q.Pagination == "offset" {
 		defaultPage := 1
 		q.Page = &defaultPage
 	}
@@ -69,11 +157,64 @@ func (q *GetCategoriesQuery) Validate() error {
 	return nil
 }
 
+// ------------------------------------------------------------
+
+type GetCategoryByIDPayload struct {
+	ID          uuid.UUID `param:"id" validate:"required,uuid"`
+	IfNoneMatch *string   `header:"If-None-Match" json:"-"`
+}
+
+func (p *GetCategoryByIDPayload) Validate() error {
+	validate := validator.New()
+	return validate.Struct(p)
+}
+
+// ------------------------------------------------------------
+
 type DeleteCategoryPayload struct {
-	ID uuid.UUID `param:"id" validate:"required,uuid"`
+	ID      uuid.UUID `param:"id" validate:"required,uuid"`
+	IfMatch *string   `header:"If-Match" json:"-"`
 }
 
 func (p *DeleteCategoryPayload) Validate() error {
 	validate := validator.New()
 	return validate.Struct(p)
 }
+
+// ------------------------------------------------------------
+
+type GetTrashedCategoriesQuery struct {
+	Page  *int `query:"page" validate:"omitempty,min=1"`
+	Limit *int `query:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+func (q *GetTrashedCategoriesQuery) Validate() error {
+	validate := validator.New()
+
+	if err := validate.Struct(q); err != nil {
+		return err
+	}
+
+	// Set defaults for pagination
+	if q.Page == nil {
+		defaultPage := 1
+		q.Page = &defaultPage
+	}
+	if q.Limit == nil {
+		defaultLimit := 20
+		q.Limit = &defaultLimit
+	}
+
+	return nil
+}
+
+// ------------------------------------------------------------
+
+type RestoreCategoryPayload struct {
+	ID uuid.UUID `param:"id" validate:"required,uuid"`
+}
+
+func (p *RestoreCategoryPayload) Validate() error {
+	validate := validator.New()
+	return validate.Struct(p)
+}
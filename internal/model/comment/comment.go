@@ -1,13 +1,25 @@
 package comment
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/uttam282005/tasker/internal/model"
 )
 
 type Comment struct {
 	model.Base
-	TodoID  uuid.UUID `json:"todoId" db:"todo_id"`
-	UserID  string    `json:"userId" db:"user_id"`
-	Content string    `json:"content" db:"content"`
+	TodoID    uuid.UUID  `json:"todoId" db:"todo_id"`
+	UserID    string     `json:"userId" db:"user_id"`
+	Content   string     `json:"content" db:"content"`
+	DeletedAt *time.Time `json:"deletedAt" db:"deleted_at"`
+	Version   int        `json:"version" db:"version"`
+
+	// SearchVector is generated by Postgres from content and never serialized.
+	SearchVector string `json:"-" db:"search_vector"`
+}
+
+// ETagVersion implements etag.Versioned.
+func (c Comment) ETagVersion() int {
+	return c.Version
 }
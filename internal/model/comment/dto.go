@@ -33,6 +33,7 @@ func (p *GetCommentsByTodoIDPayload) Validate() error {
 type UpdateCommentPayload struct {
 	ID      uuid.UUID `param:"id" validate:"required,uuid"`
 	Content string    `json:"content" validate:"required,min=1,max=1000"`
+	IfMatch *string   `header:"If-Match" json:"-"`
 }
 
 func (p *UpdateCommentPayload) Validate() error {
@@ -43,10 +44,49 @@ func (p *UpdateCommentPayload) Validate() error {
 // ------------------------------------------------------------
 
 type DeleteCommentPayload struct {
-	ID uuid.UUID `param:"id" validate:"required,uuid"`
+	ID      uuid.UUID `param:"id" validate:"required,uuid"`
+	IfMatch *string   `header:"If-Match" json:"-"`
 }
 
 func (p *DeleteCommentPayload) Validate() error {
 	validate := validator.New()
 	return validate.Struct(p)
 }
+
+// ------------------------------------------------------------
+
+type GetTrashedCommentsQuery struct {
+	Page  *int `query:"page" validate:"omitempty,min=1"`
+	Limit *int `query:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+func (q *GetTrashedCommentsQuery) Validate() error {
+	validate := validator.New()
+
+	if err := validate.Struct(q); err != nil {
+		return err
+	}
+
+	// Set defaults for pagination
+	if q.Page == nil {
+		defaultPage := 1
+		q.Page = &defaultPage
+	}
+	if q.Limit == nil {
+		defaultLimit := 20
+		q.Limit = &defaultLimit
+	}
+
+	return nil
+}
+
+// ------------------------------------------------------------
+
+type RestoreCommentPayload struct {
+	ID uuid.UUID `param:"id" validate:"required,uuid"`
+}
+
+func (p *RestoreCommentPayload) Validate() error {
+	validate := validator.New()
+	return validate.Struct(p)
+}
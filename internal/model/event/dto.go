@@ -0,0 +1,21 @@
+package event
+
+import (
+	"github.com/go-playground/validator/v10"
+)
+
+/*
+ * GET /api/v1/events -> stream live changes
+ */
+
+// StreamEventsQuery resumes a stream after LastEventID. Browsers send the
+// Last-Event-ID header on reconnect, which takes precedence; the query
+// parameter covers clients that cannot set headers on the first request.
+type StreamEventsQuery struct {
+	LastEventID *string `query:"lastEventId" validate:"omitempty,max=64"`
+}
+
+func (q *StreamEventsQuery) Validate() error {
+	validate := validator.New()
+	return validate.Struct(q)
+}
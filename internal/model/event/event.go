@@ -0,0 +1,38 @@
+package event
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Type is the kind of change pushed to connected clients.
+type Type string
+
+const (
+	TypeTodoCreated        Type = "todo.created"
+	TypeTodoUpdated        Type = "todo.updated"
+	TypeTodoDeleted        Type = "todo.deleted"
+	TypeTodoRestored       Type = "todo.restored"
+	TypeCommentAdded       Type = "comment.added"
+	TypeCommentUpdated     Type = "comment.updated"
+	TypeCommentDeleted     Type = "comment.deleted"
+	TypeCommentRestored    Type = "comment.restored"
+	TypeAttachmentUploaded Type = "attachment.uploaded"
+	TypeAttachmentDeleted  Type = "attachment.deleted"
+
+	// TypeReset tells a resuming client that part of the backlog it asked
+	// for has expired, so it should refetch instead of applying deltas.
+	TypeReset Type = "reset"
+)
+
+// Event is the data of a single server-sent event. Its ID travels in the
+// SSE id field so clients can resume with Last-Event-ID.
+type Event struct {
+	Type      Type            `json:"type"`
+	TodoID    uuid.UUID       `json:"todoId"`
+	ActorID   string          `json:"actorId"`
+	CreatedAt time.Time       `json:"createdAt"`
+	Data      json.RawMessage `json:"data"`
+}
@@ -0,0 +1,65 @@
+package imports
+
+import (
+	"encoding/json"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/uttam282005/tasker/internal/validation"
+)
+
+// ------------------------------------------------------------
+
+// CreateImportPayload comes with the file to import as the multipart "file"
+// field.
+type CreateImportPayload struct {
+	Source Source `form:"source" validate:"required,oneof=todoist trello csv"`
+	// Mapping is a JSON ColumnMapping, required for generic CSV files
+	Mapping *string `form:"mapping" validate:"omitempty,max=4096"`
+
+	ColumnMapping *ColumnMapping `json:"-"`
+}
+
+func (p *CreateImportPayload) Validate() error {
+	validate := validator.New()
+
+	if err := validate.Struct(p); err != nil {
+		return err
+	}
+
+	if p.Source != SourceCSV {
+		return nil
+	}
+
+	if p.Mapping == nil {
+		return validation.CustomValidationErrors{
+			{Field: "mapping", Message: "is required for csv imports"},
+		}
+	}
+
+	var mapping ColumnMapping
+	if err := json.Unmarshal([]byte(*p.Mapping), &mapping); err != nil {
+		return validation.CustomValidationErrors{
+			{Field: "mapping", Message: "must be a JSON object of column names"},
+		}
+	}
+	if err := validate.Struct(&mapping); err != nil {
+		return validation.CustomValidationErrors{
+			{Field: "mapping", Message: "must name the title column, and names must be at most 255 characters"},
+		}
+	}
+	p.ColumnMapping = &mapping
+
+	return nil
+}
+
+// ------------------------------------------------------------
+
+type GetImportByIDPayload struct {
+	ID uuid.UUID `param:"id" validate:"required,uuid"`
+}
+
+func (p *GetImportByIDPayload) Validate() error {
+	validate := validator.New()
+	return validate.Struct(p)
+}
@@ -0,0 +1,75 @@
+package imports
+
+import (
+	"time"
+
+	"github.com/uttam282005/tasker/internal/model"
+)
+
+// Source is the app an import's file was exported from.
+type Source string
+
+const (
+	// SourceTodoist is a Todoist project CSV or a JSON backup
+	SourceTodoist Source = "todoist"
+	// SourceTrello is a Trello board JSON export
+	SourceTrello Source = "trello"
+	// SourceCSV is any CSV, read through a column mapping
+	SourceCSV Source = "csv"
+)
+
+type Status string
+
+const (
+	StatusPending   Status = "pending"
+	StatusRunning   Status = "running"
+	StatusCompleted Status = "completed"
+	StatusFailed    Status = "failed"
+)
+
+// Import is a file of todos being imported by a background job, with its
+// progress and the rows that couldn't be imported in full.
+type Import struct {
+	model.Base
+	UserID        string         `json:"userId" db:"user_id"`
+	OrgID         *string        `json:"orgId" db:"org_id"`
+	Source        Source         `json:"source" db:"source"`
+	FileName      string         `json:"fileName" db:"file_name"`
+	Mapping       *ColumnMapping `json:"mapping" db:"mapping"`
+	Status        Status         `json:"status" db:"status"`
+	TotalRows     int            `json:"totalRows" db:"total_rows"`
+	ProcessedRows int            `json:"processedRows" db:"processed_rows"`
+	ImportedRows  int            `json:"importedRows" db:"imported_rows"`
+	FailedRows    int            `json:"failedRows" db:"failed_rows"`
+	RowErrors     []RowError     `json:"rowErrors" db:"row_errors"`
+	Error         *string        `json:"error" db:"error"`
+	StartedAt     *time.Time     `json:"startedAt" db:"started_at"`
+	CompletedAt   *time.Time     `json:"completedAt" db:"completed_at"`
+}
+
+// RowError reports a row of the source that wasn't imported, or was
+// imported without the part that failed when Imported is set.
+type RowError struct {
+	// Row is the row in a CSV, the header being row 1, or the position of
+	// the item in a JSON export, counting from 1
+	Row      int    `json:"row"`
+	Title    string `json:"title"`
+	Message  string `json:"message"`
+	Imported bool   `json:"imported"`
+}
+
+// ColumnMapping names the columns of a generic CSV that hold each field, by
+// their header. Only the title is required.
+type ColumnMapping struct {
+	Title       string `json:"title" validate:"required,max=255"`
+	Description string `json:"description,omitempty" validate:"max=255"`
+	DueDate     string `json:"dueDate,omitempty" validate:"max=255"`
+	Priority    string `json:"priority,omitempty" validate:"max=255"`
+	Status      string `json:"status,omitempty" validate:"max=255"`
+	Category    string `json:"category,omitempty" validate:"max=255"`
+	// Tags is split on commas and semicolons
+	Tags string `json:"tags,omitempty" validate:"max=255"`
+	// ID and ParentID link subtasks to their parent row
+	ID       string `json:"id,omitempty" validate:"max=255"`
+	ParentID string `json:"parentId,omitempty" validate:"max=255"`
+}
@@ -0,0 +1,38 @@
+package search
+
+import (
+	"github.com/go-playground/validator/v10"
+)
+
+/*
+ * GET /api/v1/search?q=... -> ranked hits across todos and comments
+ */
+
+// SearchQuery.Q uses websearch_to_tsquery syntax: "quoted phrases",
+// -exclusions and OR.
+type SearchQuery struct {
+	Q     string   `query:"q" validate:"required,min=1,max=250"`
+	Type  *HitType `query:"type" validate:"omitempty,oneof=todo comment"`
+	Page  *int     `query:"page" validate:"omitempty,min=1"`
+	Limit *int     `query:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+func (q *SearchQuery) Validate() error {
+	validate := validator.New()
+
+	if err := validate.Struct(q); err != nil {
+		return err
+	}
+
+	// Set defaults for pagination
+	if q.Page == nil {
+		defaultPage := 1
+		q.Page = &defaultPage
+	}
+	if q.Limit == nil {
+		defaultLimit := 20
+		q.Limit = &defaultLimit
+	}
+
+	return nil
+}
@@ -0,0 +1,26 @@
+package search
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type HitType string
+
+const (
+	HitTypeTodo    HitType = "todo"
+	HitTypeComment HitType = "comment"
+)
+
+// Hit is a single ranked match. TodoID and Title always refer to the todo,
+// so comment hits can link straight to the todo they were left on.
+type Hit struct {
+	Type      HitType   `json:"type" db:"type"`
+	ID        uuid.UUID `json:"id" db:"id"`
+	TodoID    uuid.UUID `json:"todoId" db:"todo_id"`
+	Title     string    `json:"title" db:"title"`
+	Snippet   string    `json:"snippet" db:"snippet"`
+	Rank      float64   `json:"rank" db:"rank"`
+	CreatedAt time.Time `json:"createdAt" db:"created_at"`
+}
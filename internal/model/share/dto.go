@@ -0,0 +1,105 @@
+package share
+
+import (
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+// ------------------------------------------------------------
+
+type CreateTodoSharePayload struct {
+	TodoID    uuid.UUID `param:"id" validate:"required,uuid"`
+	GranteeID string    `json:"granteeId" validate:"required,min=1,max=255"`
+	Role      Role      `json:"role" validate:"required,oneof=viewer commenter editor"`
+}
+
+func (p *CreateTodoSharePayload) Validate() error {
+	validate := validator.New()
+	return validate.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+type CreateCategorySharePayload struct {
+	CategoryID uuid.UUID `param:"id" validate:"required,uuid"`
+	GranteeID  string    `json:"granteeId" validate:"required,min=1,max=255"`
+	Role       Role      `json:"role" validate:"required,oneof=viewer commenter editor"`
+}
+
+func (p *CreateCategorySharePayload) Validate() error {
+	validate := validator.New()
+	return validate.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+type GetTodoSharesPayload struct {
+	TodoID uuid.UUID `param:"id" validate:"required,uuid"`
+}
+
+func (p *GetTodoSharesPayload) Validate() error {
+	validate := validator.New()
+	return validate.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+type GetCategorySharesPayload struct {
+	CategoryID uuid.UUID `param:"id" validate:"required,uuid"`
+}
+
+func (p *GetCategorySharesPayload) Validate() error {
+	validate := validator.New()
+	return validate.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+type UpdateSharePayload struct {
+	ID   uuid.UUID `param:"id" validate:"required,uuid"`
+	Role Role      `json:"role" validate:"required,oneof=viewer commenter editor"`
+}
+
+func (p *UpdateSharePayload) Validate() error {
+	validate := validator.New()
+	return validate.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+type DeleteSharePayload struct {
+	ID uuid.UUID `param:"id" validate:"required,uuid"`
+}
+
+func (p *DeleteSharePayload) Validate() error {
+	validate := validator.New()
+	return validate.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+type GetSharedWithMeQuery struct {
+	Type  *string `query:"type" validate:"omitempty,oneof=todo category"`
+	Page  *int    `query:"page" validate:"omitempty,min=1"`
+	Limit *int    `query:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+func (q *GetSharedWithMeQuery) Validate() error {
+	validate := validator.New()
+
+	if err := validate.Struct(q); err != nil {
+		return err
+	}
+
+	// Set defaults for pagination
+	if q.Page == nil {
+		defaultPage := 1
+		q.Page = &defaultPage
+	}
+	if q.Limit == nil {
+		defaultLimit := 20
+		q.Limit = &defaultLimit
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package share
+
+import (
+	"github.com/google/uuid"
+	"github.com/uttam282005/tasker/internal/model"
+	"github.com/uttam282005/tasker/internal/model/category"
+	"github.com/uttam282005/tasker/internal/model/todo"
+)
+
+// Role is what a user may do with a todo. Each role includes the permissions
+// of the ones before it. RoleOwner is never stored on a share.
+type Role string
+
+const (
+	RoleViewer    Role = "viewer"
+	RoleCommenter Role = "commenter"
+	RoleEditor    Role = "editor"
+	RoleOwner     Role = "owner"
+)
+
+var roleRanks = map[Role]int{
+	RoleViewer:    1,
+	RoleCommenter: 2,
+	RoleEditor:    3,
+	RoleOwner:     4,
+}
+
+// Allows reports whether r grants at least the permissions of required. The
+// empty role allows nothing.
+func (r Role) Allows(required Role) bool {
+	return roleRanks[r] > 0 && roleRanks[r] >= roleRanks[required]
+}
+
+// Share grants GranteeID a role on a single todo or, through CategoryID, on
+// every todo in a category. Exactly one of TodoID and CategoryID is set, and
+// subtasks are covered by the shares of their parent.
+type Share struct {
+	model.Base
+	OwnerID    string     `json:"ownerId" db:"owner_id"`
+	GranteeID  string     `json:"granteeId" db:"grantee_id"`
+	TodoID     *uuid.UUID `json:"todoId" db:"todo_id"`
+	CategoryID *uuid.UUID `json:"categoryId" db:"category_id"`
+	Role       Role       `json:"role" db:"role"`
+}
+
+// SharedItem is a share granted to the current user along with the todo or
+// category it covers.
+type SharedItem struct {
+	Share
+	Todo     *todo.Todo         `json:"todo" db:"todo"`
+	Category *category.Category `json:"category" db:"category"`
+}
@@ -0,0 +1,86 @@
+package tag
+
+import (
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+// ------------------------------------------------------------
+
+type CreateTagPayload struct {
+	Name string `json:"name" validate:"required,min=1,max=50"`
+	// Color defaults to grey
+	Color *string `json:"color" validate:"omitempty,hexcolor"`
+}
+
+func (p *CreateTagPayload) Validate() error {
+	validate := validator.New()
+	return validate.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+type GetTagsQuery struct {
+	Sort   *string `query:"sort" validate:"omitempty,oneof=name created_at usage_count"`
+	Order  *string `query:"order" validate:"omitempty,oneof=asc desc"`
+	Search *string `query:"search" validate:"omitempty,min=1"`
+}
+
+func (q *GetTagsQuery) Validate() error {
+	validate := validator.New()
+
+	if err := validate.Struct(q); err != nil {
+		return err
+	}
+
+	// Set defaults
+	if q.Sort == nil {
+		defaultSort := "name"
+		q.Sort = &defaultSort
+	}
+	if q.Order == nil {
+		defaultOrder := "asc"
+		if *q.Sort == "usage_count" {
+			defaultOrder = "desc"
+		}
+		q.Order = &defaultOrder
+	}
+
+	return nil
+}
+
+// ------------------------------------------------------------
+
+type GetTagByIDPayload struct {
+	ID uuid.UUID `param:"id" validate:"required,uuid"`
+}
+
+func (p *GetTagByIDPayload) Validate() error {
+	validate := validator.New()
+	return validate.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+// UpdateTagPayload renames or recolors a tag on every todo it's on.
+type UpdateTagPayload struct {
+	ID    uuid.UUID `param:"id" validate:"required,uuid"`
+	Name  *string   `json:"name" validate:"omitempty,min=1,max=50"`
+	Color *string   `json:"color" validate:"omitempty,hexcolor"`
+}
+
+func (p *UpdateTagPayload) Validate() error {
+	validate := validator.New()
+	return validate.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+type DeleteTagPayload struct {
+	ID uuid.UUID `param:"id" validate:"required,uuid"`
+}
+
+func (p *DeleteTagPayload) Validate() error {
+	validate := validator.New()
+	return validate.Struct(p)
+}
@@ -0,0 +1,20 @@
+package tag
+
+import (
+	"github.com/uttam282005/tasker/internal/model"
+)
+
+type Tag struct {
+	model.Base
+
+	UserID string  `json:"userId" db:"user_id"`
+	OrgID  *string `json:"orgId" db:"org_id"`
+	Name   string  `json:"name" db:"name"`
+	Color  string  `json:"color" db:"color"`
+}
+
+// TagUsage is a tag with the number of todos it's on, trashed ones aside.
+type TagUsage struct {
+	Tag
+	UsageCount int `json:"usageCount" db:"usage_count"`
+}
@@ -0,0 +1,57 @@
+package todo
+
+import (
+	"github.com/google/uuid"
+)
+
+type BulkAction string
+
+const (
+	BulkActionSetStatus    BulkAction = "set_status"
+	BulkActionSetPriority  BulkAction = "set_priority"
+	BulkActionMoveCategory BulkAction = "move_category"
+	BulkActionSetDueDate   BulkAction = "set_due_date"
+	BulkActionArchive      BulkAction = "archive"
+	BulkActionDelete       BulkAction = "delete"
+)
+
+// BulkMode decides what happens to the other todos when one of them fails.
+type BulkMode string
+
+const (
+	// BulkModeAtomic applies the action to every todo or to none of them
+	BulkModeAtomic BulkMode = "atomic"
+	// BulkModeBestEffort applies the action to every todo it can
+	BulkModeBestEffort BulkMode = "best_effort"
+)
+
+type BulkResultStatus string
+
+const (
+	BulkResultSucceeded BulkResultStatus = "succeeded"
+	BulkResultFailed    BulkResultStatus = "failed"
+	// BulkResultSkipped marks todos left alone because another todo failed
+	// in atomic mode
+	BulkResultSkipped BulkResultStatus = "skipped"
+)
+
+type BulkError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type BulkResult struct {
+	ID     uuid.UUID        `json:"id"`
+	Status BulkResultStatus `json:"status"`
+	Error  *BulkError       `json:"error"`
+	// Todo is the todo after the change; deleted todos carry deletedAt
+	Todo *Todo `json:"todo"`
+}
+
+type BulkResponse struct {
+	// Committed is false when nothing was changed
+	Committed bool         `json:"committed"`
+	Succeeded int          `json:"succeeded"`
+	Failed    int          `json:"failed"`
+	Results   []BulkResult `json:"results"`
+}
@@ -0,0 +1,16 @@
+package todo
+
+import (
+	"github.com/google/uuid"
+	"github.com/uttam282005/tasker/internal/model"
+)
+
+// TodoDependency records that BlockingTodoID must be completed before
+// BlockedTodoID can be.
+type TodoDependency struct {
+	model.Base
+
+	UserID         string    `json:"userId" db:"user_id"`
+	BlockingTodoID uuid.UUID `json:"blockingTodoId" db:"blocking_todo_id"`
+	BlockedTodoID  uuid.UUID `json:"blockedTodoId" db:"blocked_todo_id"`
+}
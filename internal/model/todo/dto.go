@@ -1,38 +1,86 @@
 package todo
 
 import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
+	"github.com/uttam282005/tasker/internal/lib/filterql"
+	"github.com/uttam282005/tasker/internal/lib/mergepatch"
+	"github.com/uttam282005/tasker/internal/lib/rrule"
+	"github.com/uttam282005/tasker/internal/validation"
 )
 
 /*
- * GET  /api/todos -> get all todo's
- * POST /api/todos -> create a new todo
- * PUT  /api/todos/:id -> update a todo
- * DELETE /api/todos/:id -> delete a todo
+ * GET    /api/v1/todos -> get all todo's
+ * POST   /api/v1/todos -> create a new todo
+ * GET    /api/v1/todos/:id -> get a todo
+ * PUT    /api/v1/todos/:id -> update a todo
+ * PATCH  /api/v1/todos/:id -> merge patch a todo
+ * DELETE /api/v1/todos/:id -> delete a todo
  */
 
 type CreateTodoPayload struct {
 	Title        string     `json:"title" validate:"required,min=1,max=250"`
-	Description  *string    `json:"description" validate:"omitempty max=1000"`
+	Description  *string    `json:"description" validate:"omitempty,max=1000"`
 	DueDate      *time.Time `json:"dueDate" validate:"omitempty"`
 	ParentTodoID *uuid.UUID `json:"parentTodoId" validate:"omitempty,uuid"`
 	CategoryID   *uuid.UUID `json:"categoryId" validate:"omitempty,uuid"`
 	Metadata     *Metadata  `json:"metadata"`
-	Priority     *Priority  `json:"priority" validate:"omitempty oneof=low medium high"`
+	Priority     *Priority  `json:"priority" validate:"omitempty,oneof=low medium high"`
+	Recurrence   *string    `json:"recurrence" validate:"omitempty,max=255"`
+	AssigneeID   *string    `json:"assigneeId" validate:"omitempty,min=1,max=255"`
+	// Tags are tag names; ones the workspace doesn't have yet are created
+	Tags []string `json:"tags" validate:"omitempty,max=20,dive,min=1,max=50"`
 }
 
 func (payload *CreateTodoPayload) Validate() error {
 	validate := validator.New()
-	return validate.Struct(payload)
+
+	payload.Tags = normalizeTags(payload.Tags)
+
+	if err := validate.Struct(payload); err != nil {
+		return err
+	}
+
+	if payload.Recurrence != nil {
+		var validationErrors validation.CustomValidationErrors
+
+		if _, err := rrule.Parse(*payload.Recurrence); err != nil {
+			validationErrors = append(validationErrors, validation.CustomValidationError{
+				Field:   "recurrence",
+				Message: err.Error(),
+			})
+		}
+		if payload.DueDate == nil {
+			validationErrors = append(validationErrors, validation.CustomValidationError{
+				Field:   "dueDate",
+				Message: "is required for recurring todos",
+			})
+		}
+		if payload.ParentTodoID != nil {
+			validationErrors = append(validationErrors, validation.CustomValidationError{
+				Field:   "recurrence",
+				Message: "subtasks cannot repeat",
+			})
+		}
+
+		if len(validationErrors) > 0 {
+			return validationErrors
+		}
+	}
+
+	return nil
 }
 
 // --------------------------------------------------------------------------------------
 
 type UpdateTodoPayload struct {
-	ID           uuid.UUID  `json:"id" validate:"required,uuid"`
+	ID           uuid.UUID  `param:"id" validate:"required,uuid"`
 	Title        *string    `json:"title" validate:"omitempty,min=1,max=250"`
 	Description  *string    `json:"description" validate:"omitempty,max=1000"`
 	Status       *Status    `json:"status" validate:"omitempty,oneof=draft active completed archived"`
@@ -40,30 +88,274 @@ type UpdateTodoPayload struct {
 	ParentTodoID *uuid.UUID `json:"parentTodoId" validate:"omitempty,uuid"`
 	CategoryID   *uuid.UUID `json:"categoryId" validate:"omitempty,uuid"`
 	Metadata     *Metadata  `json:"metadata"`
-	Priority     *Priority  `json:"priority" validate:"omitempty oneof=low medium high"`
+	Priority     *Priority  `json:"priority" validate:"omitempty,oneof=low medium high"`
+	// AssigneeID hands the todo to another member; an empty string unassigns it
+	AssigneeID *string `json:"assigneeId" validate:"omitempty,max=255"`
+	// Recurrence replaces the series rule; an empty string stops the series
+	Recurrence *string `json:"recurrence" validate:"omitempty,max=255"`
+	// Tags replaces the todo's tags by name, creating any the workspace
+	// doesn't have yet; an empty list removes them all
+	Tags []string `json:"tags" validate:"omitempty,max=20,dive,min=1,max=50"`
+	// Scope selects whether a recurring todo's change applies to this
+	// occurrence only or to it and all later occurrences
+	Scope *RecurrenceScope `json:"scope" validate:"omitempty,oneof=this future"`
+	// IfMatch refuses the update with 412 when the todo has changed since
+	// the client read it
+	IfMatch *string `header:"If-Match" json:"-"`
+
+	// Clear lists the nullable fields, by JSON name, to set to null. A JSON
+	// body can't tell null from a missing member, so only merge patches fill it.
+	Clear []string `json:"-"`
+	// MetadataPatch is merged into the current metadata instead of replacing it
+	MetadataPatch json.RawMessage `json:"-"`
+}
+
+// Clears reports whether the update sets field to null.
+func (payload *UpdateTodoPayload) Clears(field string) bool {
+	return slices.Contains(payload.Clear, field)
 }
 
 func (payload *UpdateTodoPayload) Validate() error {
 	validate := validator.New()
-	return validate.Struct(payload)
+
+	payload.Tags = normalizeTags(payload.Tags)
+
+	if err := validate.Struct(payload); err != nil {
+		return err
+	}
+
+	if payload.Recurrence != nil && *payload.Recurrence != "" {
+		if _, err := rrule.Parse(*payload.Recurrence); err != nil {
+			return validation.CustomValidationErrors{
+				{Field: "recurrence", Message: err.Error()},
+			}
+		}
+	}
+
+	if payload.Scope == nil {
+		defaultScope := RecurrenceScopeThis
+		payload.Scope = &defaultScope
+	}
+
+	return nil
+}
+
+// --------------------------------------------------------------------------------------
+
+// Fields a merge patch can set to null, by their JSON names
+const (
+	FieldDescription  = "description"
+	FieldDueDate      = "dueDate"
+	FieldParentTodoID = "parentTodoId"
+	FieldCategoryID   = "categoryId"
+	FieldMetadata     = "metadata"
+	FieldTags         = "tags"
+)
+
+var (
+	clearableFields = []string{FieldDescription, FieldDueDate, FieldParentTodoID, FieldCategoryID, FieldMetadata, FieldTags}
+	requiredFields  = []string{"title", "status", "priority"}
+)
+
+// PatchTodoPayload is a JSON Merge Patch (RFC 7396) for a todo. Members work
+// as in UpdateTodoPayload, except that null clears description, dueDate,
+// parentTodoId, categoryId, metadata or tags, null assigneeId or recurrence
+// unassigns the todo or stops its series, and metadata is merged into the
+// current value rather than replacing it.
+type PatchTodoPayload struct {
+	UpdateTodoPayload
+
+	// nulls holds the members that can't be cleared but were set to null
+	nulls []string
+}
+
+func (p *PatchTodoPayload) UnmarshalJSON(data []byte) error {
+	members, err := mergepatch.Members(data)
+	if err != nil {
+		return err
+	}
+
+	// Decoding leaves null members as nil, the same as missing ones
+	if err := json.Unmarshal(data, &p.UpdateTodoPayload); err != nil {
+		return err
+	}
+
+	if metadata, ok := members[FieldMetadata]; ok && !mergepatch.IsNull(metadata) {
+		p.Metadata = nil
+		p.MetadataPatch = metadata
+	}
+
+	empty := ""
+	for name, value := range members {
+		if !mergepatch.IsNull(value) {
+			continue
+		}
+
+		switch {
+		case slices.Contains(clearableFields, name):
+			p.Clear = append(p.Clear, name)
+		case name == "assigneeId":
+			p.AssigneeID = &empty
+		case name == "recurrence":
+			p.Recurrence = &empty
+		case slices.Contains(requiredFields, name):
+			p.nulls = append(p.nulls, name)
+		}
+	}
+
+	return nil
+}
+
+func (p *PatchTodoPayload) Validate() error {
+	if len(p.nulls) > 0 {
+		slices.Sort(p.nulls)
+
+		var validationErrors validation.CustomValidationErrors
+		for _, name := range p.nulls {
+			validationErrors = append(validationErrors, validation.CustomValidationError{
+				Field:   name,
+				Message: "cannot be null",
+			})
+		}
+		return validationErrors
+	}
+
+	return p.UpdateTodoPayload.Validate()
+}
+
+// --------------------------------------------------------------------------------------
+
+// MoveTodoPayload places a todo among its siblings: todos sharing its parent,
+// or for root todos, sharing its category. Exactly one of BeforeID, AfterID
+// or Position must be set.
+type MoveTodoPayload struct {
+	ID       uuid.UUID  `param:"id" validate:"required,uuid"`
+	BeforeID *uuid.UUID `json:"beforeId" validate:"omitempty,uuid"`
+	AfterID  *uuid.UUID `json:"afterId" validate:"omitempty,uuid"`
+	Position *int       `json:"position" validate:"omitempty,min=0"`
+}
+
+func (p *MoveTodoPayload) Validate() error {
+	validate := validator.New()
+
+	if err := validate.Struct(p); err != nil {
+		return err
+	}
+
+	set := 0
+	for _, isSet := range []bool{p.BeforeID != nil, p.AfterID != nil, p.Position != nil} {
+		if isSet {
+			set++
+		}
+	}
+	if set != 1 {
+		return validation.CustomValidationErrors{
+			{
+				Field:   "beforeId",
+				Message: "exactly one of beforeId, afterId or position is required",
+			},
+		}
+	}
+
+	if (p.BeforeID != nil && *p.BeforeID == p.ID) || (p.AfterID != nil && *p.AfterID == p.ID) {
+		return validation.CustomValidationErrors{
+			{
+				Field:   "beforeId",
+				Message: "cannot move a todo relative to itself",
+			},
+		}
+	}
+
+	return nil
 }
 
 // --------------------------------------------------------------------------------------
 
+// TodoFilters are the filters shared by todo lists, exports and saved
+// views, which store them as JSON.
+type TodoFilters struct {
+	Search       *string    `json:"search,omitempty" query:"search" validate:"omitempty,min=1"`
+	Status       *Status    `json:"status,omitempty" query:"status" validate:"omitempty,oneof=draft active completed archived"`
+	Priority     *Priority  `json:"priority,omitempty" query:"priority" validate:"omitempty,oneof=low medium high"`
+	CategoryID   *uuid.UUID `json:"categoryId,omitempty" query:"categoryId" validate:"omitempty,uuid"`
+	ParentTodoID *uuid.UUID `json:"parentTodoId,omitempty" query:"parentTodoId" validate:"omitempty,uuid"`
+	DueFrom      *time.Time `json:"dueFrom,omitempty" query:"dueFrom"`
+	DueTo        *time.Time `json:"dueTo,omitempty" query:"dueTo"`
+	HasDueDate   *bool      `json:"hasDueDate,omitempty" query:"hasDueDate"`
+	Overdue      *bool      `json:"overdue,omitempty" query:"overdue"`
+	Completed    *bool      `json:"completed,omitempty" query:"completed"`
+	Blocked      *bool      `json:"blocked,omitempty" query:"blocked"`
+	// Assignee is a user ID, or "me" for the requesting user
+	Assignee *string `json:"assignee,omitempty" query:"assignee" validate:"omitempty,min=1,max=255"`
+	Watching *bool   `json:"watching,omitempty" query:"watching"`
+	// Tags filters by tag name, given as repeated tag parameters. TagMatch
+	// picks whether a todo needs any of them (the default) or all of them.
+	Tags     []string `json:"tags,omitempty" query:"tag" validate:"omitempty,max=20,dive,min=1,max=50"`
+	TagMatch *string  `json:"tagMatch,omitempty" query:"tagMatch" validate:"omitempty,oneof=any all"`
+	// Q is a filter query, such as "priority:high OR due<7d -tag:someday",
+	// that todos must match on top of the other filters. See package
+	// filterql for the language.
+	Q *string `json:"q,omitempty" query:"q" validate:"omitempty,max=1000"`
+	// Timezone is the IANA time zone the days of Q's dates are in, UTC by
+	// default
+	Timezone *string `json:"timezone,omitempty" query:"timezone" validate:"omitempty,timezone"`
+}
+
+// ValidateQuery checks Q parses, reporting where it doesn't under the given
+// field name.
+func (f *TodoFilters) ValidateQuery(field string) error {
+	if f.Q == nil {
+		return nil
+	}
+
+	if _, err := filterql.Parse(*f.Q); err != nil {
+		var queryErr *filterql.Error
+		if !errors.As(err, &queryErr) {
+			return err
+		}
+		return validation.CustomValidationErrors{
+			{Field: field, Message: queryErr.Message, Position: &queryErr.Pos},
+		}
+	}
+
+	return nil
+}
+
 type GetTodosQuery struct {
-	Page         *int       `query:"page" validate:"omitempty,min=1"`
-	Limit        *int       `query:"limit" validate:"omitempty,min=1,max=100"`
-	Sort         *string    `query:"sort" validate:"omitempty,oneof=created_at updated_at title priority due_date status"`
-	Order        *string    `query:"order" validate:"omitempty,oneof=asc desc"`
-	Search       *string    `query:"search" validate:"omitempty,min=1"`
-	Status       *Status    `query:"status" validate:"omitempty,oneof=draft active completed archived"`
-	Priority     *Priority  `query:"priority" validate:"omitempty,oneof=low medium high"`
-	CategoryID   *uuid.UUID `query:"categoryId" validate:"omitempty,uuid"`
-	ParentTodoID *uuid.UUID `query:"parentTodoId" validate:"omitempty,uuid"`
-	DueFrom      *time.Time `query:"dueFrom"`
-	DueTo        *time.Time `query:"dueTo"`
-	Overdue      *bool      `query:"overdue"`
-	Completed    *bool      `query:"completed"`
+	Page  *int    `query:"page" validate:"omitempty,min=1"`
+	Limit *int    `query:"limit" validate:"omitempty,min=1,max=100"`
+	Sort  *string `query:"sort" validate:"omitempty,oneof=created_at updated_at title priority due_date status sort_order"`
+	Order *string `query:"order" validate:"omitempty,oneof=asc desc"`
+	TodoFilters
+
+	// Cursor continues the list from a nextCursor or prevCursor, in place
+	// of page, and implies cursor pagination
+	Cursor *string `query:"cursor" validate:"omitempty,max=1024"`
+	// Pagination picks paging by page number (offset, the default) or by
+	// cursor, where the first page is requested without a cursor
+	Pagination *string `query:"pagination" validate:"omitempty,oneof=offset cursor"`
+	// Count adds the total to the response. It defaults to true when paging
+	// by page number and false when paging by cursor.
+	Count *bool `query:"count"`
+	// Include lists the relations to embed in each todo, comma separated,
+	// out of children, comments and attachments. Left out relations are
+	// null. All of them are embedded by default.
+	Include *string `query:"include" validate:"omitempty,max=255"`
+}
+
+// IncludedRelations returns the relations to embed in each todo.
+func (q *GetTodosQuery) IncludedRelations() []string {
+	if q.Include == nil {
+		return Relations
+	}
+
+	var relations []string
+	for _, relation := range strings.Split(*q.Include, ",") {
+		if relation = strings.TrimSpace(relation); relation != "" {
+			relations = append(relations, relation)
+		}
+	}
+	return relations
 }
 
 func (q *GetTodosQuery) Validate() error {
@@ -73,8 +365,38 @@ func (q *GetTodosQuery) Validate() error {
 		return err
 	}
 
+	if err := q.ValidateQuery("q"); err != nil {
+		return err
+	}
+
+	for _, relation := range q.IncludedRelations() {
+		if !slices.Contains(Relations, relation) {
+			return validation.CustomValidationErrors{
+				{Field: "include", Message: "must be a comma-separated list of: " + strings.Join(Relations, ", ")},
+			}
+		}
+	}
+
+	if q.Cursor != nil && (q.Page != nil || (q.Pagination != nil && *q.Pagination == "offset")) {
+		return validation.CustomValidationErrors{
+			{Field: "cursor", Message: "cannot be combined with page or offset pagination"},
+		}
+	}
+
+	if q.Pagination == nil {
+		defaultPagination := "offset"
+		if q.Cursor != nil {
+			defaultPagination = "cursor"
+		}
+		q.Pagination = &defaultPagination
+	}
+	if q.Count == nil {
+		defaultCount := *q.Pagination == "offset"
+		q.Count = &defaultCount
+	}
+
 	// Set defaults for pagination
-	if q.Page == nil {
+	if q.Page == nil && *q.Pagination == "offset" {
 		defaultPage := 1
 		q.Page = &defaultPage
 	}
@@ -96,8 +418,45 @@ func (q *GetTodosQuery) Validate() error {
 
 // ------------------------------------------------------------
 
+// ExportTodosQuery exports every todo matching the filters, root todos with
+// their subtasks, in the list's sort order.
+type ExportTodosQuery struct {
+	Format ExportFormat `query:"format" validate:"required,oneof=csv json md"`
+	Sort   *string      `query:"sort" validate:"omitempty,oneof=created_at updated_at title priority due_date status sort_order"`
+	Order  *string      `query:"order" validate:"omitempty,oneof=asc desc"`
+	TodoFilters
+}
+
+func (q *ExportTodosQuery) Validate() error {
+	validate := validator.New()
+
+	if err := validate.Struct(q); err != nil {
+		return err
+	}
+
+	if err := q.ValidateQuery("q"); err != nil {
+		return err
+	}
+
+	if q.Sort == nil {
+		defaultSort := "created_at"
+		q.Sort = &defaultSort
+	}
+	if q.Order == nil {
+		defaultOrder := "desc"
+		q.Order = &defaultOrder
+	}
+
+	return nil
+}
+
+// ------------------------------------------------------------
+
 type GetTodoByIDPayload struct {
 	ID uuid.UUID `param:"id" validate:"required,uuid"`
+	// IfNoneMatch is answered with 304 by the response handler when the
+	// representation is unchanged
+	IfNoneMatch *string `header:"If-None-Match" json:"-"`
 }
 
 func (p *GetTodoByIDPayload) Validate() error {
@@ -108,7 +467,8 @@ func (p *GetTodoByIDPayload) Validate() error {
 // ------------------------------------------------------------
 
 type DeleteTodoPayload struct {
-	ID uuid.UUID `param:"id" validate:"required,uuid"`
+	ID      uuid.UUID `param:"id" validate:"required,uuid"`
+	IfMatch *string   `header:"If-Match" json:"-"`
 }
 
 func (p *DeleteTodoPayload) Validate() error {
@@ -118,6 +478,49 @@ func (p *DeleteTodoPayload) Validate() error {
 
 // ------------------------------------------------------------
 
+// BulkTodosPayload applies one action to several todos. Status goes with
+// set_status and priority with set_priority. For move_category and
+// set_due_date, leaving categoryId or dueDate out takes the todos out of
+// their category or clears their due date.
+type BulkTodosPayload struct {
+	IDs        []uuid.UUID `json:"ids" validate:"required,min=1,max=100,unique"`
+	Action     BulkAction  `json:"action" validate:"required,oneof=set_status set_priority move_category set_due_date archive delete"`
+	Mode       *BulkMode   `json:"mode" validate:"omitempty,oneof=atomic best_effort"`
+	Status     *Status     `json:"status" validate:"omitempty,oneof=draft active completed archived"`
+	Priority   *Priority   `json:"priority" validate:"omitempty,oneof=low medium high"`
+	CategoryID *uuid.UUID  `json:"categoryId" validate:"omitempty,uuid"`
+	DueDate    *time.Time  `json:"dueDate"`
+}
+
+func (p *BulkTodosPayload) Validate() error {
+	validate := validator.New()
+
+	if err := validate.Struct(p); err != nil {
+		return err
+	}
+
+	if p.Action == BulkActionSetStatus && p.Status == nil {
+		return validation.CustomValidationErrors{
+			{Field: "status", Message: "is required for set_status"},
+		}
+	}
+
+	if p.Action == BulkActionSetPriority && p.Priority == nil {
+		return validation.CustomValidationErrors{
+			{Field: "priority", Message: "is required for set_priority"},
+		}
+	}
+
+	if p.Mode == nil {
+		defaultMode := BulkModeAtomic
+		p.Mode = &defaultMode
+	}
+
+	return nil
+}
+
+// ------------------------------------------------------------
+
 type GetTodoStatsPayload struct{}
 
 func (p *GetTodoStatsPayload) Validate() error {
@@ -160,3 +563,116 @@ func (p *GetAttachmentPresignedURLPayload) Validate() error {
 	validate := validator.New()
 	return validate.Struct(p)
 }
+
+// ------------------------------------------------------------
+// Trash DTOs
+// ------------------------------------------------------------
+
+type GetTrashedTodosQuery struct {
+	Page  *int `query:"page" validate:"omitempty,min=1"`
+	Limit *int `query:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+func (q *GetTrashedTodosQuery) Validate() error {
+	validate := validator.New()
+
+	if err := validate.Struct(q); err != nil {
+		return err
+	}
+
+	// Set defaults for pagination
+	if q.Page == nil {
+		defaultPage := 1
+		q.Page = &defaultPage
+	}
+	if q.Limit == nil {
+		defaultLimit := 20
+		q.Limit = &defaultLimit
+	}
+
+	return nil
+}
+
+// ------------------------------------------------------------
+
+type RestoreTodoPayload struct {
+	ID uuid.UUID `param:"id" validate:"required,uuid"`
+}
+
+func (p *RestoreTodoPayload) Validate() error {
+	validate := validator.New()
+	return validate.Struct(p)
+}
+
+// ------------------------------------------------------------
+// Todo Dependency DTOs
+// ------------------------------------------------------------
+
+type AddTodoBlockerPayload struct {
+	TodoID    uuid.UUID `param:"id" validate:"required,uuid"`
+	BlockerID uuid.UUID `json:"blockerId" validate:"required,uuid"`
+}
+
+func (p *AddTodoBlockerPayload) Validate() error {
+	validate := validator.New()
+	return validate.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+type RemoveTodoBlockerPayload struct {
+	TodoID    uuid.UUID `param:"id" validate:"required,uuid"`
+	BlockerID uuid.UUID `param:"blockerId" validate:"required,uuid"`
+}
+
+func (p *RemoveTodoBlockerPayload) Validate() error {
+	validate := validator.New()
+	return validate.Struct(p)
+}
+
+// ------------------------------------------------------------
+// Todo Watcher DTOs
+// ------------------------------------------------------------
+
+// AddTodoWatcherPayload subscribes a user to a todo. UserID defaults to the
+// requesting user.
+type AddTodoWatcherPayload struct {
+	TodoID uuid.UUID `param:"id" validate:"required,uuid"`
+	UserID *string   `json:"userId" validate:"omitempty,min=1,max=255"`
+}
+
+func (p *AddTodoWatcherPayload) Validate() error {
+	validate := validator.New()
+	return validate.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+// RemoveTodoWatcherPayload unsubscribes a user from a todo. UserID may be
+// "me" for the requesting user.
+type RemoveTodoWatcherPayload struct {
+	TodoID uuid.UUID `param:"id" validate:"required,uuid"`
+	UserID string    `param:"userId" validate:"required,max=255"`
+}
+
+func (p *RemoveTodoWatcherPayload) Validate() error {
+	validate := validator.New()
+	return validate.Struct(p)
+}
+
+// normalizeTags trims tag names and drops blanks and repeats. A nil list
+// stays nil, so updates can tell "leave the tags alone" from "remove them".
+func normalizeTags(tags []string) []string {
+	if tags == nil {
+		return nil
+	}
+
+	normalized := make([]string, 0, len(tags))
+	for _, name := range tags {
+		name = strings.TrimSpace(name)
+		if name != "" && !slices.Contains(normalized, name) {
+			normalized = append(normalized, name)
+		}
+	}
+	return normalized
+}
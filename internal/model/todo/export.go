@@ -0,0 +1,88 @@
+package todo
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ExportFormat string
+
+const (
+	ExportFormatCSV      ExportFormat = "csv"
+	ExportFormatJSON     ExportFormat = "json"
+	ExportFormatMarkdown ExportFormat = "md"
+)
+
+// ContentType returns the media type an export is served as.
+func (f ExportFormat) ContentType() string {
+	switch f {
+	case ExportFormatCSV:
+		return "text/csv; charset=utf-8"
+	case ExportFormatMarkdown:
+		return "text/markdown; charset=utf-8"
+	default:
+		return "application/json"
+	}
+}
+
+// ExportedTodo is a todo as it appears in an export, with names in place of
+// ids where there's one to show.
+type ExportedTodo struct {
+	ID          uuid.UUID         `json:"id"`
+	Title       string            `json:"title"`
+	Description *string           `json:"description"`
+	Status      Status            `json:"status"`
+	Priority    Priority          `json:"priority"`
+	DueDate     *time.Time        `json:"dueDate"`
+	CompletedAt *time.Time        `json:"completedAt"`
+	Category    *string           `json:"category"`
+	Tags        []string          `json:"tags"`
+	AssigneeID  *string           `json:"assigneeId"`
+	CreatedAt   time.Time         `json:"createdAt"`
+	UpdatedAt   time.Time         `json:"updatedAt"`
+	Comments    []ExportedComment `json:"comments"`
+	// Subtasks is left out of subtasks themselves
+	Subtasks []ExportedTodo `json:"subtasks,omitempty"`
+}
+
+type ExportedComment struct {
+	UserID    string    `json:"userId"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// NewExportedTodo returns the export form of a todo read with its category
+// and comments.
+func NewExportedTodo(t *PopulatedTodo) ExportedTodo {
+	exported := ExportedTodo{
+		ID:          t.ID,
+		Title:       t.Title,
+		Description: t.Description,
+		Status:      t.Status,
+		Priority:    t.Priority,
+		DueDate:     t.DueDate,
+		CompletedAt: t.CompletedAt,
+		Tags:        []string{},
+		AssigneeID:  t.AssigneeID,
+		CreatedAt:   t.CreatedAt,
+		UpdatedAt:   t.UpdatedAt,
+		Comments:    make([]ExportedComment, 0, len(t.Comments)),
+	}
+
+	if t.Category != nil {
+		exported.Category = &t.Category.Name
+	}
+	for _, tg := range t.Tags {
+		exported.Tags = append(exported.Tags, tg.Name)
+	}
+	for _, c := range t.Comments {
+		exported.Comments = append(exported.Comments, ExportedComment{
+			UserID:    c.UserID,
+			Content:   c.Content,
+			CreatedAt: c.CreatedAt,
+		})
+	}
+
+	return exported
+}
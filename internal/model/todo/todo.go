@@ -7,6 +7,7 @@ import (
 	"github.com/uttam282005/tasker/internal/model"
 	"github.com/uttam282005/tasker/internal/model/category"
 	"github.com/uttam282005/tasker/internal/model/comment"
+	"github.com/uttam282005/tasker/internal/model/tag"
 )
 
 type Status string
@@ -26,9 +27,22 @@ const (
 	PriorityHigh   Priority = "high"
 )
 
+type RecurrenceScope string
+
+const (
+	RecurrenceScopeThis   RecurrenceScope = "this"
+	RecurrenceScopeFuture RecurrenceScope = "future"
+)
+
+// SortOrderGap is the spacing between neighbouring sort_order values when a
+// list is (re)numbered, leaving room for moves to take a midpoint.
+const SortOrderGap int64 = 1024
+
 type Todo struct {
 	model.Base
 	UserID       string     `json:"userId" db:"user_id"`
+	OrgID        *string    `json:"orgId" db:"org_id"`
+	AssigneeID   *string    `json:"assigneeId" db:"assignee_id"`
 	Title        string     `json:"title" db:"title"`
 	Description  *string    `json:"description" db:"description"`
 	Status       Status     `json:"status" db:"status"`
@@ -38,22 +52,53 @@ type Todo struct {
 	ParentTodoID *uuid.UUID `json:"parentTodoId" db:"parent_todo_id"`
 	CategoryID   *uuid.UUID `json:"categoryId" db:"category_id"`
 	Metadata     *Metadata  `json:"metadata" db:"metadata"`
-	SortOrder    int        `json:"sortOrder" db:"sort_order"`
+	SortOrder    int64      `json:"sortOrder" db:"sort_order"`
+	DeletedAt    *time.Time `json:"deletedAt" db:"deleted_at"`
+	Version      int        `json:"version" db:"version"`
+
+	// Recurrence is an RFC 5545 RRULE shared by every occurrence of a series.
+	// RecurrenceDate is the slot the occurrence was scheduled for, which stays
+	// put when a single occurrence's due date is moved.
+	Recurrence         *string    `json:"recurrence" db:"recurrence"`
+	RecurrenceSeriesID *uuid.UUID `json:"recurrenceSeriesId" db:"recurrence_series_id"`
+	RecurrenceIndex    *int       `json:"recurrenceIndex" db:"recurrence_index"`
+	RecurrenceDate     *time.Time `json:"recurrenceDate" db:"recurrence_date"`
+
+	// SearchVector is generated by Postgres from title and description. It is
+	// never serialized; it only exists so SELECT * rows map onto Todo.
+	SearchVector string `json:"-" db:"search_vector"`
+}
+
+// ETagVersion implements etag.Versioned.
+func (t Todo) ETagVersion() int {
+	return t.Version
 }
 
 type Metadata struct {
-	Tags       []string `json:"tags"`
-	Reminder   *string  `json:"reminder"`
-	Color      *string  `json:"color"`
-	Difficulty *int     `json:"difficulty"`
+	Reminder   *string `json:"reminder"`
+	Color      *string `json:"color"`
+	Difficulty *int    `json:"difficulty"`
 }
 
+// Relations a PopulatedTodo can leave out when listing todos
+const (
+	RelationChildren    = "children"
+	RelationComments    = "comments"
+	RelationAttachments = "attachments"
+)
+
+var Relations = []string{RelationChildren, RelationComments, RelationAttachments}
+
 type PopulatedTodo struct {
 	Todo
 	Category    *category.Category `json:"category" db:"category"`
+	Tags        []tag.Tag          `json:"tags" db:"tags"`
 	Children    []Todo             `json:"children" db:"children"`
 	Comments    []comment.Comment  `json:"comments" db:"comments"`
 	Attachments []TodoAttachment   `json:"attachments" db:"attachments"`
+	BlockedBy   []Todo             `json:"blockedBy" db:"blocked_by"`
+	Blocking    []Todo             `json:"blocking" db:"blocking"`
+	Watchers    []string           `json:"watchers" db:"watchers"`
 }
 
 type TodoStats struct {
@@ -77,6 +122,10 @@ func (t *Todo) IsOverdue() bool {
 	return t.DueDate != nil && t.DueDate.Before(time.Now()) && t.Status != StatusCompleted
 }
 
+func (t *Todo) IsRecurring() bool {
+	return t.Recurrence != nil && t.RecurrenceSeriesID != nil
+}
+
 func (t *Todo) CanHaveChildren() bool {
 	return t.ParentTodoID == nil
 }
@@ -0,0 +1,14 @@
+package todo
+
+import (
+	"github.com/google/uuid"
+	"github.com/uttam282005/tasker/internal/model"
+)
+
+// TodoWatcher subscribes UserID to notifications about changes to TodoID.
+type TodoWatcher struct {
+	model.Base
+
+	TodoID uuid.UUID `json:"todoId" db:"todo_id"`
+	UserID string    `json:"userId" db:"user_id"`
+}
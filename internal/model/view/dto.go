@@ -0,0 +1,191 @@
+package view
+
+import (
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/uttam282005/tasker/internal/model/todo"
+)
+
+// ------------------------------------------------------------
+
+type CreateViewPayload struct {
+	Name    string           `json:"name" validate:"required,min=1,max=100"`
+	Filters todo.TodoFilters `json:"filters"`
+	Sort    *string          `json:"sort" validate:"omitempty,oneof=created_at updated_at title priority due_date status sort_order"`
+	Order   *string          `json:"order" validate:"omitempty,oneof=asc desc"`
+	GroupBy *GroupBy         `json:"groupBy" validate:"omitempty,oneof=none category status priority due_date assignee"`
+	// Shared lists the view for every member of the organization. Views
+	// outside an organization can't be shared.
+	Shared *bool `json:"shared"`
+}
+
+func (p *CreateViewPayload) Validate() error {
+	validate := validator.New()
+
+	if err := validate.Struct(p); err != nil {
+		return err
+	}
+
+	if err := p.Filters.ValidateQuery("filters.q"); err != nil {
+		return err
+	}
+
+	// Set defaults, the same as the todo list's
+	if p.Sort == nil {
+		defaultSort := "created_at"
+		p.Sort = &defaultSort
+	}
+	if p.Order == nil {
+		defaultOrder := "desc"
+		p.Order = &defaultOrder
+	}
+	if p.GroupBy == nil {
+		defaultGroupBy := GroupByNone
+		p.GroupBy = &defaultGroupBy
+	}
+
+	return nil
+}
+
+// ------------------------------------------------------------
+
+type GetViewsPayload struct{}
+
+func (p *GetViewsPayload) Validate() error {
+	return nil
+}
+
+// ------------------------------------------------------------
+
+type GetSystemViewsPayload struct{}
+
+func (p *GetSystemViewsPayload) Validate() error {
+	return nil
+}
+
+// ------------------------------------------------------------
+
+type GetViewByIDPayload struct {
+	ID uuid.UUID `param:"id" validate:"required,uuid"`
+}
+
+func (p *GetViewByIDPayload) Validate() error {
+	validate := validator.New()
+	return validate.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+// UpdateViewPayload changes the fields given. Filters, when given, replace
+// all of the view's filters.
+type UpdateViewPayload struct {
+	ID      uuid.UUID         `param:"id" validate:"required,uuid"`
+	Name    *string           `json:"name" validate:"omitempty,min=1,max=100"`
+	Filters *todo.TodoFilters `json:"filters"`
+	Sort    *string           `json:"sort" validate:"omitempty,oneof=created_at updated_at title priority due_date status sort_order"`
+	Order   *string           `json:"order" validate:"omitempty,oneof=asc desc"`
+	GroupBy *GroupBy          `json:"groupBy" validate:"omitempty,oneof=none category status priority due_date assignee"`
+	Shared  *bool             `json:"shared"`
+}
+
+func (p *UpdateViewPayload) Validate() error {
+	validate := validator.New()
+
+	if err := validate.Struct(p); err != nil {
+		return err
+	}
+
+	if p.Filters != nil {
+		return p.Filters.ValidateQuery("filters.q")
+	}
+
+	return nil
+}
+
+// ------------------------------------------------------------
+
+type DeleteViewPayload struct {
+	ID uuid.UUID `param:"id" validate:"required,uuid"`
+}
+
+func (p *DeleteViewPayload) Validate() error {
+	validate := validator.New()
+	return validate.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+type PinViewPayload struct {
+	ID uuid.UUID `param:"id" validate:"required,uuid"`
+}
+
+func (p *PinViewPayload) Validate() error {
+	validate := validator.New()
+	return validate.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+type UnpinViewPayload struct {
+	ID uuid.UUID `param:"id" validate:"required,uuid"`
+}
+
+func (p *UnpinViewPayload) Validate() error {
+	validate := validator.New()
+	return validate.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+// TodosPage holds the paging parameters of a view's todos, which work as
+// they do on the todo list.
+type TodosPage struct {
+	Page       *int    `query:"page" validate:"omitempty,min=1"`
+	Limit      *int    `query:"limit" validate:"omitempty,min=1,max=100"`
+	Cursor     *string `query:"cursor" validate:"omitempty,max=1024"`
+	Pagination *string `query:"pagination" validate:"omitempty,oneof=offset cursor"`
+	Count      *bool   `query:"count"`
+	Include    *string `query:"include" validate:"omitempty,max=255"`
+}
+
+// TodosQuery returns the todo list query running a view with these paging
+// parameters. Its defaults are filled in the way the todo list's are.
+func (p *TodosPage) TodosQuery(filters todo.TodoFilters, sort, order string) (*todo.GetTodosQuery, error) {
+	query := &todo.GetTodosQuery{
+		Page:        p.Page,
+		Limit:       p.Limit,
+		Sort:        &sort,
+		Order:       &order,
+		TodoFilters: filters,
+		Cursor:      p.Cursor,
+		Pagination:  p.Pagination,
+		Count:       p.Count,
+		Include:     p.Include,
+	}
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+	return query, nil
+}
+
+type GetViewTodosQuery struct {
+	ID uuid.UUID `param:"id" validate:"required,uuid"`
+	TodosPage
+}
+
+func (q *GetViewTodosQuery) Validate() error {
+	validate := validator.New()
+	return validate.Struct(q)
+}
+
+type GetSystemViewTodosQuery struct {
+	Key SystemViewKey `param:"key" validate:"required,oneof=today upcoming no_due_date"`
+	// Timezone is the IANA time zone days are counted in, UTC by default
+	Timezone *string `query:"timezone" validate:"omitempty,timezone"`
+	TodosPage
+}
+
+func (q *GetSystemViewTodosQuery) Validate() error {
+	validate := validator.New()
+	return validate.Struct(q)
+}
@@ -0,0 +1,98 @@
+package view
+
+import (
+	"time"
+
+	"github.com/uttam282005/tasker/internal/model"
+	"github.com/uttam282005/tasker/internal/model/todo"
+)
+
+// GroupBy is how clients section the todos of a view. The todos come back
+// in the view's sort order either way.
+type GroupBy string
+
+const (
+	GroupByNone     GroupBy = "none"
+	GroupByCategory GroupBy = "category"
+	GroupByStatus   GroupBy = "status"
+	GroupByPriority GroupBy = "priority"
+	GroupByDueDate  GroupBy = "due_date"
+	GroupByAssignee GroupBy = "assignee"
+)
+
+// View is a saved todo list query. It's private to its creator unless
+// shared with the rest of the organization.
+type View struct {
+	model.Base
+
+	UserID  string           `json:"userId" db:"user_id"`
+	OrgID   *string          `json:"orgId" db:"org_id"`
+	Name    string           `json:"name" db:"name"`
+	Filters todo.TodoFilters `json:"filters" db:"filters"`
+	Sort    string           `json:"sort" db:"sort"`
+	Order   string           `json:"order" db:"sort_direction"`
+	GroupBy GroupBy          `json:"groupBy" db:"group_by"`
+	Shared  bool             `json:"shared" db:"shared"`
+	// Pinned tells whether the requesting user pinned the view
+	Pinned bool `json:"pinned" db:"pinned"`
+}
+
+type SystemViewKey string
+
+const (
+	SystemViewToday     SystemViewKey = "today"
+	SystemViewUpcoming  SystemViewKey = "upcoming"
+	SystemViewNoDueDate SystemViewKey = "no_due_date"
+)
+
+// SystemView is a built-in view every user has. Its due date filters move
+// with the calendar, so they're worked out each time it's opened.
+type SystemView struct {
+	Key     SystemViewKey `json:"key"`
+	Name    string        `json:"name"`
+	Sort    string        `json:"sort"`
+	Order   string        `json:"order"`
+	GroupBy GroupBy       `json:"groupBy"`
+}
+
+var SystemViews = []SystemView{
+	{Key: SystemViewToday, Name: "Today", Sort: "due_date", Order: "asc", GroupBy: GroupByNone},
+	{Key: SystemViewUpcoming, Name: "Upcoming 7 days", Sort: "due_date", Order: "asc", GroupBy: GroupByDueDate},
+	{Key: SystemViewNoDueDate, Name: "No due date", Sort: "created_at", Order: "desc", GroupBy: GroupByCategory},
+}
+
+func GetSystemView(key SystemViewKey) *SystemView {
+	for i := range SystemViews {
+		if SystemViews[i].Key == key {
+			return &SystemViews[i]
+		}
+	}
+	return nil
+}
+
+// Filters returns the filters of the view as of now, in the given time
+// zone. Today takes in overdue todos, Upcoming runs to the end of the
+// seventh day from today, and neither lists completed todos.
+func (v *SystemView) Filters(now time.Time) todo.TodoFilters {
+	notCompleted := false
+	filters := todo.TodoFilters{Completed: &notCompleted}
+
+	startOfToday := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	endOfDay := func(days int) *time.Time {
+		end := startOfToday.AddDate(0, 0, days).Add(-time.Microsecond)
+		return &end
+	}
+
+	switch v.Key {
+	case SystemViewToday:
+		filters.DueTo = endOfDay(1)
+	case SystemViewUpcoming:
+		filters.DueFrom = &startOfToday
+		filters.DueTo = endOfDay(7)
+	case SystemViewNoDueDate:
+		hasDueDate := false
+		filters.HasDueDate = &hasDueDate
+	}
+
+	return filters
+}
@@ -0,0 +1,105 @@
+package webhook
+
+import (
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+// ------------------------------------------------------------
+
+type CreateWebhookPayload struct {
+	URL    string  `json:"url" validate:"required,http_url,max=2048"`
+	Events []Event `json:"events" validate:"required,min=1,dive,oneof=todo.created todo.updated todo.completed comment.added attachment.uploaded"`
+	// Secret signs deliveries; one is generated when omitted
+	Secret *string `json:"secret" validate:"omitempty,min=16,max=255"`
+}
+
+func (p *CreateWebhookPayload) Validate() error {
+	validate := validator.New()
+	return validate.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+type GetWebhooksPayload struct{}
+
+func (p *GetWebhooksPayload) Validate() error {
+	return nil
+}
+
+// ------------------------------------------------------------
+
+type GetWebhookByIDPayload struct {
+	ID uuid.UUID `param:"id" validate:"required,uuid"`
+}
+
+func (p *GetWebhookByIDPayload) Validate() error {
+	validate := validator.New()
+	return validate.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+type UpdateWebhookPayload struct {
+	ID     uuid.UUID `param:"id" validate:"required,uuid"`
+	URL    *string   `json:"url" validate:"omitempty,http_url,max=2048"`
+	Events []Event   `json:"events" validate:"omitempty,min=1,dive,oneof=todo.created todo.updated todo.completed comment.added attachment.uploaded"`
+	Active *bool     `json:"active"`
+}
+
+func (p *UpdateWebhookPayload) Validate() error {
+	validate := validator.New()
+	return validate.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+type DeleteWebhookPayload struct {
+	ID uuid.UUID `param:"id" validate:"required,uuid"`
+}
+
+func (p *DeleteWebhookPayload) Validate() error {
+	validate := validator.New()
+	return validate.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+type GetDeliveriesQuery struct {
+	WebhookID uuid.UUID       `param:"id" validate:"required,uuid"`
+	Page      *int            `query:"page" validate:"omitempty,min=1"`
+	Limit     *int            `query:"limit" validate:"omitempty,min=1,max=100"`
+	Status    *DeliveryStatus `query:"status" validate:"omitempty,oneof=pending succeeded failed"`
+}
+
+func (q *GetDeliveriesQuery) Validate() error {
+	validate := validator.New()
+
+	if err := validate.Struct(q); err != nil {
+		return err
+	}
+
+	// Set defaults for pagination
+	if q.Page == nil {
+		defaultPage := 1
+		q.Page = &defaultPage
+	}
+	if q.Limit == nil {
+		defaultLimit := 20
+		q.Limit = &defaultLimit
+	}
+
+	return nil
+}
+
+// ------------------------------------------------------------
+
+type RedeliverPayload struct {
+	WebhookID  uuid.UUID `param:"id" validate:"required,uuid"`
+	DeliveryID uuid.UUID `param:"deliveryId" validate:"required,uuid"`
+}
+
+func (p *RedeliverPayload) Validate() error {
+	validate := validator.New()
+	return validate.Struct(p)
+}
@@ -0,0 +1,73 @@
+package webhook
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uttam282005/tasker/internal/model"
+)
+
+// Event is the type of change a webhook can subscribe to.
+type Event string
+
+const (
+	EventTodoCreated        Event = "todo.created"
+	EventTodoUpdated        Event = "todo.updated"
+	EventTodoCompleted      Event = "todo.completed"
+	EventCommentAdded       Event = "comment.added"
+	EventAttachmentUploaded Event = "attachment.uploaded"
+)
+
+type DeliveryStatus string
+
+const (
+	DeliveryStatusPending   DeliveryStatus = "pending"
+	DeliveryStatusSucceeded DeliveryStatus = "succeeded"
+	DeliveryStatusFailed    DeliveryStatus = "failed"
+)
+
+// Webhook is an endpoint that receives the events it subscribes to. The
+// secret is only returned once, when the webhook is created.
+type Webhook struct {
+	model.Base
+	UserID string  `json:"userId" db:"user_id"`
+	OrgID  *string `json:"orgId" db:"org_id"`
+	URL    string  `json:"url" db:"url"`
+	Secret string  `json:"-" db:"secret"`
+	Events []Event `json:"events" db:"events"`
+	Active bool    `json:"active" db:"active"`
+}
+
+// CreatedWebhook is returned when a webhook is registered and is the only
+// response that carries its signing secret.
+type CreatedWebhook struct {
+	Webhook
+	Secret string `json:"secret"`
+}
+
+// Delivery is a single event sent to a webhook, along with the outcome of
+// its latest attempt. Only the first few hundred bytes of the endpoint's
+// response are kept.
+type Delivery struct {
+	model.Base
+	WebhookID    uuid.UUID       `json:"webhookId" db:"webhook_id"`
+	Event        Event           `json:"event" db:"event"`
+	Payload      json.RawMessage `json:"payload" db:"payload"`
+	Status       DeliveryStatus  `json:"status" db:"status"`
+	Attempts     int             `json:"attempts" db:"attempts"`
+	ResponseCode *int            `json:"responseCode" db:"response_code"`
+	ResponseBody *string         `json:"responseBody" db:"response_body"`
+	Error        *string         `json:"error" db:"error"`
+	DurationMs   *int            `json:"durationMs" db:"duration_ms"`
+	DeliveredAt  *time.Time      `json:"deliveredAt" db:"delivered_at"`
+}
+
+// Envelope is the JSON body sent for every delivery. Data holds the todo,
+// comment or attachment the event is about.
+type Envelope struct {
+	ID        uuid.UUID       `json:"id"`
+	Event     Event           `json:"event"`
+	CreatedAt time.Time       `json:"createdAt"`
+	Data      json.RawMessage `json:"data"`
+}
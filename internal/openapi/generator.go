@@ -0,0 +1,321 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/uttam282005/tasker/internal/errs"
+)
+
+// Route documents a single registered endpoint. Request is the payload type
+// handed to handler.Handle and Response the value it returns.
+type Route struct {
+	Method    string
+	Path      string
+	Summary   string
+	Tag       string
+	Request   any
+	Response  any
+	Status    int
+	Multipart bool
+	Public    bool
+	// Idempotent routes accept an Idempotency-Key header
+	Idempotent bool
+
+	// RequestContentType overrides the request body media type
+	RequestContentType string
+	// ContentType overrides the response media type for file responses
+	ContentType string
+	// ContentTypes lists the media types of file responses served in more
+	// than one format
+	ContentTypes []string
+	// Schema overrides the response schema for handlers that don't return a typed value
+	Schema *Schema
+}
+
+func (r Route) key() string {
+	return r.Method + " " + r.Path
+}
+
+var documentedMethods = map[string]bool{
+	http.MethodGet:    true,
+	http.MethodPost:   true,
+	http.MethodPut:    true,
+	http.MethodPatch:  true,
+	http.MethodDelete: true,
+}
+
+// ignoredPaths are served by the router but intentionally left out of the spec
+var ignoredPaths = map[string]bool{
+	"/docs": true,
+}
+
+// Generate builds the OpenAPI document for the given Echo routes. Every
+// route must have a matching entry in Routes and vice versa, so adding an
+// endpoint without documenting it fails generation.
+func Generate(echoRoutes []*echo.Route) (*Document, error) {
+	documented := make(map[string]Route, len(Routes))
+	for _, route := range Routes {
+		documented[route.key()] = route
+	}
+
+	registry := newSchemaRegistry()
+	doc := &Document{
+		OpenAPI: Version,
+		Info: Info{
+			Title:       "Tasker REST API",
+			Description: "Tasker REST API - Documentation",
+			Version:     "1.0.0",
+		},
+		Servers: []Server{
+			{URL: "http://localhost:8080", Description: "Local Server"},
+		},
+		Paths: map[string]*PathItem{},
+		Components: Components{
+			SecuritySchemes: map[string]*SecurityScheme{
+				"bearerAuth": {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+			},
+		},
+	}
+
+	var undocumented []string
+	seen := map[string]bool{}
+	for _, er := range echoRoutes {
+		if !documentedMethods[er.Method] || ignoredPaths[er.Path] || strings.HasSuffix(er.Path, "*") {
+			continue
+		}
+
+		key := er.Method + " " + er.Path
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		route, ok := documented[key]
+		if !ok {
+			undocumented = append(undocumented, key)
+			continue
+		}
+
+		path := openAPIPath(route.Path)
+		item, ok := doc.Paths[path]
+		if !ok {
+			item = &PathItem{}
+			doc.Paths[path] = item
+		}
+		(*item)[strings.ToLower(route.Method)] = buildOperation(registry, route)
+	}
+
+	var stale []string
+	for key := range documented {
+		if !seen[key] {
+			stale = append(stale, key)
+		}
+	}
+
+	if len(undocumented) > 0 || len(stale) > 0 {
+		sort.Strings(undocumented)
+		sort.Strings(stale)
+		return nil, fmt.Errorf("openapi routes out of sync: undocumented=%v unregistered=%v", undocumented, stale)
+	}
+
+	registry.schemaFor(reflect.TypeOf(errs.HTTPError{}))
+	doc.Components.Schemas = registry.schemas
+
+	return doc, nil
+}
+
+// Marshal renders the document the way it is committed to static/openapi.json.
+func Marshal(doc *Document) ([]byte, error) {
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal openapi document: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+func buildOperation(registry *schemaRegistry, route Route) *Operation {
+	op := &Operation{
+		OperationID: operationID(route),
+		Summary:     route.Summary,
+		Responses:   map[string]*Response{},
+	}
+	if route.Tag != "" {
+		op.Tags = []string{route.Tag}
+	}
+	if !route.Public {
+		op.Security = []map[string][]string{{"bearerAuth": {}}}
+	}
+
+	headers := map[string]bool{}
+	if route.Request != nil {
+		reqType := indirect(reflect.TypeOf(route.Request))
+
+		for _, f := range fieldsOf(reqType, fieldsParam) {
+			op.Parameters = append(op.Parameters, &Parameter{
+				Name:     f.name,
+				In:       "path",
+				Required: true,
+				Schema:   registry.paramSchema(f),
+			})
+		}
+		for _, f := range fieldsOf(reqType, fieldsQuery) {
+			op.Parameters = append(op.Parameters, &Parameter{
+				Name:     f.name,
+				In:       "query",
+				Required: f.has("required"),
+				Schema:   registry.paramSchema(f),
+			})
+		}
+		for _, f := range fieldsOf(reqType, fieldsHeader) {
+			op.Parameters = append(op.Parameters, &Parameter{
+				Name:     f.name,
+				In:       "header",
+				Required: f.has("required"),
+				Schema:   registry.paramSchema(f),
+			})
+			headers[f.name] = true
+		}
+
+		if route.Multipart {
+			form := &Schema{
+				Type:       "object",
+				Properties: map[string]*Schema{"file": {Type: "string", Format: "binary"}},
+				Required:   []string{"file"},
+			}
+			for _, f := range fieldsOf(reqType, fieldsForm) {
+				form.Properties[f.name] = registry.paramSchema(f)
+				if f.has("required") {
+					form.Required = append(form.Required, f.name)
+				}
+			}
+			op.RequestBody = &RequestBody{
+				Required: true,
+				Content:  map[string]*MediaType{"multipart/form-data": {Schema: form}},
+			}
+		} else if body := registry.requestSchema(reqType); body != nil && route.Method != http.MethodGet {
+			// A GET has no body, even if its query fields have JSON names
+			// for being stored, such as the todo filters of saved views
+			contentType := route.RequestContentType
+			if contentType == "" {
+				contentType = echo.MIMEApplicationJSON
+			}
+			op.RequestBody = &RequestBody{
+				Required: true,
+				Content:  map[string]*MediaType{contentType: {Schema: body}},
+			}
+		}
+	}
+
+	if route.Idempotent {
+		maxLength := 255
+		op.Parameters = append(op.Parameters, &Parameter{
+			Name:   "Idempotency-Key",
+			In:     "header",
+			Schema: &Schema{Type: "string", MaxLength: &maxLength},
+		})
+	}
+
+	status := route.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	success := &Response{Description: http.StatusText(status)}
+	switch {
+	case route.Schema != nil:
+		success.Content = map[string]*MediaType{echo.MIMEApplicationJSON: {Schema: route.Schema}}
+	case route.ContentType != "":
+		success.Content = map[string]*MediaType{route.ContentType: {Schema: &Schema{Type: "string", Format: "binary"}}}
+	case len(route.ContentTypes) > 0:
+		success.Content = map[string]*MediaType{}
+		for _, contentType := range route.ContentTypes {
+			success.Content[contentType] = &MediaType{Schema: &Schema{Type: "string", Format: "binary"}}
+		}
+	case route.Response != nil:
+		success.Content = map[string]*MediaType{
+			echo.MIMEApplicationJSON: {Schema: registry.schemaFor(indirect(reflect.TypeOf(route.Response)))},
+		}
+	}
+	op.Responses[strconv.Itoa(status)] = success
+
+	// Conditional requests answer a stale If-Match with the current
+	// representation and a fresh If-None-Match with no body at all
+	if headers["If-None-Match"] {
+		op.Responses[strconv.Itoa(http.StatusNotModified)] = &Response{
+			Description: http.StatusText(http.StatusNotModified),
+		}
+	}
+	if headers["If-Match"] {
+		op.Responses[strconv.Itoa(http.StatusPreconditionFailed)] = &Response{
+			Description: http.StatusText(http.StatusPreconditionFailed),
+		}
+	}
+
+	errorStatuses := []int{http.StatusInternalServerError}
+	if op.RequestBody != nil || len(op.Parameters) > 0 {
+		errorStatuses = append(errorStatuses, http.StatusBadRequest)
+	}
+	if !route.Public {
+		errorStatuses = append(errorStatuses, http.StatusUnauthorized)
+	}
+	if strings.Contains(route.Path, ":") {
+		errorStatuses = append(errorStatuses, http.StatusNotFound)
+	}
+	if route.Idempotent {
+		errorStatuses = append(errorStatuses, http.StatusConflict)
+	}
+	for _, s := range errorStatuses {
+		op.Responses[strconv.Itoa(s)] = &Response{
+			Description: http.StatusText(s),
+			Content: map[string]*MediaType{
+				echo.MIMEApplicationJSON: {Schema: &Schema{Ref: "#/components/schemas/HTTPError"}},
+			},
+		}
+	}
+
+	return op
+}
+
+func indirect(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+// openAPIPath converts Echo's :param segments to OpenAPI {param} templates.
+func openAPIPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, s := range segments {
+		if strings.HasPrefix(s, ":") {
+			segments[i] = "{" + strings.TrimPrefix(s, ":") + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// operationID derives a camelCase identifier from the method and path,
+// e.g. GET /api/v1/todos/:id/comments -> getTodosByIdComments.
+func operationID(route Route) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(route.Method))
+	for _, s := range strings.Split(strings.TrimPrefix(route.Path, "/api/v1"), "/") {
+		if s == "" {
+			continue
+		}
+		if strings.HasPrefix(s, ":") {
+			b.WriteString("By")
+			s = strings.TrimPrefix(s, ":")
+		}
+		for _, part := range strings.FieldsFunc(s, func(r rune) bool { return r == '-' || r == '_' || r == '.' }) {
+			b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+		}
+	}
+	return b.String()
+}
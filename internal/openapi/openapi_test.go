@@ -0,0 +1,41 @@
+package openapi_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+	"github.com/uttam282005/tasker/internal/config"
+	"github.com/uttam282005/tasker/internal/handler"
+	"github.com/uttam282005/tasker/internal/openapi"
+	"github.com/uttam282005/tasker/internal/router"
+	"github.com/uttam282005/tasker/internal/server"
+	"github.com/uttam282005/tasker/internal/service"
+	testhelpers "github.com/uttam282005/tasker/internal/testing"
+)
+
+// TestCommittedSpecIsCurrent regenerates the document the way cmd/openapi
+// does and fails when static/openapi.json no longer matches the routes.
+func TestCommittedSpecIsCurrent(t *testing.T) {
+	logger := zerolog.Nop()
+	srv := &server.Server{
+		Config: &config.Config{},
+		Logger: &logger,
+	}
+	services := &service.Services{}
+	r := router.NewRouter(srv, handler.NewHandlers(srv, services), services)
+
+	doc, err := openapi.Generate(r.Routes())
+	require.NoError(t, err)
+
+	generated, err := openapi.Marshal(doc)
+	require.NoError(t, err)
+
+	committed, err := os.ReadFile(filepath.Join(testhelpers.ProjectRoot(t), "static", "openapi.json"))
+	require.NoError(t, err)
+
+	require.Equal(t, string(committed), string(generated),
+		"static/openapi.json is out of date, run `task openapi:generate`")
+}
@@ -0,0 +1,811 @@
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+
+	"github.com/uttam282005/tasker/internal/lib/ical"
+	"github.com/uttam282005/tasker/internal/lib/mergepatch"
+	"github.com/uttam282005/tasker/internal/model"
+	"github.com/uttam282005/tasker/internal/model/activity"
+	"github.com/uttam282005/tasker/internal/model/calendar"
+	"github.com/uttam282005/tasker/internal/model/category"
+	"github.com/uttam282005/tasker/internal/model/comment"
+	"github.com/uttam282005/tasker/internal/model/event"
+	"github.com/uttam282005/tasker/internal/model/imports"
+	"github.com/uttam282005/tasker/internal/model/search"
+	"github.com/uttam282005/tasker/internal/model/share"
+	"github.com/uttam282005/tasker/internal/model/tag"
+	"github.com/uttam282005/tasker/internal/model/todo"
+	"github.com/uttam282005/tasker/internal/model/view"
+	"github.com/uttam282005/tasker/internal/model/webhook"
+)
+
+// Enums lists the allowed values of named string types used in responses.
+var Enums = map[reflect.Type][]string{
+	reflect.TypeOf(todo.Status("")): {
+		string(todo.StatusDraft),
+		string(todo.StatusActive),
+		string(todo.StatusCompleted),
+		string(todo.StatusArchived),
+	},
+	reflect.TypeOf(todo.Priority("")): {
+		string(todo.PriorityLow),
+		string(todo.PriorityMedium),
+		string(todo.PriorityHigh),
+	},
+	reflect.TypeOf(todo.ExportFormat("")): {
+		string(todo.ExportFormatCSV),
+		string(todo.ExportFormatJSON),
+		string(todo.ExportFormatMarkdown),
+	},
+	reflect.TypeOf(todo.BulkAction("")): {
+		string(todo.BulkActionSetStatus),
+		string(todo.BulkActionSetPriority),
+		string(todo.BulkActionMoveCategory),
+		string(todo.BulkActionSetDueDate),
+		string(todo.BulkActionArchive),
+		string(todo.BulkActionDelete),
+	},
+	reflect.TypeOf(todo.BulkMode("")): {
+		string(todo.BulkModeAtomic),
+		string(todo.BulkModeBestEffort),
+	},
+	reflect.TypeOf(todo.BulkResultStatus("")): {
+		string(todo.BulkResultSucceeded),
+		string(todo.BulkResultFailed),
+		string(todo.BulkResultSkipped),
+	},
+	reflect.TypeOf(activity.EntityType("")): {
+		string(activity.EntityTypeTodo),
+		string(activity.EntityTypeComment),
+		string(activity.EntityTypeAttachment),
+		string(activity.EntityTypeCategory),
+	},
+	reflect.TypeOf(activity.Action("")): {
+		string(activity.ActionCreated),
+		string(activity.ActionUpdated),
+		string(activity.ActionDeleted),
+		string(activity.ActionRestored),
+	},
+	reflect.TypeOf(search.HitType("")): {
+		string(search.HitTypeTodo),
+		string(search.HitTypeComment),
+	},
+	reflect.TypeOf(share.Role("")): {
+		string(share.RoleViewer),
+		string(share.RoleCommenter),
+		string(share.RoleEditor),
+	},
+	reflect.TypeOf(webhook.Event("")): {
+		string(webhook.EventTodoCreated),
+		string(webhook.EventTodoUpdated),
+		string(webhook.EventTodoCompleted),
+		string(webhook.EventCommentAdded),
+		string(webhook.EventAttachmentUploaded),
+	},
+	reflect.TypeOf(webhook.DeliveryStatus("")): {
+		string(webhook.DeliveryStatusPending),
+		string(webhook.DeliveryStatusSucceeded),
+		string(webhook.DeliveryStatusFailed),
+	},
+	reflect.TypeOf(calendar.Component("")): {
+		string(calendar.ComponentTodo),
+		string(calendar.ComponentEvent),
+	},
+	reflect.TypeOf(imports.Source("")): {
+		string(imports.SourceTodoist),
+		string(imports.SourceTrello),
+		string(imports.SourceCSV),
+	},
+	reflect.TypeOf(imports.Status("")): {
+		string(imports.StatusPending),
+		string(imports.StatusRunning),
+		string(imports.StatusCompleted),
+		string(imports.StatusFailed),
+	},
+	reflect.TypeOf(view.GroupBy("")): {
+		string(view.GroupByNone),
+		string(view.GroupByCategory),
+		string(view.GroupByStatus),
+		string(view.GroupByPriority),
+		string(view.GroupByDueDate),
+		string(view.GroupByAssignee),
+	},
+	reflect.TypeOf(view.SystemViewKey("")): {
+		string(view.SystemViewToday),
+		string(view.SystemViewUpcoming),
+		string(view.SystemViewNoDueDate),
+	},
+}
+
+// Routes lists every documented endpoint. Keep it in sync with the router;
+// Generate fails when a registered route is missing here or vice versa.
+var Routes = []Route{
+	// ------------------------------------------------------------
+	// System
+	// ------------------------------------------------------------
+	{
+		Method:  http.MethodGet,
+		Path:    "/status",
+		Summary: "Get health status",
+		Tag:     "Health",
+		Public:  true,
+		Schema: &Schema{
+			Type: "object",
+			Properties: map[string]*Schema{
+				"status":      {Type: "string", Enum: []string{"healthy", "unhealthy"}},
+				"timestamp":   {Type: "string", Format: "date-time"},
+				"environment": {Type: "string"},
+				"checks":      {Type: "object", AdditionalProperties: &Schema{Type: "object"}},
+			},
+			Required: []string{"status", "timestamp", "environment", "checks"},
+		},
+	},
+
+	// ------------------------------------------------------------
+	// Todos
+	// ------------------------------------------------------------
+	{
+		Method:     http.MethodPost,
+		Path:       "/api/v1/todos",
+		Summary:    "Create a todo",
+		Tag:        "Todos",
+		Request:    todo.CreateTodoPayload{},
+		Response:   todo.Todo{},
+		Status:     http.StatusCreated,
+		Idempotent: true,
+	},
+	{
+		Method:   http.MethodGet,
+		Path:     "/api/v1/todos",
+		Summary:  "List todos",
+		Tag:      "Todos",
+		Request:  todo.GetTodosQuery{},
+		Response: model.CursorPaginatedResponse[todo.PopulatedTodo]{},
+	},
+	{
+		Method:   http.MethodGet,
+		Path:     "/api/v1/todos/stats",
+		Summary:  "Get todo statistics",
+		Tag:      "Todos",
+		Request:  todo.GetTodoStatsPayload{},
+		Response: todo.TodoStats{},
+	},
+	{
+		Method:  http.MethodGet,
+		Path:    "/api/v1/todos/export",
+		Summary: "Export todos with their subtasks and comments as CSV, JSON or Markdown",
+		Tag:     "Todos",
+		Request: todo.ExportTodosQuery{},
+		ContentTypes: []string{
+			todo.ExportFormatCSV.ContentType(),
+			todo.ExportFormatJSON.ContentType(),
+			todo.ExportFormatMarkdown.ContentType(),
+		},
+	},
+	{
+		Method:   http.MethodGet,
+		Path:     "/api/v1/todos/trash",
+		Summary:  "List trashed todos",
+		Tag:      "Trash",
+		Request:  todo.GetTrashedTodosQuery{},
+		Response: model.PaginatedResponse[todo.Todo]{},
+	},
+	{
+		Method:   http.MethodPost,
+		Path:     "/api/v1/todos/bulk",
+		Summary:  "Apply one action to several todos",
+		Tag:      "Todos",
+		Request:  todo.BulkTodosPayload{},
+		Response: todo.BulkResponse{},
+	},
+
+	{
+		Method:   http.MethodGet,
+		Path:     "/api/v1/todos/:id",
+		Summary:  "Get a todo",
+		Tag:      "Todos",
+		Request:  todo.GetTodoByIDPayload{},
+		Response: todo.PopulatedTodo{},
+	},
+	{
+		Method:   http.MethodPut,
+		Path:     "/api/v1/todos/:id",
+		Summary:  "Update a todo",
+		Tag:      "Todos",
+		Request:  todo.UpdateTodoPayload{},
+		Response: todo.Todo{},
+	},
+	{
+		Method:             http.MethodPatch,
+		Path:               "/api/v1/todos/:id",
+		Summary:            "Patch a todo (null clears a field)",
+		Tag:                "Todos",
+		Request:            todo.PatchTodoPayload{},
+		RequestContentType: mergepatch.MIMEApplicationMergePatchJSON,
+		Response:           todo.Todo{},
+	},
+	{
+		Method:  http.MethodDelete,
+		Path:    "/api/v1/todos/:id",
+		Summary: "Delete a todo",
+		Tag:     "Todos",
+		Request: todo.DeleteTodoPayload{},
+		Status:  http.StatusNoContent,
+	},
+	{
+		Method:   http.MethodPost,
+		Path:     "/api/v1/todos/:id/move",
+		Summary:  "Move a todo before or after a sibling, or to a position",
+		Tag:      "Todos",
+		Request:  todo.MoveTodoPayload{},
+		Response: todo.Todo{},
+	},
+	{
+		Method:   http.MethodPost,
+		Path:     "/api/v1/todos/:id/restore",
+		Summary:  "Restore a todo and the subtasks trashed with it",
+		Tag:      "Trash",
+		Request:  todo.RestoreTodoPayload{},
+		Response: todo.Todo{},
+	},
+
+	// ------------------------------------------------------------
+	// Attachments
+	// ------------------------------------------------------------
+	{
+		Method:     http.MethodPost,
+		Path:       "/api/v1/todos/:id/attachments",
+		Summary:    "Upload a todo attachment",
+		Tag:        "Attachments",
+		Request:    todo.UploadTodoAttachmentPayload{},
+		Response:   todo.TodoAttachment{},
+		Status:     http.StatusCreated,
+		Multipart:  true,
+		Idempotent: true,
+	},
+	{
+		Method:  http.MethodDelete,
+		Path:    "/api/v1/todos/:id/attachments/:attachmentId",
+		Summary: "Delete a todo attachment",
+		Tag:     "Attachments",
+		Request: todo.DeleteTodoAttachmentPayload{},
+		Status:  http.StatusNoContent,
+	},
+	{
+		Method:  http.MethodGet,
+		Path:    "/api/v1/todos/:id/attachments/:attachmentId/download",
+		Summary: "Get a presigned download URL for an attachment",
+		Tag:     "Attachments",
+		Request: todo.GetAttachmentPresignedURLPayload{},
+		Response: struct {
+			URL string `json:"url"`
+		}{},
+	},
+
+	// ------------------------------------------------------------
+	// Activity
+	// ------------------------------------------------------------
+	{
+		Method:   http.MethodGet,
+		Path:     "/api/v1/todos/:id/activity",
+		Summary:  "List a todo's activity, newest first",
+		Tag:      "Activity",
+		Request:  activity.GetTodoActivityQuery{},
+		Response: model.PaginatedResponse[activity.Activity]{},
+	},
+
+	// ------------------------------------------------------------
+	// Dependencies
+	// ------------------------------------------------------------
+	{
+		Method:   http.MethodPost,
+		Path:     "/api/v1/todos/:id/blockers",
+		Summary:  "Mark a todo as blocked by another todo",
+		Tag:      "Dependencies",
+		Request:  todo.AddTodoBlockerPayload{},
+		Response: todo.TodoDependency{},
+		Status:   http.StatusCreated,
+	},
+	{
+		Method:  http.MethodDelete,
+		Path:    "/api/v1/todos/:id/blockers/:blockerId",
+		Summary: "Remove a blocker from a todo",
+		Tag:     "Dependencies",
+		Request: todo.RemoveTodoBlockerPayload{},
+		Status:  http.StatusNoContent,
+	},
+
+	// ------------------------------------------------------------
+	// Watchers
+	// ------------------------------------------------------------
+	{
+		Method:   http.MethodPost,
+		Path:     "/api/v1/todos/:id/watchers",
+		Summary:  "Watch a todo, or subscribe another member to it",
+		Tag:      "Watchers",
+		Request:  todo.AddTodoWatcherPayload{},
+		Response: todo.TodoWatcher{},
+		Status:   http.StatusCreated,
+	},
+	{
+		Method:  http.MethodDelete,
+		Path:    "/api/v1/todos/:id/watchers/:userId",
+		Summary: "Stop watching a todo",
+		Tag:     "Watchers",
+		Request: todo.RemoveTodoWatcherPayload{},
+		Status:  http.StatusNoContent,
+	},
+
+	// ------------------------------------------------------------
+	// Comments
+	// ------------------------------------------------------------
+	{
+		Method:     http.MethodPost,
+		Path:       "/api/v1/todos/:id/comments",
+		Summary:    "Add a comment to a todo",
+		Tag:        "Comments",
+		Request:    comment.AddCommentPayload{},
+		Response:   comment.Comment{},
+		Status:     http.StatusCreated,
+		Idempotent: true,
+	},
+	{
+		Method:   http.MethodGet,
+		Path:     "/api/v1/todos/:id/comments",
+		Summary:  "List comments on a todo",
+		Tag:      "Comments",
+		Request:  comment.GetCommentsByTodoIDPayload{},
+		Response: []comment.Comment{},
+	},
+	{
+		Method:   http.MethodPut,
+		Path:     "/api/v1/comments/:id",
+		Summary:  "Update a comment",
+		Tag:      "Comments",
+		Request:  comment.UpdateCommentPayload{},
+		Response: comment.Comment{},
+	},
+	{
+		Method:  http.MethodDelete,
+		Path:    "/api/v1/comments/:id",
+		Summary: "Delete a comment",
+		Tag:     "Comments",
+		Request: comment.DeleteCommentPayload{},
+		Status:  http.StatusNoContent,
+	},
+	{
+		Method:   http.MethodPost,
+		Path:     "/api/v1/comments/:id/restore",
+		Summary:  "Restore a trashed comment",
+		Tag:      "Trash",
+		Request:  comment.RestoreCommentPayload{},
+		Response: comment.Comment{},
+	},
+	{
+		Method:   http.MethodGet,
+		Path:     "/api/v1/comments/trash",
+		Summary:  "List trashed comments",
+		Tag:      "Trash",
+		Request:  comment.GetTrashedCommentsQuery{},
+		Response: model.PaginatedResponse[comment.Comment]{},
+	},
+
+	// ------------------------------------------------------------
+	// Categories
+	// ------------------------------------------------------------
+	{
+		Method:   http.MethodPost,
+		Path:     "/api/v1/categories",
+		Summary:  "Create a category",
+		Tag:      "Categories",
+		Request:  category.CreateCategoryPayload{},
+		Response: category.Category{},
+		Status:   http.StatusCreated,
+	},
+	{
+		Method:   http.MethodGet,
+		Path:     "/api/v1/categories",
+		Summary:  "List categories",
+		Tag:      "Categories",
+		Request:  category.GetCategoriesQuery{},
+		Response: model.CursorPaginatedResponse[category.Category]{},
+	},
+	{
+		Method:   http.MethodGet,
+		Path:     "/api/v1/categories/:id",
+		Summary:  "Get a category",
+		Tag:      "Categories",
+		Request:  category.GetCategoryByIDPayload{},
+		Response: category.Category{},
+	},
+	{
+		Method:   http.MethodPut,
+		Path:     "/api/v1/categories/:id",
+		Summary:  "Update a category",
+		Tag:      "Categories",
+		Request:  category.UpdateCategoryPayload{},
+		Response: category.Category{},
+	},
+	{
+		Method:             http.MethodPatch,
+		Path:               "/api/v1/categories/:id",
+		Summary:            "Patch a category (null clears the description)",
+		Tag:                "Categories",
+		Request:            category.PatchCategoryPayload{},
+		RequestContentType: mergepatch.MIMEApplicationMergePatchJSON,
+		Response:           category.Category{},
+	},
+	{
+		Method:  http.MethodDelete,
+		Path:    "/api/v1/categories/:id",
+		Summary: "Delete a category",
+		Tag:     "Categories",
+		Request: category.DeleteCategoryPayload{},
+		Status:  http.StatusNoContent,
+	},
+	{
+		Method:   http.MethodPost,
+		Path:     "/api/v1/categories/:id/restore",
+		Summary:  "Restore a trashed category",
+		Tag:      "Trash",
+		Request:  category.RestoreCategoryPayload{},
+		Response: category.Category{},
+	},
+	{
+		Method:   http.MethodGet,
+		Path:     "/api/v1/categories/trash",
+		Summary:  "List trashed categories",
+		Tag:      "Trash",
+		Request:  category.GetTrashedCategoriesQuery{},
+		Response: model.PaginatedResponse[category.Category]{},
+	},
+
+	// ------------------------------------------------------------
+	// Tags
+	// ------------------------------------------------------------
+	{
+		Method:   http.MethodPost,
+		Path:     "/api/v1/tags",
+		Summary:  "Create a tag",
+		Tag:      "Tags",
+		Request:  tag.CreateTagPayload{},
+		Response: tag.Tag{},
+		Status:   http.StatusCreated,
+	},
+	{
+		Method:   http.MethodGet,
+		Path:     "/api/v1/tags",
+		Summary:  "List tags with how many todos each is on",
+		Tag:      "Tags",
+		Request:  tag.GetTagsQuery{},
+		Response: []tag.TagUsage{},
+	},
+	{
+		Method:   http.MethodGet,
+		Path:     "/api/v1/tags/:id",
+		Summary:  "Get a tag",
+		Tag:      "Tags",
+		Request:  tag.GetTagByIDPayload{},
+		Response: tag.TagUsage{},
+	},
+	{
+		Method:   http.MethodPut,
+		Path:     "/api/v1/tags/:id",
+		Summary:  "Rename or recolor a tag on every todo",
+		Tag:      "Tags",
+		Request:  tag.UpdateTagPayload{},
+		Response: tag.Tag{},
+	},
+	{
+		Method:  http.MethodDelete,
+		Path:    "/api/v1/tags/:id",
+		Summary: "Delete a tag and remove it from every todo",
+		Tag:     "Tags",
+		Request: tag.DeleteTagPayload{},
+		Status:  http.StatusNoContent,
+	},
+
+	// ------------------------------------------------------------
+	// Views
+	// ------------------------------------------------------------
+	{
+		Method:   http.MethodPost,
+		Path:     "/api/v1/views",
+		Summary:  "Save a todo list query as a view",
+		Tag:      "Views",
+		Request:  view.CreateViewPayload{},
+		Response: view.View{},
+		Status:   http.StatusCreated,
+	},
+	{
+		Method:   http.MethodGet,
+		Path:     "/api/v1/views",
+		Summary:  "List own and shared views, pinned ones first",
+		Tag:      "Views",
+		Request:  view.GetViewsPayload{},
+		Response: []view.View{},
+	},
+	{
+		Method:   http.MethodGet,
+		Path:     "/api/v1/views/system",
+		Summary:  "List the built-in views",
+		Tag:      "Views",
+		Request:  view.GetSystemViewsPayload{},
+		Response: []view.SystemView{},
+	},
+	{
+		Method:   http.MethodGet,
+		Path:     "/api/v1/views/system/:key/todos",
+		Summary:  "List the todos of a built-in view",
+		Tag:      "Views",
+		Request:  view.GetSystemViewTodosQuery{},
+		Response: model.CursorPaginatedResponse[todo.PopulatedTodo]{},
+	},
+	{
+		Method:   http.MethodGet,
+		Path:     "/api/v1/views/:id",
+		Summary:  "Get a view",
+		Tag:      "Views",
+		Request:  view.GetViewByIDPayload{},
+		Response: view.View{},
+	},
+	{
+		Method:   http.MethodPut,
+		Path:     "/api/v1/views/:id",
+		Summary:  "Update a view",
+		Tag:      "Views",
+		Request:  view.UpdateViewPayload{},
+		Response: view.View{},
+	},
+	{
+		Method:  http.MethodDelete,
+		Path:    "/api/v1/views/:id",
+		Summary: "Delete a view",
+		Tag:     "Views",
+		Request: view.DeleteViewPayload{},
+		Status:  http.StatusNoContent,
+	},
+	{
+		Method:   http.MethodGet,
+		Path:     "/api/v1/views/:id/todos",
+		Summary:  "List the todos of a view",
+		Tag:      "Views",
+		Request:  view.GetViewTodosQuery{},
+		Response: model.CursorPaginatedResponse[todo.PopulatedTodo]{},
+	},
+	{
+		Method:   http.MethodPost,
+		Path:     "/api/v1/views/:id/pin",
+		Summary:  "Pin a view",
+		Tag:      "Views",
+		Request:  view.PinViewPayload{},
+		Response: view.View{},
+	},
+	{
+		Method:  http.MethodDelete,
+		Path:    "/api/v1/views/:id/pin",
+		Summary: "Unpin a view",
+		Tag:     "Views",
+		Request: view.UnpinViewPayload{},
+		Status:  http.StatusNoContent,
+	},
+
+	// ------------------------------------------------------------
+	// Search
+	// ------------------------------------------------------------
+	{
+		Method:   http.MethodGet,
+		Path:     "/api/v1/search",
+		Summary:  "Search todos and comments",
+		Tag:      "Search",
+		Request:  search.SearchQuery{},
+		Response: model.PaginatedResponse[search.Hit]{},
+	},
+
+	// ------------------------------------------------------------
+	// Sharing
+	// ------------------------------------------------------------
+	{
+		Method:   http.MethodPost,
+		Path:     "/api/v1/todos/:id/shares",
+		Summary:  "Share a todo with another user",
+		Tag:      "Sharing",
+		Request:  share.CreateTodoSharePayload{},
+		Response: share.Share{},
+		Status:   http.StatusCreated,
+	},
+	{
+		Method:   http.MethodGet,
+		Path:     "/api/v1/todos/:id/shares",
+		Summary:  "List who a todo is shared with",
+		Tag:      "Sharing",
+		Request:  share.GetTodoSharesPayload{},
+		Response: []share.Share{},
+	},
+	{
+		Method:   http.MethodPost,
+		Path:     "/api/v1/categories/:id/shares",
+		Summary:  "Share every todo in a category with another user",
+		Tag:      "Sharing",
+		Request:  share.CreateCategorySharePayload{},
+		Response: share.Share{},
+		Status:   http.StatusCreated,
+	},
+	{
+		Method:   http.MethodGet,
+		Path:     "/api/v1/categories/:id/shares",
+		Summary:  "List who a category is shared with",
+		Tag:      "Sharing",
+		Request:  share.GetCategorySharesPayload{},
+		Response: []share.Share{},
+	},
+	{
+		Method:   http.MethodGet,
+		Path:     "/api/v1/shares/with-me",
+		Summary:  "List todos and categories shared with me",
+		Tag:      "Sharing",
+		Request:  share.GetSharedWithMeQuery{},
+		Response: model.PaginatedResponse[share.SharedItem]{},
+	},
+	{
+		Method:   http.MethodPut,
+		Path:     "/api/v1/shares/:id",
+		Summary:  "Change the role granted by a share",
+		Tag:      "Sharing",
+		Request:  share.UpdateSharePayload{},
+		Response: share.Share{},
+	},
+	{
+		Method:  http.MethodDelete,
+		Path:    "/api/v1/shares/:id",
+		Summary: "Revoke a share, or leave one granted to me",
+		Tag:     "Sharing",
+		Request: share.DeleteSharePayload{},
+		Status:  http.StatusNoContent,
+	},
+
+	// ------------------------------------------------------------
+	// Webhooks
+	// ------------------------------------------------------------
+	{
+		Method:   http.MethodPost,
+		Path:     "/api/v1/webhooks",
+		Summary:  "Register a webhook endpoint",
+		Tag:      "Webhooks",
+		Request:  webhook.CreateWebhookPayload{},
+		Response: webhook.CreatedWebhook{},
+		Status:   http.StatusCreated,
+	},
+	{
+		Method:   http.MethodGet,
+		Path:     "/api/v1/webhooks",
+		Summary:  "List webhooks",
+		Tag:      "Webhooks",
+		Request:  webhook.GetWebhooksPayload{},
+		Response: []webhook.Webhook{},
+	},
+	{
+		Method:   http.MethodGet,
+		Path:     "/api/v1/webhooks/:id",
+		Summary:  "Get a webhook by ID",
+		Tag:      "Webhooks",
+		Request:  webhook.GetWebhookByIDPayload{},
+		Response: webhook.Webhook{},
+	},
+	{
+		Method:   http.MethodPut,
+		Path:     "/api/v1/webhooks/:id",
+		Summary:  "Update a webhook's URL, events or active flag",
+		Tag:      "Webhooks",
+		Request:  webhook.UpdateWebhookPayload{},
+		Response: webhook.Webhook{},
+	},
+	{
+		Method:  http.MethodDelete,
+		Path:    "/api/v1/webhooks/:id",
+		Summary: "Delete a webhook and its delivery log",
+		Tag:     "Webhooks",
+		Request: webhook.DeleteWebhookPayload{},
+		Status:  http.StatusNoContent,
+	},
+	{
+		Method:   http.MethodGet,
+		Path:     "/api/v1/webhooks/:id/deliveries",
+		Summary:  "List a webhook's deliveries, newest first",
+		Tag:      "Webhooks",
+		Request:  webhook.GetDeliveriesQuery{},
+		Response: model.PaginatedResponse[webhook.Delivery]{},
+	},
+	{
+		Method:   http.MethodPost,
+		Path:     "/api/v1/webhooks/:id/deliveries/:deliveryId/redeliver",
+		Summary:  "Send a past delivery again",
+		Tag:      "Webhooks",
+		Request:  webhook.RedeliverPayload{},
+		Response: webhook.Delivery{},
+		Status:   http.StatusAccepted,
+	},
+
+	// ------------------------------------------------------------
+	// Events
+	// ------------------------------------------------------------
+	{
+		Method:      http.MethodGet,
+		Path:        "/api/v1/events",
+		Summary:     "Stream live todo, comment and attachment changes as Server-Sent Events",
+		Tag:         "Events",
+		Request:     event.StreamEventsQuery{},
+		ContentType: "text/event-stream",
+	},
+
+	// ------------------------------------------------------------
+	// Calendar
+	// ------------------------------------------------------------
+	{
+		Method:   http.MethodPost,
+		Path:     "/api/v1/calendar/feeds",
+		Summary:  "Create a calendar feed of due todos",
+		Tag:      "Calendar",
+		Request:  calendar.CreateFeedPayload{},
+		Response: calendar.CreatedFeed{},
+		Status:   http.StatusCreated,
+	},
+	{
+		Method:   http.MethodGet,
+		Path:     "/api/v1/calendar/feeds",
+		Summary:  "List calendar feeds",
+		Tag:      "Calendar",
+		Request:  calendar.GetFeedsPayload{},
+		Response: []calendar.Feed{},
+	},
+	{
+		Method:   http.MethodPut,
+		Path:     "/api/v1/calendar/feeds/:id",
+		Summary:  "Update a calendar feed",
+		Tag:      "Calendar",
+		Request:  calendar.UpdateFeedPayload{},
+		Response: calendar.Feed{},
+	},
+	{
+		Method:  http.MethodDelete,
+		Path:    "/api/v1/calendar/feeds/:id",
+		Summary: "Delete a calendar feed, revoking its token",
+		Tag:     "Calendar",
+		Request: calendar.DeleteFeedPayload{},
+		Status:  http.StatusNoContent,
+	},
+	{
+		Method:      http.MethodGet,
+		Path:        "/api/v1/calendar/:token",
+		Summary:     "Get a calendar feed as iCalendar, where token is the feed token followed by .ics",
+		Tag:         "Calendar",
+		Request:     calendar.GetFeedQuery{},
+		Public:      true,
+		ContentType: ical.MIMETextCalendar,
+	},
+
+	// ------------------------------------------------------------
+	// Import
+	// ------------------------------------------------------------
+	{
+		Method:     http.MethodPost,
+		Path:       "/api/v1/import",
+		Summary:    "Import todos from a Todoist, Trello or CSV export in the background",
+		Tag:        "Import",
+		Request:    imports.CreateImportPayload{},
+		Response:   imports.Import{},
+		Status:     http.StatusAccepted,
+		Multipart:  true,
+		Idempotent: true,
+	},
+	{
+		Method:   http.MethodGet,
+		Path:     "/api/v1/import/:id",
+		Summary:  "Get the progress and row errors of an import",
+		Tag:      "Import",
+		Request:  imports.GetImportByIDPayload{},
+		Response: imports.Import{},
+	},
+}
@@ -0,0 +1,325 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	uuidType      = reflect.TypeOf(uuid.UUID{})
+	rawJSONType   = reflect.TypeOf(json.RawMessage{})
+	interfaceType = reflect.TypeOf((*any)(nil)).Elem()
+)
+
+// schemaRegistry turns Go types into schemas, registering named structs
+// under components.schemas so they can be referenced instead of inlined.
+type schemaRegistry struct {
+	schemas map[string]*Schema
+}
+
+func newSchemaRegistry() *schemaRegistry {
+	return &schemaRegistry{schemas: map[string]*Schema{}}
+}
+
+// schemaFor returns the schema for a response or nested type.
+func (r *schemaRegistry) schemaFor(t reflect.Type) *Schema {
+	nullable := false
+	for t.Kind() == reflect.Ptr {
+		nullable = true
+		t = t.Elem()
+	}
+
+	s := r.baseSchema(t)
+	if nullable {
+		return withNull(s)
+	}
+	return s
+}
+
+func (r *schemaRegistry) baseSchema(t reflect.Type) *Schema {
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case uuidType:
+		return &Schema{Type: "string", Format: "uuid"}
+	case rawJSONType, interfaceType:
+		return &Schema{}
+	}
+
+	if values, ok := Enums[t]; ok {
+		return &Schema{Type: "string", Enum: values}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "binary"}
+		}
+		return &Schema{Type: "array", Items: r.schemaFor(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: r.schemaFor(t.Elem())}
+	case reflect.Interface:
+		return &Schema{}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return r.objectSchema(t, fieldsJSON)
+		}
+		name := componentName(t)
+		if _, ok := r.schemas[name]; !ok {
+			// Reserve the name first so recursive types terminate
+			r.schemas[name] = &Schema{}
+			*r.schemas[name] = *r.objectSchema(t, fieldsJSON)
+		}
+		return &Schema{Ref: "#/components/schemas/" + name}
+	}
+
+	return &Schema{}
+}
+
+type fieldSource int
+
+const (
+	fieldsJSON fieldSource = iota
+	fieldsQuery
+	fieldsParam
+	fieldsHeader
+	fieldsForm
+)
+
+// field is a single bindable struct field with its resolved wire name.
+type field struct {
+	name     string
+	typ      reflect.Type
+	validate []string
+	optional bool
+}
+
+// fieldsOf flattens embedded structs and returns the fields exposed through
+// the given struct tag, mirroring how Echo binds and encoding/json encodes.
+func fieldsOf(t reflect.Type, source fieldSource) []field {
+	var fields []field
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		if f.Anonymous && f.Type.Kind() == reflect.Struct && f.Tag.Get("json") == "" {
+			fields = append(fields, fieldsOf(f.Type, source)...)
+			continue
+		}
+
+		var name string
+		optional := f.Type.Kind() == reflect.Ptr
+		switch source {
+		case fieldsJSON:
+			tag := f.Tag.Get("json")
+			if tag == "-" || (tag == "" && (f.Tag.Get("param") != "" || f.Tag.Get("query") != "" || f.Tag.Get("header") != "" || f.Tag.Get("form") != "")) {
+				continue
+			}
+			parts := strings.Split(tag, ",")
+			name = parts[0]
+			if name == "" {
+				name = f.Name
+			}
+			for _, opt := range parts[1:] {
+				if opt == "omitempty" {
+					optional = true
+				}
+			}
+		case fieldsQuery:
+			name = f.Tag.Get("query")
+		case fieldsParam:
+			name = f.Tag.Get("param")
+		case fieldsHeader:
+			name = f.Tag.Get("header")
+		case fieldsForm:
+			name = f.Tag.Get("form")
+		}
+		if name == "" {
+			continue
+		}
+
+		var validate []string
+		if tag := f.Tag.Get("validate"); tag != "" {
+			validate = strings.Split(tag, ",")
+		}
+
+		fields = append(fields, field{name: name, typ: f.Type, validate: validate, optional: optional})
+	}
+	return fields
+}
+
+// objectSchema builds an object schema from the JSON fields of a struct.
+func (r *schemaRegistry) objectSchema(t reflect.Type, source fieldSource) *Schema {
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	for _, f := range fieldsOf(t, source) {
+		s.Properties[f.name] = r.fieldSchema(f)
+		if f.required() {
+			s.Required = append(s.Required, f.name)
+		}
+	}
+	return s
+}
+
+// requestSchema builds a request body schema where required-ness comes from
+// the validate tags rather than from the Go types.
+func (r *schemaRegistry) requestSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	for _, f := range fieldsOf(t, fieldsJSON) {
+		s.Properties[f.name] = r.fieldSchema(f)
+		if f.has("required") {
+			s.Required = append(s.Required, f.name)
+		}
+	}
+	if len(s.Properties) == 0 {
+		return nil
+	}
+	return s
+}
+
+func (f field) required() bool {
+	if f.has("required") {
+		return true
+	}
+	if f.has("omitempty") {
+		return false
+	}
+	return !f.optional
+}
+
+func (f field) has(rule string) bool {
+	for _, v := range f.validate {
+		if v == rule {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldSchema applies the validate rules of a field on top of its type schema.
+func (r *schemaRegistry) fieldSchema(f field) *Schema {
+	s := r.schemaFor(f.typ)
+
+	target := s
+	if len(s.OneOf) > 0 {
+		target = s.OneOf[0]
+	}
+	if target.Ref != "" {
+		return s
+	}
+
+	for _, rule := range f.validate {
+		if rule == "dive" {
+			break
+		}
+		key, value, _ := strings.Cut(rule, "=")
+		switch key {
+		case "oneof":
+			target.Enum = strings.Fields(value)
+		case "min", "max":
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				continue
+			}
+			applyBound(target, key == "min", n)
+		case "uuid":
+			target.Format = "uuid"
+		case "email":
+			target.Format = "email"
+		case "url":
+			target.Format = "uri"
+		case "hexcolor":
+			target.Pattern = "^#(?:[0-9a-fA-F]{3}|[0-9a-fA-F]{6})$"
+		}
+	}
+
+	return s
+}
+
+// paramSchema is fieldSchema for path and query parameters, which are
+// absent rather than null when not provided.
+func (r *schemaRegistry) paramSchema(f field) *Schema {
+	f.typ = indirect(f.typ)
+	return r.fieldSchema(f)
+}
+
+func applyBound(s *Schema, lower bool, n int) {
+	switch typeName(s) {
+	case "string":
+		if lower {
+			s.MinLength = &n
+		} else {
+			s.MaxLength = &n
+		}
+	case "array":
+		if lower {
+			s.MinItems = &n
+		} else {
+			s.MaxItems = &n
+		}
+	case "integer", "number":
+		v := float64(n)
+		if lower {
+			s.Minimum = &v
+		} else {
+			s.Maximum = &v
+		}
+	}
+}
+
+func typeName(s *Schema) string {
+	switch t := s.Type.(type) {
+	case string:
+		return t
+	case []string:
+		return t[0]
+	}
+	return ""
+}
+
+// withNull marks a schema as nullable using the OpenAPI 3.1 type arrays.
+func withNull(s *Schema) *Schema {
+	if s.Ref != "" {
+		return &Schema{OneOf: []*Schema{s, {Type: "null"}}}
+	}
+	if t, ok := s.Type.(string); ok {
+		s.Type = []string{t, "null"}
+	}
+	return s
+}
+
+// componentName derives a stable schema name from a Go type, flattening
+// generic instantiations such as PaginatedResponse[todo.PopulatedTodo].
+func componentName(t reflect.Type) string {
+	name := t.Name()
+	base, args, ok := strings.Cut(name, "[")
+	if !ok {
+		return name
+	}
+
+	var b strings.Builder
+	b.WriteString(base)
+	for _, arg := range strings.Split(strings.TrimSuffix(args, "]"), ",") {
+		if i := strings.LastIndex(arg, "."); i >= 0 {
+			arg = arg[i+1:]
+		}
+		b.WriteString(arg)
+	}
+	return b.String()
+}
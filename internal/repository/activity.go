@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/uttam282005/tasker/internal/lib/workspace"
+	"github.com/uttam282005/tasker/internal/model"
+	"github.com/uttam282005/tasker/internal/model/activity"
+	"github.com/uttam282005/tasker/internal/server"
+)
+
+type ActivityRepository struct {
+	server *server.Server
+}
+
+func NewActivityRepository(server *server.Server) *ActivityRepository {
+	return &ActivityRepository{server: server}
+}
+
+func (r *ActivityRepository) CreateActivity(ctx context.Context, entry *activity.Activity) (*activity.Activity, error) {
+	stmt := `
+		INSERT INTO
+			todo_activity (
+				todo_id,
+				user_id,
+				actor_id,
+				entity_type,
+				entity_id,
+				action,
+				changes
+			)
+		VALUES
+			(
+				@todo_id,
+				@user_id,
+				@actor_id,
+				@entity_type,
+				@entity_id,
+				@action,
+				@changes
+			)
+		RETURNING
+			*
+	`
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{
+		"todo_id":     entry.TodoID,
+		"user_id":     entry.UserID,
+		"actor_id":    entry.ActorID,
+		"entity_type": entry.EntityType,
+		"entity_id":   entry.EntityID,
+		"action":      entry.Action,
+		"changes":     entry.Changes,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute create activity query for entity_type=%s entity_id=%s: %w",
+			entry.EntityType, entry.EntityID.String(), err)
+	}
+
+	activityItem, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[activity.Activity])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect row from table:todo_activity: %w", err)
+	}
+
+	return &activityItem, nil
+}
+
+func (r *ActivityRepository) GetTodoActivity(ctx context.Context, userID string,
+	query *activity.GetTodoActivityQuery,
+) (*model.PaginatedResponse[activity.Activity], error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			todo_activity
+		WHERE
+			todo_id=@todo_id
+			AND todo_role(@todo_id, @user_id, @org_id) IS NOT NULL
+		ORDER BY
+			created_at DESC
+		LIMIT
+			@limit
+		OFFSET
+			@offset
+	`
+
+	args := pgx.NamedArgs{
+		"todo_id": query.TodoID,
+		"user_id": userID,
+		"org_id":  workspace.OrgID(ctx),
+		"limit":   *query.Limit,
+		"offset":  (*query.Page - 1) * (*query.Limit),
+	}
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get todo activity query for todo_id=%s: %w", query.TodoID.String(), err)
+	}
+
+	entries, err := pgx.CollectRows(rows, pgx.RowToStructByName[activity.Activity])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:todo_activity for todo_id=%s: %w", query.TodoID.String(), err)
+	}
+
+	countStmt := `
+		SELECT
+			COUNT(*)
+		FROM
+			todo_activity
+		WHERE
+			todo_id=@todo_id
+			AND todo_role(@todo_id, @user_id, @org_id) IS NOT NULL
+	`
+
+	var total int
+	err = r.server.DB.Pool.QueryRow(ctx, countStmt, args).Scan(&total)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get total count of activity for todo_id=%s: %w", query.TodoID.String(), err)
+	}
+
+	return &model.PaginatedResponse[activity.Activity]{
+		Data:       entries,
+		Page:       *query.Page,
+		Limit:      *query.Limit,
+		Total:      total,
+		TotalPages: (total + *query.Limit - 1) / *query.Limit,
+	}, nil
+}
@@ -0,0 +1,237 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/uttam282005/tasker/internal/errs"
+	"github.com/uttam282005/tasker/internal/lib/workspace"
+	"github.com/uttam282005/tasker/internal/model/calendar"
+	"github.com/uttam282005/tasker/internal/server"
+)
+
+type CalendarRepository struct {
+	server *server.Server
+}
+
+func NewCalendarRepository(server *server.Server) *CalendarRepository {
+	return &CalendarRepository{server: server}
+}
+
+func (r *CalendarRepository) CreateFeed(ctx context.Context, userID string,
+	payload *calendar.CreateFeedPayload, tokenHash string,
+) (*calendar.Feed, error) {
+	stmt := `
+		INSERT INTO
+			calendar_feeds (
+				user_id,
+				org_id,
+				name,
+				token_hash,
+				component,
+				category_ids
+			)
+		VALUES
+			(
+				@user_id,
+				@org_id,
+				@name,
+				@token_hash,
+				@component,
+				@category_ids
+			)
+		RETURNING
+			*
+	`
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{
+		"user_id":      userID,
+		"org_id":       workspace.OrgID(ctx),
+		"name":         payload.Name,
+		"token_hash":   tokenHash,
+		"component":    payload.Component,
+		"category_ids": payload.CategoryIDs,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute create calendar feed query for user_id=%s: %w", userID, err)
+	}
+
+	feed, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[calendar.Feed])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect row from table:calendar_feeds for user_id=%s: %w", userID, err)
+	}
+
+	return &feed, nil
+}
+
+func (r *CalendarRepository) GetFeeds(ctx context.Context, userID string) ([]calendar.Feed, error) {
+	args := pgx.NamedArgs{}
+	stmt := `
+		SELECT
+			*
+		FROM
+			calendar_feeds
+		WHERE
+			` + ownerScope(ctx, userID, args) + `
+		ORDER BY
+			created_at ASC
+	`
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get calendar feeds query for user_id=%s: %w", userID, err)
+	}
+
+	feeds, err := pgx.CollectRows(rows, pgx.RowToStructByName[calendar.Feed])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:calendar_feeds for user_id=%s: %w", userID, err)
+	}
+
+	return feeds, nil
+}
+
+func (r *CalendarRepository) UpdateFeed(ctx context.Context, userID string, payload *calendar.UpdateFeedPayload) (*calendar.Feed, error) {
+	args := pgx.NamedArgs{"id": payload.ID}
+	setClauses := []string{}
+
+	if payload.Name != nil {
+		setClauses = append(setClauses, "name = @name")
+		args["name"] = *payload.Name
+	}
+
+	if payload.Component != nil {
+		setClauses = append(setClauses, "component = @component")
+		args["component"] = *payload.Component
+	}
+
+	if payload.CategoryIDs != nil {
+		setClauses = append(setClauses, "category_ids = @category_ids")
+		args["category_ids"] = payload.CategoryIDs
+	}
+
+	if len(setClauses) == 0 {
+		return nil, errs.NewBadRequestError("no fields to update", false, nil, nil, nil)
+	}
+
+	stmt := "UPDATE calendar_feeds SET " + strings.Join(setClauses, ", ") +
+		" WHERE id = @id AND " + ownerScope(ctx, userID, args) + " RETURNING *"
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute update calendar feed query for feed_id=%s user_id=%s: %w", payload.ID.String(), userID, err)
+	}
+
+	feed, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[calendar.Feed])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect row from table:calendar_feeds for feed_id=%s user_id=%s: %w", payload.ID.String(), userID, err)
+	}
+
+	return &feed, nil
+}
+
+func (r *CalendarRepository) DeleteFeed(ctx context.Context, userID string, feedID uuid.UUID) error {
+	args := pgx.NamedArgs{"id": feedID}
+	stmt := `
+		DELETE FROM calendar_feeds
+		WHERE
+			id=@id
+			AND ` + ownerScope(ctx, userID, args) + `
+	`
+
+	result, err := r.server.DB.Pool.Exec(ctx, stmt, args)
+	if err != nil {
+		return fmt.Errorf("failed to execute delete calendar feed query for feed_id=%s user_id=%s: %w", feedID.String(), userID, err)
+	}
+
+	if result.RowsAffected() == 0 {
+		code := "CALENDAR_FEED_NOT_FOUND"
+		return errs.NewNotFoundError("calendar feed not found", false, &code)
+	}
+
+	return nil
+}
+
+// GetFeedByTokenHash returns the feed a token belongs to and records that
+// it was fetched.
+func (r *CalendarRepository) GetFeedByTokenHash(ctx context.Context, tokenHash string) (*calendar.Feed, error) {
+	stmt := `
+		UPDATE calendar_feeds
+		SET
+			last_fetched_at=CURRENT_TIMESTAMP
+		WHERE
+			token_hash=@token_hash
+		RETURNING
+			*
+	`
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{"token_hash": tokenHash})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get calendar feed by token query: %w", err)
+	}
+
+	feed, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[calendar.Feed])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			code := "CALENDAR_FEED_NOT_FOUND"
+			return nil, errs.NewNotFoundError("calendar feed not found", false, &code)
+		}
+		return nil, fmt.Errorf("failed to collect row from table:calendar_feeds by token: %w", err)
+	}
+
+	return &feed, nil
+}
+
+// GetFeedTodos calls fn with every todo with a due date the feed's owner can
+// access in the feed's workspace, in the feed's categories, reading rows as
+// they arrive.
+func (r *CalendarRepository) GetFeedTodos(ctx context.Context, feed *calendar.Feed, fn func(*calendar.Todo) error) error {
+	stmt := `
+		SELECT
+			t.*,
+			c.name AS category_name
+		FROM
+			todos t
+			LEFT JOIN todo_categories c ON c.id=t.category_id AND c.user_id=t.user_id AND c.deleted_at IS NULL
+		WHERE
+			t.id IN (SELECT todo_id FROM accessible_todos(@user_id, @org_id))
+			AND t.deleted_at IS NULL
+			AND t.due_date IS NOT NULL
+			AND (
+				CARDINALITY(@category_ids::uuid[])=0
+				OR t.category_id=ANY(@category_ids::uuid[])
+			)
+		ORDER BY
+			t.due_date ASC,
+			t.id ASC
+	`
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{
+		"user_id":      feed.UserID,
+		"org_id":       feed.OrgID,
+		"category_ids": feed.CategoryIDs,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to execute get calendar feed todos query for feed_id=%s: %w", feed.ID.String(), err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		todoItem, err := pgx.RowToStructByName[calendar.Todo](rows)
+		if err != nil {
+			return fmt.Errorf("failed to scan row from table:todos for feed_id=%s: %w", feed.ID.String(), err)
+		}
+		if err := fn(&todoItem); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read rows from table:todos for feed_id=%s: %w", feed.ID.String(), err)
+	}
+
+	return nil
+}
@@ -5,9 +5,13 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/uttam282005/tasker/internal/errs"
+	"github.com/uttam282005/tasker/internal/lib/etag"
+	"github.com/uttam282005/tasker/internal/lib/workspace"
 	"github.com/uttam282005/tasker/internal/model"
 	"github.com/uttam282005/tasker/internal/model/category"
 	"github.com/uttam282005/tasker/internal/server"
@@ -28,6 +32,7 @@ func (r *CategoryRepository) CreateCategory(ctx context.Context, userID string,
 		INSERT INTO
 			todo_categories (
 				user_id,
+				org_id,
 				name,
 				color,
 				description
@@ -35,6 +40,7 @@ func (r *CategoryRepository) CreateCategory(ctx context.Context, userID string,
 		VALUES
 			(
 				@user_id,
+				@org_id,
 				@name,
 				@color,
 				@description
@@ -45,6 +51,7 @@ func (r *CategoryRepository) CreateCategory(ctx context.Context, userID string,
 
 	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{
 		"user_id":     userID,
+		"org_id":      workspace.OrgID(ctx),
 		"name":        payload.Name,
 		"color":       payload.Color,
 		"description": payload.Description,
@@ -61,6 +68,60 @@ func (r *CategoryRepository) CreateCategory(ctx context.Context, userID string,
 	return &categoryItem, nil
 }
 
+// GetOrCreateCategoryByName returns the workspace's category named name,
+// creating it with the default color if there is none.
+func (r *CategoryRepository) GetOrCreateCategoryByName(ctx context.Context, userID string, name string) (*category.Category, error) {
+	args := pgx.NamedArgs{
+		"name":    name,
+		"user_id": userID,
+		"org_id":  workspace.OrgID(ctx),
+	}
+	scope := workspaceScope(ctx, "", userID, args)
+
+	// The insert does nothing when the name is taken, and the select can't
+	// see the row it inserts, so exactly one of them returns the category
+	stmt := `
+		WITH
+			inserted AS (
+				INSERT INTO
+					todo_categories (user_id, org_id, name)
+				VALUES
+					(@user_id, @org_id, @name)
+				ON CONFLICT DO NOTHING
+				RETURNING
+					*
+			)
+		SELECT
+			*
+		FROM
+			inserted
+		UNION ALL
+		SELECT
+			*
+		FROM
+			todo_categories
+		WHERE
+			name=@name
+			AND ` + scope + `
+			AND deleted_at IS NULL
+		LIMIT
+			1
+	`
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get or create category query for user_id=%s name=%s: %w", userID, name, err)
+	}
+
+	categoryItem, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[category.Category])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect row from table:todo_categories for user_id=%s name=%s: %w", userID, name, err)
+	}
+
+	return &categoryItem, nil
+}
+
+// GetCategoryByID returns a category the user owns or that was shared with them.
 func (r *CategoryRepository) GetCategoryByID(ctx context.Context, userID string, categoryID uuid.UUID) (*category.Category, error) {
 	stmt := `
 		SELECT
@@ -69,12 +130,14 @@ func (r *CategoryRepository) GetCategoryByID(ctx context.Context, userID string,
 			todo_categories
 		WHERE
 			id=@id
-			AND user_id=@user_id
+			AND category_role(id, @user_id, @org_id) IS NOT NULL
+			AND deleted_at IS NULL
 	`
 
 	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{
 		"id":      categoryID,
 		"user_id": userID,
+		"org_id":  workspace.OrgID(ctx),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute get category by id query for category_id=%s user_id=%s: %w", categoryID.String(), userID, err)
@@ -88,43 +151,113 @@ func (r *CategoryRepository) GetCategoryByID(ctx context.Context, userID string,
 	return &categoryItem, nil
 }
 
-func (r *CategoryRepository) GetCategories(ctx context.Context, userID string,
-	query *category.GetCategoriesQuery,
-) (*model.PaginatedResponse[category.Category], error) {
+// CanAccessCategory reports whether userID can see the category in the
+// current workspace.
+func (r *CategoryRepository) CanAccessCategory(ctx context.Context, userID string, categoryID uuid.UUID) (bool, error) {
 	stmt := `
 		SELECT
-			*
-		FROM
-			todo_categories
-		WHERE
-			user_id=@user_id
+			category_role(@id, @user_id, @org_id) IS NOT NULL
 	`
 
-	args := pgx.NamedArgs{
+	var ok bool
+	err := r.server.DB.Pool.QueryRow(ctx, stmt, pgx.NamedArgs{
+		"id":      categoryID,
 		"user_id": userID,
+		"org_id":  workspace.OrgID(ctx),
+	}).Scan(&ok)
+	if err != nil {
+		return false, fmt.Errorf("failed to check category access for category_id=%s user_id=%s: %w", categoryID.String(), userID, err)
+	}
+
+	return ok, nil
+}
+
+// visibleCategories returns the condition matching the categories listed in
+// the request's workspace: the organization's categories, or the user's own
+// personal categories plus the ones shared with them.
+func visibleCategories(ctx context.Context, userID string, args pgx.NamedArgs) string {
+	if orgID := workspace.OrgID(ctx); orgID != nil {
+		args["org_id"] = *orgID
+		return "org_id = @org_id"
+	}
+
+	args["user_id"] = userID
+	return `org_id IS NULL
+			AND (
+				user_id=@user_id
+				OR id IN (
+					SELECT
+						category_id
+					FROM
+						todo_shares
+					WHERE
+						grantee_id=@user_id
+				)
+			)`
+}
+
+func (r *CategoryRepository) GetCategories(ctx context.Context, userID string,
+	query *category.GetCategoriesQuery,
+) (*model.CursorPaginatedResponse[category.Category], error) {
+	args := pgx.NamedArgs{}
+
+	conditions := []string{
+		visibleCategories(ctx, userID, args),
+		"deleted_at IS NULL",
 	}
 
 	// Add search filter if provided
 	if query.Search != nil {
-		stmt += ` AND name ILIKE '%' || @search || '%'`
+		conditions = append(conditions, `name ILIKE '%' || @search || '%'`)
 		args["search"] = *query.Search
 	}
 
-	// Add sorting
-	sortColumn := "name"
-	if query.Sort != nil {
-		sortColumn = *query.Sort
+	pageKeys, err := newKeyset(query.Cursor, *query.Sort, *query.Order, categorySortKeys[*query.Sort], "id")
+	if err != nil {
+		return nil, err
 	}
-	sortOrder := "asc"
-	if query.Order != nil {
-		sortOrder = *query.Order
+
+	// Get total count
+	var total *int
+	if *query.Count {
+		countStmt := `
+			SELECT
+				COUNT(*)
+			FROM
+				todo_categories
+			WHERE
+				` + strings.Join(conditions, " AND ")
+
+		var count int
+		if err := r.server.DB.Pool.QueryRow(ctx, countStmt, args).Scan(&count); err != nil {
+			return nil, fmt.Errorf("failed to get total count of categories for user_id=%s: %w", userID, err)
+		}
+		total = &count
 	}
-	stmt += fmt.Sprintf(" ORDER BY %s %s", sortColumn, sortOrder)
 
-	// Add pagination
-	stmt += ` LIMIT @limit OFFSET @offset`
+	if condition := pageKeys.condition(args); condition != "" {
+		conditions = append(conditions, condition)
+	}
+
+	stmt := `
+		SELECT
+			*
+		FROM
+			todo_categories
+		WHERE
+			` + strings.Join(conditions, " AND ")
+
+	// Add sorting and pagination
+	stmt += pageKeys.orderBy()
+
 	args["limit"] = *query.Limit
-	args["offset"] = (*query.Page - 1) * (*query.Limit)
+	if *query.Pagination == "cursor" {
+		// One extra row tells whether there's a next page
+		stmt += ` LIMIT @limit + 1`
+	} else {
+		stmt += ` LIMIT @limit OFFSET @offset`
+		args["offset"] = (*query.Page - 1) * (*query.Limit)
+	}
 
 	rows, err := r.server.DB.Pool.Query(ctx, stmt, args)
 	if err != nil {
@@ -133,59 +266,59 @@ func (r *CategoryRepository) GetCategories(ctx context.Context, userID string,
 
 	categories, err := pgx.CollectRows(rows, pgx.RowToStructByName[category.Category])
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return &model.PaginatedResponse[category.Category]{
-				Data:       []category.Category{},
-				Page:       *query.Page,
-				Limit:      *query.Limit,
-				Total:      0,
-				TotalPages: 0,
-			}, nil
-		}
 		return nil, fmt.Errorf("failed to collect rows from table:todo_categories for user_id=%s: %w", userID, err)
 	}
 
-	// Get total count
-	countStmt := `
-		SELECT
-			COUNT(*)
-		FROM
-			todo_categories
-		WHERE
-			user_id=@user_id
-	`
-
-	countArgs := pgx.NamedArgs{
-		"user_id": userID,
+	if *query.Pagination == "cursor" {
+		page := keysetPage(pageKeys, categories, *query.Limit, func(c *category.Category) (string, uuid.UUID) {
+			return categorySortValue(c, *query.Sort), c.ID
+		})
+		page.Total = total
+		return page, nil
 	}
 
-	if query.Search != nil {
-		countStmt += ` AND name ILIKE '%' || @search || '%'`
-		countArgs["search"] = *query.Search
+	page := &model.CursorPaginatedResponse[category.Category]{
+		Data:  categories,
+		Limit: *query.Limit,
+		Page:  query.Page,
+		Total: total,
 	}
-
-	var total int
-	err = r.server.DB.Pool.QueryRow(ctx, countStmt, countArgs).Scan(&total)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get total count of categories for user_id=%s: %w", userID, err)
+	if total != nil {
+		totalPages := (*total + *query.Limit - 1) / *query.Limit
+		page.TotalPages = &totalPages
 	}
 
-	return &model.PaginatedResponse[category.Category]{
-		Data:       categories,
-		Page:       *query.Page,
-		Limit:      *query.Limit,
-		Total:      total,
-		TotalPages: (total + *query.Limit - 1) / *query.Limit,
-	}, nil
+	return page, nil
 }
 
+// categorySortKeys are the columns GetCategories sorts by.
+var categorySortKeys = map[string]sortKey{
+	"created_at": {expr: "created_at", sqlType: "timestamptz"},
+	"updated_at": {expr: "updated_at", sqlType: "timestamptz"},
+	"name":       {expr: "name", sqlType: "text"},
+}
+
+// categorySortValue returns a category's value for a categorySortKeys
+// column, formatted for a cursor.
+func categorySortValue(c *category.Category, sort string) string {
+	switch sort {
+	case "created_at":
+		return c.CreatedAt.Format(time.RFC3339Nano)
+	case "updated_at":
+		return c.UpdatedAt.Format(time.RFC3339Nano)
+	default:
+		return c.Name
+	}
+}
+
+// UpdateCategory changes a category. A non-nil version must still be the
+// category's, or the update fails with etag.PreconditionFailedError.
 func (r *CategoryRepository) UpdateCategory(ctx context.Context, userID string,
-	categoryID uuid.UUID, payload *category.UpdateCategoryPayload,
+	categoryID uuid.UUID, payload *category.UpdateCategoryPayload, version *int,
 ) (*category.Category, error) {
 	stmt := `UPDATE todo_categories SET `
 	args := pgx.NamedArgs{
-		"id":      categoryID,
-		"user_id": userID,
+		"id": categoryID,
 	}
 	setClauses := []string{}
 
@@ -200,14 +333,17 @@ func (r *CategoryRepository) UpdateCategory(ctx context.Context, userID string,
 	if payload.Description != nil {
 		setClauses = append(setClauses, "description = @description")
 		args["description"] = *payload.Description
+	} else if payload.ClearDescription {
+		setClauses = append(setClauses, "description = NULL")
 	}
 
 	if len(setClauses) == 0 {
-		return nil, fmt.Errorf("no fields to update")
+		return nil, errs.NewBadRequestError("no fields to update", false, nil, nil, nil)
 	}
 
 	stmt += strings.Join(setClauses, ", ")
-	stmt += ` WHERE id = @id AND user_id = @user_id RETURNING *`
+	stmt += ` WHERE id = @id AND ` + workspaceScope(ctx, "", userID, args) + ` AND deleted_at IS NULL` +
+		versionCondition(version, args) + ` RETURNING *`
 
 	rows, err := r.server.DB.Pool.Query(ctx, stmt, args)
 	if err != nil {
@@ -215,6 +351,9 @@ func (r *CategoryRepository) UpdateCategory(ctx context.Context, userID string,
 	}
 
 	categoryItem, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[category.Category])
+	if errors.Is(err, pgx.ErrNoRows) && version != nil {
+		return nil, r.versionConflict(ctx, userID, categoryID)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to collect row from table:todo_categories for category_id=%s user_id=%s: %w", categoryID.String(), userID, err)
 	}
@@ -222,21 +361,178 @@ func (r *CategoryRepository) UpdateCategory(ctx context.Context, userID string,
 	return &categoryItem, nil
 }
 
-func (r *CategoryRepository) DeleteCategory(ctx context.Context, userID string, categoryID uuid.UUID) error {
+// DeleteCategory trashes a category. A non-nil version must still be the
+// category's, or the delete fails with etag.PreconditionFailedError.
+func (r *CategoryRepository) DeleteCategory(ctx context.Context, userID string, categoryID uuid.UUID, version *int) error {
+	args := pgx.NamedArgs{
+		"id": categoryID,
+	}
+
 	result, err := r.server.DB.Pool.Exec(ctx, `
-		DELETE FROM todo_categories
-		WHERE id = @id AND user_id = @user_id
-	`, pgx.NamedArgs{
-		"id":      categoryID,
-		"user_id": userID,
-	})
+		UPDATE todo_categories
+		SET deleted_at = NOW()
+		WHERE id = @id AND `+workspaceScope(ctx, "", userID, args)+` AND deleted_at IS NULL`+
+		versionCondition(version, args), args)
 	if err != nil {
 		return fmt.Errorf("failed to delete category: %w", err)
 	}
 
+	if result.RowsAffected() == 0 && version != nil {
+		return r.versionConflict(ctx, userID, categoryID)
+	}
+
 	if result.RowsAffected() == 0 {
-		return fmt.Errorf("category not found")
+		code := "CATEGORY_NOT_FOUND"
+		return errs.NewNotFoundError("category not found", false, &code)
 	}
 
 	return nil
 }
+
+// versionConflict explains a versioned write that matched no row: the
+// category is either gone or at another version.
+func (r *CategoryRepository) versionConflict(ctx context.Context, userID string, categoryID uuid.UUID) error {
+	current, err := r.GetCategoryByID(ctx, userID, categoryID)
+	if err != nil {
+		return err
+	}
+	return &etag.PreconditionFailedError{Current: current}
+}
+
+func (r *CategoryRepository) GetTrashedCategories(ctx context.Context, userID string,
+	query *category.GetTrashedCategoriesQuery,
+) (*model.PaginatedResponse[category.Category], error) {
+	args := pgx.NamedArgs{
+		"limit":  *query.Limit,
+		"offset": (*query.Page - 1) * (*query.Limit),
+	}
+	scope := workspaceScope(ctx, "", userID, args)
+
+	stmt := `
+		SELECT
+			*
+		FROM
+			todo_categories
+		WHERE
+			` + scope + `
+			AND deleted_at IS NOT NULL
+		ORDER BY
+			deleted_at DESC
+		LIMIT
+			@limit
+		OFFSET
+			@offset
+	`
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get trashed categories query for user_id=%s: %w", userID, err)
+	}
+
+	categories, err := pgx.CollectRows(rows, pgx.RowToStructByName[category.Category])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:todo_categories for user_id=%s: %w", userID, err)
+	}
+
+	countStmt := `
+		SELECT
+			COUNT(*)
+		FROM
+			todo_categories
+		WHERE
+			` + scope + `
+			AND deleted_at IS NOT NULL
+	`
+
+	var total int
+	err = r.server.DB.Pool.QueryRow(ctx, countStmt, args).Scan(&total)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get total count of trashed categories for user_id=%s: %w", userID, err)
+	}
+
+	return &model.PaginatedResponse[category.Category]{
+		Data:       categories,
+		Page:       *query.Page,
+		Limit:      *query.Limit,
+		Total:      total,
+		TotalPages: (total + *query.Limit - 1) / *query.Limit,
+	}, nil
+}
+
+func (r *CategoryRepository) GetTrashedCategory(ctx context.Context, userID string, categoryID uuid.UUID) (*category.Category, error) {
+	args := pgx.NamedArgs{
+		"id": categoryID,
+	}
+	scope := workspaceScope(ctx, "", userID, args)
+
+	stmt := `
+		SELECT
+			*
+		FROM
+			todo_categories
+		WHERE
+			id=@id
+			AND ` + scope + `
+			AND deleted_at IS NOT NULL
+	`
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get trashed category query for category_id=%s user_id=%s: %w", categoryID.String(), userID, err)
+	}
+
+	categoryItem, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[category.Category])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect row from table:todo_categories for category_id=%s user_id=%s: %w", categoryID.String(), userID, err)
+	}
+
+	return &categoryItem, nil
+}
+
+func (r *CategoryRepository) RestoreCategory(ctx context.Context, userID string, categoryID uuid.UUID) (*category.Category, error) {
+	args := pgx.NamedArgs{
+		"id": categoryID,
+	}
+	scope := workspaceScope(ctx, "", userID, args)
+
+	stmt := `
+		UPDATE
+			todo_categories
+		SET
+			deleted_at=NULL
+		WHERE
+			id=@id
+			AND ` + scope + `
+			AND deleted_at IS NOT NULL
+		RETURNING
+		*
+	`
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute restore category query for category_id=%s user_id=%s: %w", categoryID.String(), userID, err)
+	}
+
+	categoryItem, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[category.Category])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect row from table:todo_categories for category_id=%s user_id=%s: %w", categoryID.String(), userID, err)
+	}
+
+	return &categoryItem, nil
+}
+
+// PurgeCategoriesDeletedBefore permanently removes categories trashed before
+// cutoff. Todos still pointing at them lose their category.
+func (r *CategoryRepository) PurgeCategoriesDeletedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	result, err := r.server.DB.Pool.Exec(ctx, `
+		DELETE FROM todo_categories
+		WHERE deleted_at < @cutoff
+	`, pgx.NamedArgs{
+		"cutoff": cutoff,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge trashed categories: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
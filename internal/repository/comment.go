@@ -2,10 +2,16 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/uttam282005/tasker/internal/errs"
+	"github.com/uttam282005/tasker/internal/lib/etag"
+	"github.com/uttam282005/tasker/internal/lib/workspace"
+	"github.com/uttam282005/tasker/internal/model"
 	"github.com/uttam282005/tasker/internal/model/comment"
 	"github.com/uttam282005/tasker/internal/server"
 )
@@ -63,7 +69,8 @@ func (r *CommentRepository) GetCommentsByTodoID(ctx context.Context, userID stri
 			todo_comments
 		WHERE
 			todo_id=@todo_id
-			AND user_id=@user_id
+			AND todo_role(todo_id, @user_id, @org_id) IS NOT NULL
+			AND deleted_at IS NULL
 		ORDER BY
 			created_at ASC
 	`
@@ -71,6 +78,7 @@ func (r *CommentRepository) GetCommentsByTodoID(ctx context.Context, userID stri
 	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{
 		"todo_id": todoID,
 		"user_id": userID,
+		"org_id":  workspace.OrgID(ctx),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute get comments by todo id query for todo_id=%s user_id=%s: %w", todoID.String(), userID, err)
@@ -84,6 +92,8 @@ func (r *CommentRepository) GetCommentsByTodoID(ctx context.Context, userID stri
 	return comments, nil
 }
 
+// GetCommentByID returns any comment on a todo the user can access, not only
+// the ones they wrote.
 func (r *CommentRepository) GetCommentByID(ctx context.Context, userID string, commentID uuid.UUID) (*comment.Comment, error) {
 	stmt := `
 		SELECT
@@ -92,12 +102,14 @@ func (r *CommentRepository) GetCommentByID(ctx context.Context, userID string, c
 			todo_comments
 		WHERE
 			id=@id
-			AND user_id=@user_id
+			AND todo_role(todo_id, @user_id, @org_id) IS NOT NULL
+			AND deleted_at IS NULL
 	`
 
 	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{
 		"id":      commentID,
 		"user_id": userID,
+		"org_id":  workspace.OrgID(ctx),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute get comment by id query for comment_id=%s user_id=%s: %w", commentID.String(), userID, err)
@@ -111,7 +123,18 @@ func (r *CommentRepository) GetCommentByID(ctx context.Context, userID string, c
 	return &commentItem, nil
 }
 
-func (r *CommentRepository) UpdateComment(ctx context.Context, userID string, commentID uuid.UUID, content string) (*comment.Comment, error) {
+// UpdateComment only lets authors edit their own comments. A non-nil version
+// must still be the comment's, or the update fails with
+// etag.PreconditionFailedError.
+func (r *CommentRepository) UpdateComment(ctx context.Context, userID string, commentID uuid.UUID, content string,
+	version *int,
+) (*comment.Comment, error) {
+	args := pgx.NamedArgs{
+		"id":      commentID,
+		"user_id": userID,
+		"content": content,
+	}
+
 	stmt := `
 		UPDATE
 			todo_comments
@@ -120,17 +143,151 @@ func (r *CommentRepository) UpdateComment(ctx context.Context, userID string, co
 		WHERE
 			id=@id
 			AND user_id=@user_id
+			AND deleted_at IS NULL` + versionCondition(version, args) + `
 		RETURNING
 		*
 	`
 
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute update comment query for comment_id=%s user_id=%s: %w", commentID.String(), userID, err)
+	}
+
+	commentItem, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[comment.Comment])
+	if errors.Is(err, pgx.ErrNoRows) && version != nil {
+		return nil, r.versionConflict(ctx, userID, commentID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect row from table:todo_comments for comment_id=%s user_id=%s: %w", commentID.String(), userID, err)
+	}
+
+	return &commentItem, nil
+}
+
+// DeleteComment trashes a comment written by the user or, when moderate is
+// set, any comment on a todo the user owns. Callers decide who may moderate.
+// A non-nil version must still be the comment's, or the delete fails with
+// etag.PreconditionFailedError.
+func (r *CommentRepository) DeleteComment(ctx context.Context, userID string, commentID uuid.UUID, moderate bool,
+	version *int,
+) error {
+	args := pgx.NamedArgs{
+		"id":       commentID,
+		"user_id":  userID,
+		"org_id":   workspace.OrgID(ctx),
+		"moderate": moderate,
+	}
+
+	result, err := r.server.DB.Pool.Exec(ctx, `
+		UPDATE todo_comments
+		SET deleted_at = NOW()
+		WHERE id = @id
+			AND (user_id = @user_id OR (@moderate AND todo_role(todo_id, @user_id, @org_id) = 'owner'))
+			AND deleted_at IS NULL`+versionCondition(version, args), args)
+	if err != nil {
+		return fmt.Errorf("failed to delete comment: %w", err)
+	}
+
+	if result.RowsAffected() == 0 && version != nil {
+		return r.versionConflict(ctx, userID, commentID)
+	}
+
+	if result.RowsAffected() == 0 {
+		code := "COMMENT_NOT_FOUND"
+		return errs.NewNotFoundError("comment not found", false, &code)
+	}
+
+	return nil
+}
+
+// versionConflict explains a versioned write that matched no row: the
+// comment is either gone or at another version.
+func (r *CommentRepository) versionConflict(ctx context.Context, userID string, commentID uuid.UUID) error {
+	current, err := r.GetCommentByID(ctx, userID, commentID)
+	if err != nil {
+		return err
+	}
+	return &etag.PreconditionFailedError{Current: current}
+}
+
+func (r *CommentRepository) GetTrashedComments(ctx context.Context, userID string,
+	query *comment.GetTrashedCommentsQuery,
+) (*model.PaginatedResponse[comment.Comment], error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			todo_comments
+		WHERE
+			user_id=@user_id
+			AND deleted_at IS NOT NULL
+		ORDER BY
+			deleted_at DESC
+		LIMIT
+			@limit
+		OFFSET
+			@offset
+	`
+
+	args := pgx.NamedArgs{
+		"user_id": userID,
+		"limit":   *query.Limit,
+		"offset":  (*query.Page - 1) * (*query.Limit),
+	}
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get trashed comments query for user_id=%s: %w", userID, err)
+	}
+
+	comments, err := pgx.CollectRows(rows, pgx.RowToStructByName[comment.Comment])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:todo_comments for user_id=%s: %w", userID, err)
+	}
+
+	countStmt := `
+		SELECT
+			COUNT(*)
+		FROM
+			todo_comments
+		WHERE
+			user_id=@user_id
+			AND deleted_at IS NOT NULL
+	`
+
+	var total int
+	err = r.server.DB.Pool.QueryRow(ctx, countStmt, args).Scan(&total)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get total count of trashed comments for user_id=%s: %w", userID, err)
+	}
+
+	return &model.PaginatedResponse[comment.Comment]{
+		Data:       comments,
+		Page:       *query.Page,
+		Limit:      *query.Limit,
+		Total:      total,
+		TotalPages: (total + *query.Limit - 1) / *query.Limit,
+	}, nil
+}
+
+func (r *CommentRepository) GetTrashedComment(ctx context.Context, userID string, commentID uuid.UUID) (*comment.Comment, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			todo_comments
+		WHERE
+			id=@id
+			AND user_id=@user_id
+			AND deleted_at IS NOT NULL
+	`
+
 	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{
 		"id":      commentID,
 		"user_id": userID,
-		"content": content,
 	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute update comment query for comment_id=%s user_id=%s: %w", commentID.String(), userID, err)
+		return nil, fmt.Errorf("failed to execute get trashed comment query for comment_id=%s user_id=%s: %w", commentID.String(), userID, err)
 	}
 
 	commentItem, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[comment.Comment])
@@ -141,21 +298,47 @@ func (r *CommentRepository) UpdateComment(ctx context.Context, userID string, co
 	return &commentItem, nil
 }
 
-func (r *CommentRepository) DeleteComment(ctx context.Context, userID string, commentID uuid.UUID) error {
-	result, err := r.server.DB.Pool.Exec(ctx, `
-		DELETE FROM todo_comments
-		WHERE id = @id AND user_id = @user_id
-	`, pgx.NamedArgs{
+func (r *CommentRepository) RestoreComment(ctx context.Context, userID string, commentID uuid.UUID) (*comment.Comment, error) {
+	stmt := `
+		UPDATE
+			todo_comments
+		SET
+			deleted_at=NULL
+		WHERE
+			id=@id
+			AND user_id=@user_id
+			AND deleted_at IS NOT NULL
+		RETURNING
+		*
+	`
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{
 		"id":      commentID,
 		"user_id": userID,
 	})
 	if err != nil {
-		return fmt.Errorf("failed to delete comment: %w", err)
+		return nil, fmt.Errorf("failed to execute restore comment query for comment_id=%s user_id=%s: %w", commentID.String(), userID, err)
 	}
 
-	if result.RowsAffected() == 0 {
-		return fmt.Errorf("comment not found")
+	commentItem, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[comment.Comment])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect row from table:todo_comments for comment_id=%s user_id=%s: %w", commentID.String(), userID, err)
 	}
 
-	return nil
+	return &commentItem, nil
+}
+
+// PurgeCommentsDeletedBefore permanently removes comments trashed before cutoff.
+func (r *CommentRepository) PurgeCommentsDeletedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	result, err := r.server.DB.Pool.Exec(ctx, `
+		DELETE FROM todo_comments
+		WHERE deleted_at < @cutoff
+	`, pgx.NamedArgs{
+		"cutoff": cutoff,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge trashed comments: %w", err)
+	}
+
+	return result.RowsAffected(), nil
 }
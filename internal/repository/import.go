@@ -0,0 +1,243 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/uttam282005/tasker/internal/errs"
+	"github.com/uttam282005/tasker/internal/lib/workspace"
+	"github.com/uttam282005/tasker/internal/model/imports"
+	"github.com/uttam282005/tasker/internal/server"
+)
+
+type ImportRepository struct {
+	server *server.Server
+}
+
+func NewImportRepository(server *server.Server) *ImportRepository {
+	return &ImportRepository{server: server}
+}
+
+// CreateImport stores a pending import together with its file.
+func (r *ImportRepository) CreateImport(ctx context.Context, userID string,
+	payload *imports.CreateImportPayload, fileName string, data []byte,
+) (*imports.Import, error) {
+	tx, err := r.server.DB.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	stmt := `
+		INSERT INTO
+			imports (
+				user_id,
+				org_id,
+				source,
+				file_name,
+				mapping
+			)
+		VALUES
+			(
+				@user_id,
+				@org_id,
+				@source,
+				@file_name,
+				@mapping
+			)
+		RETURNING
+			*
+	`
+
+	rows, err := tx.Query(ctx, stmt, pgx.NamedArgs{
+		"user_id":   userID,
+		"org_id":    workspace.OrgID(ctx),
+		"source":    payload.Source,
+		"file_name": fileName,
+		"mapping":   payload.ColumnMapping,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute create import query for user_id=%s: %w", userID, err)
+	}
+
+	importItem, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[imports.Import])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect row from table:imports for user_id=%s: %w", userID, err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO
+			import_files (import_id, data)
+		VALUES
+			(@import_id, @data)
+	`, pgx.NamedArgs{
+		"import_id": importItem.ID,
+		"data":      data,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to store file for import_id=%s: %w", importItem.ID.String(), err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return &importItem, nil
+}
+
+func (r *ImportRepository) GetImportByID(ctx context.Context, userID string, importID uuid.UUID) (*imports.Import, error) {
+	args := pgx.NamedArgs{"id": importID}
+	stmt := `
+		SELECT
+			*
+		FROM
+			imports
+		WHERE
+			id=@id
+			AND ` + ownerScope(ctx, userID, args) + `
+	`
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get import by id query for import_id=%s user_id=%s: %w", importID.String(), userID, err)
+	}
+
+	importItem, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[imports.Import])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			code := "IMPORT_NOT_FOUND"
+			return nil, errs.NewNotFoundError("import not found", false, &code)
+		}
+		return nil, fmt.Errorf("failed to collect row from table:imports for import_id=%s user_id=%s: %w", importID.String(), userID, err)
+	}
+
+	return &importItem, nil
+}
+
+// ClaimImport marks a pending import as running and returns it with its
+// file. It returns nil when the import has already been claimed, so a job
+// delivered twice doesn't import the file twice.
+func (r *ImportRepository) ClaimImport(ctx context.Context, importID uuid.UUID) (*imports.Import, []byte, error) {
+	stmt := `
+		UPDATE imports
+		SET
+			status='running',
+			started_at=CURRENT_TIMESTAMP
+		WHERE
+			id=@id
+			AND status='pending'
+		RETURNING
+			*
+	`
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{"id": importID})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to execute claim import query for import_id=%s: %w", importID.String(), err)
+	}
+
+	importItem, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[imports.Import])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil, nil
+		}
+		return nil, nil, fmt.Errorf("failed to collect row from table:imports for import_id=%s: %w", importID.String(), err)
+	}
+
+	var data []byte
+	err = r.server.DB.Pool.QueryRow(ctx, `
+		SELECT
+			data
+		FROM
+			import_files
+		WHERE
+			import_id=@import_id
+	`, pgx.NamedArgs{"import_id": importID}).Scan(&data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read file for import_id=%s: %w", importID.String(), err)
+	}
+
+	return &importItem, data, nil
+}
+
+// UpdateImportProgress saves the counts and row errors of a running import.
+func (r *ImportRepository) UpdateImportProgress(ctx context.Context, importItem *imports.Import) error {
+	stmt := `
+		UPDATE imports
+		SET
+			total_rows=@total_rows,
+			processed_rows=@processed_rows,
+			imported_rows=@imported_rows,
+			failed_rows=@failed_rows,
+			row_errors=@row_errors
+		WHERE
+			id=@id
+	`
+
+	_, err := r.server.DB.Pool.Exec(ctx, stmt, importProgressArgs(importItem))
+	if err != nil {
+		return fmt.Errorf("failed to update progress for import_id=%s: %w", importItem.ID.String(), err)
+	}
+
+	return nil
+}
+
+// FinishImport saves the final state of an import and drops its file, which
+// is no longer needed.
+func (r *ImportRepository) FinishImport(ctx context.Context, importItem *imports.Import) error {
+	tx, err := r.server.DB.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	args := importProgressArgs(importItem)
+	args["status"] = importItem.Status
+	args["error"] = importItem.Error
+
+	_, err = tx.Exec(ctx, `
+		UPDATE imports
+		SET
+			status=@status,
+			error=@error,
+			total_rows=@total_rows,
+			processed_rows=@processed_rows,
+			imported_rows=@imported_rows,
+			failed_rows=@failed_rows,
+			row_errors=@row_errors,
+			completed_at=CURRENT_TIMESTAMP
+		WHERE
+			id=@id
+	`, args)
+	if err != nil {
+		return fmt.Errorf("failed to finish import_id=%s: %w", importItem.ID.String(), err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		DELETE FROM import_files
+		WHERE
+			import_id=@id
+	`, args)
+	if err != nil {
+		return fmt.Errorf("failed to delete file for import_id=%s: %w", importItem.ID.String(), err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func importProgressArgs(importItem *imports.Import) pgx.NamedArgs {
+	return pgx.NamedArgs{
+		"id":             importItem.ID,
+		"total_rows":     importItem.TotalRows,
+		"processed_rows": importItem.ProcessedRows,
+		"imported_rows":  importItem.ImportedRows,
+		"failed_rows":    importItem.FailedRows,
+		"row_errors":     importItem.RowErrors,
+	}
+}
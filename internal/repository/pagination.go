@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/uttam282005/tasker/internal/errs"
+	"github.com/uttam282005/tasker/internal/lib/cursor"
+	"github.com/uttam282005/tasker/internal/model"
+)
+
+// sortKey is a column a list can be sorted by: the expression rows are
+// ordered by and the SQL type a cursor's value is read back as. Nullable
+// columns are coalesced so every row has a position.
+type sortKey struct {
+	expr    string
+	sqlType string
+}
+
+// keyset orders a list by a sort key with the row id as the tie-breaker, and
+// optionally starts it at a cursor.
+type keyset struct {
+	sort   string
+	order  string
+	key    sortKey
+	idExpr string
+	cursor *cursor.Cursor
+}
+
+// newKeyset checks that rawCursor, when given, was issued for the same sort.
+func newKeyset(rawCursor *string, sort, order string, key sortKey, idExpr string) (*keyset, error) {
+	k := &keyset{sort: sort, order: order, key: key, idExpr: idExpr}
+
+	if rawCursor == nil {
+		return k, nil
+	}
+
+	c, err := cursor.Decode(*rawCursor)
+	if err == nil && (c.Sort != sort || c.Order != order) {
+		err = cursor.ErrInvalid
+	}
+	if err != nil {
+		code := "INVALID_CURSOR"
+		return nil, errs.NewBadRequestError("Cursor is invalid or was issued for a different sort", false, &code,
+			[]errs.FieldError{{Field: "cursor", Error: "is invalid"}}, nil)
+	}
+
+	k.cursor = c
+	return k, nil
+}
+
+// backward reports whether the page is read towards the start of the list.
+func (k *keyset) backward() bool {
+	return k.cursor != nil && k.cursor.Direction == cursor.DirectionPrev
+}
+
+// condition returns the condition selecting the rows beyond the cursor, or
+// an empty string when there's no cursor.
+func (k *keyset) condition(args pgx.NamedArgs) string {
+	if k.cursor == nil {
+		return ""
+	}
+
+	op := ">"
+	if (k.order == "desc") != k.backward() {
+		op = "<"
+	}
+
+	args["cursor_value"] = k.cursor.Value
+	args["cursor_id"] = k.cursor.ID
+
+	return fmt.Sprintf("(%s, %s) %s (@cursor_value::%s, @cursor_id::uuid)", k.key.expr, k.idExpr, op, k.key.sqlType)
+}
+
+// orderBy returns the ORDER BY clause. Backward pages are read in reverse
+// and put back in order by keysetPage.
+func (k *keyset) orderBy() string {
+	desc := (k.order == "desc") != k.backward()
+
+	direction := "ASC"
+	if desc {
+		direction = "DESC"
+	}
+
+	return fmt.Sprintf(" ORDER BY %s %s, %s %s", k.key.expr, direction, k.idExpr, direction)
+}
+
+func (k *keyset) encode(value string, id uuid.UUID, direction cursor.Direction) *string {
+	encoded := cursor.Cursor{
+		Sort:      k.sort,
+		Order:     k.order,
+		Value:     value,
+		ID:        id,
+		Direction: direction,
+	}.Encode()
+	return &encoded
+}
+
+// keysetPage turns rows fetched with limit+1 into a page, using the extra
+// row only to tell whether more rows follow. position returns a row's sort
+// key value and id.
+func keysetPage[T any](k *keyset, rows []T, limit int,
+	position func(*T) (string, uuid.UUID),
+) *model.CursorPaginatedResponse[T] {
+	hasMore := len(rows) > limit
+	if hasMore {
+		rows = rows[:limit]
+	}
+	if k.backward() {
+		slices.Reverse(rows)
+	}
+
+	page := &model.CursorPaginatedResponse[T]{
+		Data:  rows,
+		Limit: limit,
+	}
+
+	if len(rows) == 0 {
+		// Nothing beyond the cursor, but the rows on its other side are
+		// still there
+		if k.cursor != nil {
+			if k.backward() {
+				page.NextCursor = k.encode(k.cursor.Value, k.cursor.ID, cursor.DirectionNext)
+			} else {
+				page.PrevCursor = k.encode(k.cursor.Value, k.cursor.ID, cursor.DirectionPrev)
+			}
+		}
+		return page
+	}
+
+	firstValue, firstID := position(&rows[0])
+	lastValue, lastID := position(&rows[len(rows)-1])
+
+	if hasMore || k.backward() {
+		page.NextCursor = k.encode(lastValue, lastID, cursor.DirectionNext)
+	}
+	if (hasMore && k.backward()) || (k.cursor != nil && !k.backward()) {
+		page.PrevCursor = k.encode(firstValue, firstID, cursor.DirectionPrev)
+	}
+
+	return page
+}
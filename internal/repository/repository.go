@@ -1,11 +1,72 @@
 package repository
 
-import "github.com/uttam282005/tasker/internal/server"
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/uttam282005/tasker/internal/lib/workspace"
+	"github.com/uttam282005/tasker/internal/server"
+)
+
+// querier is satisfied by both the connection pool and a transaction, so
+// helpers can run standalone or as part of a larger unit of work.
+type querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+}
+
+// workspaceScope returns the condition matching rows that belong to the
+// workspace the request runs in: every row of the active organization, or the
+// user's own personal rows. alias qualifies the columns when non-empty. It
+// sets the arguments the condition references.
+func workspaceScope(ctx context.Context, alias, userID string, args pgx.NamedArgs) string {
+	prefix := ""
+	if alias != "" {
+		prefix = alias + "."
+	}
+
+	if orgID := workspace.OrgID(ctx); orgID != nil {
+		args["org_id"] = *orgID
+		return prefix + "org_id = @org_id"
+	}
+
+	args["user_id"] = userID
+	return prefix + "user_id = @user_id AND " + prefix + "org_id IS NULL"
+}
+
+// ownerScope matches the user's own rows in the request's workspace, for
+// things that stay personal even in an organization, like calendar feeds,
+// which read as their owner, and imports.
+func ownerScope(ctx context.Context, userID string, args pgx.NamedArgs) string {
+	args["user_id"] = userID
+	args["org_id"] = workspace.OrgID(ctx)
+	return "user_id=@user_id AND org_id IS NOT DISTINCT FROM @org_id::TEXT"
+}
+
+// versionCondition returns the condition a versioned write adds to its WHERE
+// clause, or nothing when version is nil. It sets the argument it references.
+func versionCondition(version *int, args pgx.NamedArgs) string {
+	if version == nil {
+		return ""
+	}
+
+	args["version"] = *version
+	return " AND version = @version"
+}
 
 type Repositories struct {
 	Todo     *TodoRepository
 	Comment  *CommentRepository
 	Category *CategoryRepository
+	Search   *SearchRepository
+	Activity *ActivityRepository
+	Share    *ShareRepository
+	Webhook  *WebhookRepository
+	Calendar *CalendarRepository
+	Import   *ImportRepository
+	Tag      *TagRepository
+	View     *ViewRepository
 }
 
 func NewRepositories(s *server.Server) *Repositories {
@@ -13,5 +74,13 @@ func NewRepositories(s *server.Server) *Repositories {
 		Todo:     NewTodoRepository(s),
 		Comment:  NewCommentRepository(s),
 		Category: NewCategoryRepository(s),
+		Search:   NewSearchRepository(s),
+		Activity: NewActivityRepository(s),
+		Share:    NewShareRepository(s),
+		Webhook:  NewWebhookRepository(s),
+		Calendar: NewCalendarRepository(s),
+		Import:   NewImportRepository(s),
+		Tag:      NewTagRepository(s),
+		View:     NewViewRepository(s),
 	}
 }
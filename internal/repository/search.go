@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/uttam282005/tasker/internal/lib/workspace"
+	"github.com/uttam282005/tasker/internal/model"
+	"github.com/uttam282005/tasker/internal/model/search"
+	"github.com/uttam282005/tasker/internal/server"
+)
+
+type SearchRepository struct {
+	server *server.Server
+}
+
+func NewSearchRepository(server *server.Server) *SearchRepository {
+	return &SearchRepository{server: server}
+}
+
+const (
+	searchTodoHits = `
+		SELECT
+			'todo' AS type,
+			t.id,
+			t.id AS todo_id,
+			t.title,
+			concat_ws(' ', t.title, t.description) AS document,
+			ts_rank_cd(t.search_vector, q.query) AS rank,
+			t.created_at
+		FROM
+			todos t,
+			q
+		WHERE
+			t.id IN (
+				SELECT
+					todo_id
+				FROM
+					accessible_todos(@user_id, @org_id)
+			)
+			AND t.deleted_at IS NULL
+			AND t.search_vector @@ q.query
+	`
+
+	searchCommentHits = `
+		SELECT
+			'comment' AS type,
+			com.id,
+			com.todo_id,
+			t.title,
+			com.content AS document,
+			ts_rank_cd(com.search_vector, q.query) AS rank,
+			com.created_at
+		FROM
+			todo_comments com
+			JOIN todos t ON t.id=com.todo_id
+			AND t.deleted_at IS NULL,
+			q
+		WHERE
+			t.id IN (
+				SELECT
+					todo_id
+				FROM
+					accessible_todos(@user_id, @org_id)
+			)
+			AND com.deleted_at IS NULL
+			AND com.search_vector @@ q.query
+	`
+)
+
+func (r *SearchRepository) Search(ctx context.Context, userID string,
+	query *search.SearchQuery,
+) (*model.PaginatedResponse[search.Hit], error) {
+	sources := []string{}
+	if query.Type == nil || *query.Type == search.HitTypeTodo {
+		sources = append(sources, searchTodoHits)
+	}
+	if query.Type == nil || *query.Type == search.HitTypeComment {
+		sources = append(sources, searchCommentHits)
+	}
+
+	hits := `
+		WITH
+			q AS (
+				SELECT
+					websearch_to_tsquery('english', @query) AS query
+			),
+			hits AS (` + strings.Join(sources, " UNION ALL ") + `)
+	`
+
+	args := pgx.NamedArgs{
+		"user_id": userID,
+		"org_id":  workspace.OrgID(ctx),
+		"query":   query.Q,
+	}
+
+	var total int
+	err := r.server.DB.Pool.QueryRow(ctx, hits+" SELECT COUNT(*) FROM hits", args).Scan(&total)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get total count of search hits for user_id=%s: %w", userID, err)
+	}
+
+	// Headlines are costly, so only build them for the page being returned
+	stmt := hits + `,
+			page AS (
+				SELECT
+					*
+				FROM
+					hits
+				ORDER BY
+					rank DESC,
+					created_at DESC
+				LIMIT
+					@limit
+				OFFSET
+					@offset
+			)
+		SELECT
+			page.type,
+			page.id,
+			page.todo_id,
+			page.title,
+			ts_headline('english', page.document, q.query, 'MaxFragments=2, MinWords=5, MaxWords=20') AS snippet,
+			page.rank,
+			page.created_at
+		FROM
+			page,
+			q
+		ORDER BY
+			page.rank DESC,
+			page.created_at DESC
+	`
+	args["limit"] = *query.Limit
+	args["offset"] = (*query.Page - 1) * (*query.Limit)
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute search query for user_id=%s: %w", userID, err)
+	}
+
+	results, err := pgx.CollectRows(rows, pgx.RowToStructByName[search.Hit])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect search hits for user_id=%s: %w", userID, err)
+	}
+
+	return &model.PaginatedResponse[search.Hit]{
+		Data:       results,
+		Page:       *query.Page,
+		Limit:      *query.Limit,
+		Total:      total,
+		TotalPages: (total + *query.Limit - 1) / *query.Limit,
+	}, nil
+}
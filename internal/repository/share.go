@@ -0,0 +1,289 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/uttam282005/tasker/internal/errs"
+	"github.com/uttam282005/tasker/internal/model"
+	"github.com/uttam282005/tasker/internal/model/share"
+	"github.com/uttam282005/tasker/internal/server"
+)
+
+type ShareRepository struct {
+	server *server.Server
+}
+
+func NewShareRepository(server *server.Server) *ShareRepository {
+	return &ShareRepository{server: server}
+}
+
+// CreateShare grants granteeID a role on either todoID or categoryID; the
+// other one must be nil.
+func (r *ShareRepository) CreateShare(ctx context.Context, ownerID, granteeID string,
+	todoID, categoryID *uuid.UUID, role share.Role,
+) (*share.Share, error) {
+	stmt := `
+		INSERT INTO
+			todo_shares (
+				owner_id,
+				grantee_id,
+				todo_id,
+				category_id,
+				role
+			)
+		VALUES
+			(
+				@owner_id,
+				@grantee_id,
+				@todo_id,
+				@category_id,
+				@role
+			)
+		RETURNING
+			*
+	`
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{
+		"owner_id":    ownerID,
+		"grantee_id":  granteeID,
+		"todo_id":     todoID,
+		"category_id": categoryID,
+		"role":        role,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute create share query for owner_id=%s grantee_id=%s: %w", ownerID, granteeID, err)
+	}
+
+	shareItem, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[share.Share])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect row from table:todo_shares for owner_id=%s grantee_id=%s: %w", ownerID, granteeID, err)
+	}
+
+	return &shareItem, nil
+}
+
+func (r *ShareRepository) GetTodoShares(ctx context.Context, userID string, todoID uuid.UUID) ([]share.Share, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			todo_shares
+		WHERE
+			todo_id=@todo_id
+			AND owner_id=@user_id
+		ORDER BY
+			created_at ASC
+	`
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{
+		"todo_id": todoID,
+		"user_id": userID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get todo shares query for todo_id=%s user_id=%s: %w", todoID.String(), userID, err)
+	}
+
+	shares, err := pgx.CollectRows(rows, pgx.RowToStructByName[share.Share])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:todo_shares for todo_id=%s user_id=%s: %w", todoID.String(), userID, err)
+	}
+
+	return shares, nil
+}
+
+func (r *ShareRepository) GetCategoryShares(ctx context.Context, userID string, categoryID uuid.UUID) ([]share.Share, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			todo_shares
+		WHERE
+			category_id=@category_id
+			AND owner_id=@user_id
+		ORDER BY
+			created_at ASC
+	`
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{
+		"category_id": categoryID,
+		"user_id":     userID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get category shares query for category_id=%s user_id=%s: %w", categoryID.String(), userID, err)
+	}
+
+	shares, err := pgx.CollectRows(rows, pgx.RowToStructByName[share.Share])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:todo_shares for category_id=%s user_id=%s: %w", categoryID.String(), userID, err)
+	}
+
+	return shares, nil
+}
+
+// GetShareByID returns a share granted by or to the user.
+func (r *ShareRepository) GetShareByID(ctx context.Context, userID string, shareID uuid.UUID) (*share.Share, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			todo_shares
+		WHERE
+			id=@id
+			AND (
+				owner_id=@user_id
+				OR grantee_id=@user_id
+			)
+	`
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{
+		"id":      shareID,
+		"user_id": userID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get share by id query for share_id=%s user_id=%s: %w", shareID.String(), userID, err)
+	}
+
+	shareItem, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[share.Share])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect row from table:todo_shares for share_id=%s user_id=%s: %w", shareID.String(), userID, err)
+	}
+
+	return &shareItem, nil
+}
+
+func (r *ShareRepository) UpdateShareRole(ctx context.Context, userID string, shareID uuid.UUID, role share.Role) (*share.Share, error) {
+	stmt := `
+		UPDATE
+			todo_shares
+		SET
+			role=@role
+		WHERE
+			id=@id
+			AND owner_id=@user_id
+		RETURNING
+			*
+	`
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{
+		"id":      shareID,
+		"user_id": userID,
+		"role":    role,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute update share query for share_id=%s user_id=%s: %w", shareID.String(), userID, err)
+	}
+
+	shareItem, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[share.Share])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect row from table:todo_shares for share_id=%s user_id=%s: %w", shareID.String(), userID, err)
+	}
+
+	return &shareItem, nil
+}
+
+// DeleteShare revokes a share. Grantees may remove shares granted to them.
+func (r *ShareRepository) DeleteShare(ctx context.Context, userID string, shareID uuid.UUID) error {
+	result, err := r.server.DB.Pool.Exec(ctx, `
+		DELETE FROM todo_shares
+		WHERE id = @id AND (owner_id = @user_id OR grantee_id = @user_id)
+	`, pgx.NamedArgs{
+		"id":      shareID,
+		"user_id": userID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete share: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		code := "SHARE_NOT_FOUND"
+		return errs.NewNotFoundError("share not found", false, &code)
+	}
+
+	return nil
+}
+
+// GetSharedWithMe lists the shares granted to the user whose todo or category
+// is not in the trash.
+func (r *ShareRepository) GetSharedWithMe(ctx context.Context, userID string,
+	query *share.GetSharedWithMeQuery,
+) (*model.PaginatedResponse[share.SharedItem], error) {
+	conditions := `
+			s.grantee_id=@user_id
+			AND (
+				t.id IS NOT NULL
+				OR c.id IS NOT NULL
+			)
+	`
+	args := pgx.NamedArgs{
+		"user_id": userID,
+	}
+
+	if query.Type != nil {
+		if *query.Type == "todo" {
+			conditions += " AND s.todo_id IS NOT NULL"
+		} else {
+			conditions += " AND s.category_id IS NOT NULL"
+		}
+	}
+
+	from := `
+		FROM
+			todo_shares s
+			LEFT JOIN todos t ON t.id=s.todo_id
+			AND t.deleted_at IS NULL
+			LEFT JOIN todo_categories c ON c.id=s.category_id
+			AND c.deleted_at IS NULL
+	`
+
+	stmt := `
+		SELECT
+			s.*,
+			CASE
+				WHEN t.id IS NOT NULL THEN to_jsonb(camel (t))
+				ELSE NULL
+			END AS todo,
+			CASE
+				WHEN c.id IS NOT NULL THEN to_jsonb(camel (c))
+				ELSE NULL
+			END AS category
+	` + from + `
+		WHERE
+	` + conditions + `
+		ORDER BY
+			s.created_at DESC
+		LIMIT
+			@limit
+		OFFSET
+			@offset
+	`
+
+	args["limit"] = *query.Limit
+	args["offset"] = (*query.Page - 1) * (*query.Limit)
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get shared with me query for user_id=%s: %w", userID, err)
+	}
+
+	items, err := pgx.CollectRows(rows, pgx.RowToStructByName[share.SharedItem])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:todo_shares for user_id=%s: %w", userID, err)
+	}
+
+	var total int
+	err = r.server.DB.Pool.QueryRow(ctx, "SELECT COUNT(*)"+from+" WHERE "+conditions, args).Scan(&total)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get total count of shared items for user_id=%s: %w", userID, err)
+	}
+
+	return &model.PaginatedResponse[share.SharedItem]{
+		Data:       items,
+		Page:       *query.Page,
+		Limit:      *query.Limit,
+		Total:      total,
+		TotalPages: (total + *query.Limit - 1) / *query.Limit,
+	}, nil
+}
@@ -0,0 +1,263 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/uttam282005/tasker/internal/errs"
+	"github.com/uttam282005/tasker/internal/lib/workspace"
+	"github.com/uttam282005/tasker/internal/model/tag"
+	"github.com/uttam282005/tasker/internal/model/todo"
+	"github.com/uttam282005/tasker/internal/server"
+)
+
+type TagRepository struct {
+	server *server.Server
+}
+
+func NewTagRepository(server *server.Server) *TagRepository {
+	return &TagRepository{server: server}
+}
+
+// tagUsageColumn counts the todos outside the trash a tag tg is on.
+const tagUsageColumn = `(
+				SELECT
+					COUNT(*)
+				FROM
+					todo_tags tt
+					JOIN todos t ON t.id=tt.todo_id
+				WHERE
+					tt.tag_id=tg.id
+					AND t.deleted_at IS NULL
+			) AS usage_count`
+
+func (r *TagRepository) CreateTag(ctx context.Context, userID string, payload *tag.CreateTagPayload) (*tag.Tag, error) {
+	stmt := `
+		INSERT INTO
+			tags (
+				user_id,
+				org_id,
+				name,
+				color
+			)
+		VALUES
+			(
+				@user_id,
+				@org_id,
+				@name,
+				COALESCE(@color, '#6b7280')
+			)
+		RETURNING
+			*
+	`
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{
+		"user_id": userID,
+		"org_id":  workspace.OrgID(ctx),
+		"name":    payload.Name,
+		"color":   payload.Color,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute create tag query for user_id=%s name=%s: %w", userID, payload.Name, err)
+	}
+
+	tagItem, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[tag.Tag])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect row from table:tags for user_id=%s name=%s: %w", userID, payload.Name, err)
+	}
+
+	return &tagItem, nil
+}
+
+// GetTags lists the workspace's tags with how many todos each is on.
+func (r *TagRepository) GetTags(ctx context.Context, userID string, query *tag.GetTagsQuery) ([]tag.TagUsage, error) {
+	args := pgx.NamedArgs{}
+	conditions := []string{workspaceScope(ctx, "tg", userID, args)}
+
+	if query.Search != nil {
+		conditions = append(conditions, "tg.name ILIKE @search")
+		args["search"] = "%" + *query.Search + "%"
+	}
+
+	stmt := `
+		SELECT
+			tg.*,
+			` + tagUsageColumn + `
+		FROM
+			tags tg
+		WHERE
+			` + strings.Join(conditions, " AND ") + `
+		ORDER BY
+			` + *query.Sort + ` ` + *query.Order + `,
+			tg.name ASC
+	`
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get tags query for user_id=%s: %w", userID, err)
+	}
+
+	tags, err := pgx.CollectRows(rows, pgx.RowToStructByName[tag.TagUsage])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:tags for user_id=%s: %w", userID, err)
+	}
+
+	return tags, nil
+}
+
+func (r *TagRepository) GetTagByID(ctx context.Context, userID string, tagID uuid.UUID) (*tag.TagUsage, error) {
+	args := pgx.NamedArgs{"id": tagID}
+	stmt := `
+		SELECT
+			tg.*,
+			` + tagUsageColumn + `
+		FROM
+			tags tg
+		WHERE
+			tg.id=@id
+			AND ` + workspaceScope(ctx, "tg", userID, args) + `
+	`
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get tag by id query for tag_id=%s user_id=%s: %w", tagID.String(), userID, err)
+	}
+
+	tagItem, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[tag.TagUsage])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			code := "TAG_NOT_FOUND"
+			return nil, errs.NewNotFoundError("tag not found", false, &code)
+		}
+		return nil, fmt.Errorf("failed to collect row from table:tags for tag_id=%s user_id=%s: %w", tagID.String(), userID, err)
+	}
+
+	return &tagItem, nil
+}
+
+func (r *TagRepository) UpdateTag(ctx context.Context, userID string, payload *tag.UpdateTagPayload) (*tag.Tag, error) {
+	args := pgx.NamedArgs{"id": payload.ID}
+	setClauses := []string{}
+
+	if payload.Name != nil {
+		setClauses = append(setClauses, "name = @name")
+		args["name"] = *payload.Name
+	}
+
+	if payload.Color != nil {
+		setClauses = append(setClauses, "color = @color")
+		args["color"] = *payload.Color
+	}
+
+	if len(setClauses) == 0 {
+		return nil, errs.NewBadRequestError("no fields to update", false, nil, nil, nil)
+	}
+
+	stmt := "UPDATE tags SET " + strings.Join(setClauses, ", ") +
+		" WHERE id = @id AND " + workspaceScope(ctx, "", userID, args) + " RETURNING *"
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute update tag query for tag_id=%s user_id=%s: %w", payload.ID.String(), userID, err)
+	}
+
+	tagItem, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[tag.Tag])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			code := "TAG_NOT_FOUND"
+			return nil, errs.NewNotFoundError("tag not found", false, &code)
+		}
+		return nil, fmt.Errorf("failed to collect row from table:tags for tag_id=%s user_id=%s: %w", payload.ID.String(), userID, err)
+	}
+
+	return &tagItem, nil
+}
+
+// DeleteTag deletes the tag and takes it off every todo.
+func (r *TagRepository) DeleteTag(ctx context.Context, userID string, tagID uuid.UUID) error {
+	args := pgx.NamedArgs{"id": tagID}
+	stmt := `
+		DELETE FROM tags
+		WHERE
+			id=@id
+			AND ` + workspaceScope(ctx, "", userID, args) + `
+	`
+
+	result, err := r.server.DB.Pool.Exec(ctx, stmt, args)
+	if err != nil {
+		return fmt.Errorf("failed to execute delete tag query for tag_id=%s user_id=%s: %w", tagID.String(), userID, err)
+	}
+
+	if result.RowsAffected() == 0 {
+		code := "TAG_NOT_FOUND"
+		return errs.NewNotFoundError("tag not found", false, &code)
+	}
+
+	return nil
+}
+
+// setTodoTags replaces the todo's tags with the ones named, creating those
+// its workspace doesn't have yet. The tags come from the todo's workspace
+// rather than the request's, so an editor of a shared todo tags it with the
+// owner's tags.
+func setTodoTags(ctx context.Context, q querier, t *todo.Todo, names []string) error {
+	args := pgx.NamedArgs{
+		"todo_id": t.ID,
+		"user_id": t.UserID,
+		"org_id":  t.OrgID,
+		"names":   names,
+	}
+	if names == nil {
+		args["names"] = []string{}
+	}
+
+	// Personal tags belong to the todo's owner, an organization's to all of
+	// its members
+	scope := "org_id IS NOT DISTINCT FROM @org_id::TEXT AND (@org_id::TEXT IS NOT NULL OR user_id=@user_id)"
+
+	stmt := `
+		INSERT INTO
+			tags (user_id, org_id, name)
+		SELECT
+			@user_id,
+			@org_id,
+			name
+		FROM
+			UNNEST(@names::TEXT[]) AS name
+		ON CONFLICT DO NOTHING
+	`
+	if _, err := q.Exec(ctx, stmt, args); err != nil {
+		return fmt.Errorf("failed to create tags for todo_id=%s: %w", t.ID.String(), err)
+	}
+
+	stmt = `
+		DELETE FROM todo_tags
+		WHERE
+			todo_id=@todo_id
+	`
+	if _, err := q.Exec(ctx, stmt, args); err != nil {
+		return fmt.Errorf("failed to clear tags of todo_id=%s: %w", t.ID.String(), err)
+	}
+
+	stmt = `
+		INSERT INTO
+			todo_tags (todo_id, tag_id)
+		SELECT
+			@todo_id,
+			id
+		FROM
+			tags
+		WHERE
+			name=ANY (@names::TEXT[])
+			AND ` + scope + `
+	`
+	if _, err := q.Exec(ctx, stmt, args); err != nil {
+		return fmt.Errorf("failed to tag todo_id=%s: %w", t.ID.String(), err)
+	}
+
+	return nil
+}
@@ -2,15 +2,24 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/uttam282005/tasker/internal/errs"
+	"github.com/uttam282005/tasker/internal/lib/etag"
+	"github.com/uttam282005/tasker/internal/lib/filterql"
+	"github.com/uttam282005/tasker/internal/lib/mergepatch"
+	"github.com/uttam282005/tasker/internal/lib/rrule"
+	"github.com/uttam282005/tasker/internal/lib/workspace"
 	"github.com/uttam282005/tasker/internal/model"
+	"github.com/uttam282005/tasker/internal/model/share"
 	"github.com/uttam282005/tasker/internal/model/todo"
 	"github.com/uttam282005/tasker/internal/server"
 )
@@ -28,24 +37,36 @@ func (r *TodoRepository) CreateTodo(ctx context.Context, userID string, payload
 		INSERT INTO
 			todos (
 				user_id,
+				org_id,
 				title,
 				description,
 				priority,
 				due_date,
 				parent_todo_id,
 				category_id,
-				metadata
+				metadata,
+				recurrence,
+				recurrence_series_id,
+				recurrence_index,
+				recurrence_date,
+				assignee_id
 			)
 		VALUES
 			(
 				@user_id,
+				@org_id,
 				@title,
 				@description,
 				@priority,
 				@due_date,
 				@parent_todo_id,
 				@category_id,
-				@metadata
+				@metadata,
+				@recurrence,
+				@recurrence_series_id,
+				@recurrence_index,
+				@recurrence_date,
+				@assignee_id
 			)
 		RETURNING
 		*
@@ -55,16 +76,42 @@ func (r *TodoRepository) CreateTodo(ctx context.Context, userID string, payload
 		priority = *payload.Priority
 	}
 
-	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{
-		"user_id":        userID,
-		"title":          payload.Title,
-		"description":    payload.Description,
-		"priority":       priority,
-		"due_date":       payload.DueDate,
-		"parent_todo_id": payload.ParentTodoID,
-		"category_id":    payload.CategoryID,
-		"metadata":       payload.Metadata,
-	})
+	args := pgx.NamedArgs{
+		"user_id":              userID,
+		"org_id":               workspace.OrgID(ctx),
+		"title":                payload.Title,
+		"description":          payload.Description,
+		"priority":             priority,
+		"due_date":             payload.DueDate,
+		"parent_todo_id":       payload.ParentTodoID,
+		"category_id":          payload.CategoryID,
+		"metadata":             payload.Metadata,
+		"recurrence":           nil,
+		"recurrence_series_id": nil,
+		"recurrence_index":     nil,
+		"recurrence_date":      nil,
+		"assignee_id":          payload.AssigneeID,
+	}
+
+	// A recurring todo is the first occurrence of a new series
+	if payload.Recurrence != nil {
+		rule, err := rrule.Parse(*payload.Recurrence)
+		if err != nil {
+			return nil, errs.NewBadRequestError("invalid recurrence rule: "+err.Error(), false, nil, nil, nil)
+		}
+		args["recurrence"] = rule.String()
+		args["recurrence_series_id"] = uuid.New()
+		args["recurrence_index"] = 0
+		args["recurrence_date"] = payload.DueDate
+	}
+
+	tx, err := r.server.DB.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, stmt, args)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute create todo query for user_id=%s title=%s: %w", userID, payload.Title, err)
 	}
@@ -74,71 +121,185 @@ func (r *TodoRepository) CreateTodo(ctx context.Context, userID string, payload
 		return nil, fmt.Errorf("failed to collect row from table:todos for user_id=%s title=%s: %w", userID, payload.Title, err)
 	}
 
+	if len(payload.Tags) > 0 {
+		if err := setTodoTags(ctx, tx, &todoItem, payload.Tags); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
 	return &todoItem, nil
 }
 
-func (r *TodoRepository) GetTodoByID(ctx context.Context, userID string, todoID uuid.UUID) (*todo.PopulatedTodo, error) {
-	stmt := `
+// todoRelationQueries aggregate each relation of a todo t into a JSON array
+// named items.
+var todoRelationQueries = map[string]string{
+	todo.RelationChildren: `
+			SELECT
+				COALESCE(
+					jsonb_agg(
+						to_jsonb(camel (child))
+						ORDER BY
+							child.sort_order ASC,
+							child.created_at ASC
+					),
+					'[]'::JSONB
+				) AS items
+			FROM
+				todos child
+			WHERE
+				child.parent_todo_id=t.id
+				AND child.deleted_at IS NULL`,
+	todo.RelationComments: `
+			SELECT
+				COALESCE(
+					jsonb_agg(
+						to_jsonb(camel (com))
+						ORDER BY
+							com.created_at ASC
+					),
+					'[]'::JSONB
+				) AS items
+			FROM
+				todo_comments com
+			WHERE
+				com.todo_id=t.id
+				AND com.deleted_at IS NULL`,
+	todo.RelationAttachments: `
+			SELECT
+				COALESCE(
+					jsonb_agg(
+						to_jsonb(camel (att))
+						ORDER BY
+							att.created_at DESC
+					),
+					'[]'::JSONB
+				) AS items
+			FROM
+				todo_attachments att
+			WHERE
+				att.todo_id=t.id`,
+	"blocked_by": `
+			SELECT
+				COALESCE(
+					jsonb_agg(
+						to_jsonb(camel (blocker))
+						ORDER BY
+							blocker.created_at ASC
+					),
+					'[]'::JSONB
+				) AS items
+			FROM
+				todo_dependencies dep
+				JOIN todos blocker ON blocker.id=dep.blocking_todo_id
+			WHERE
+				dep.blocked_todo_id=t.id
+				AND blocker.deleted_at IS NULL
+				AND todo_role(blocker.id, @user_id, t.org_id) IS NOT NULL`,
+	"blocking": `
+			SELECT
+				COALESCE(
+					jsonb_agg(
+						to_jsonb(camel (blocked))
+						ORDER BY
+							blocked.created_at ASC
+					),
+					'[]'::JSONB
+				) AS items
+			FROM
+				todo_dependencies dep
+				JOIN todos blocked ON blocked.id=dep.blocked_todo_id
+			WHERE
+				dep.blocking_todo_id=t.id
+				AND blocked.deleted_at IS NULL
+				AND todo_role(blocked.id, @user_id, t.org_id) IS NOT NULL`,
+	"tags": `
+			SELECT
+				COALESCE(
+					jsonb_agg(
+						to_jsonb(camel (tg))
+						ORDER BY
+							tg.name ASC
+					),
+					'[]'::JSONB
+				) AS items
+			FROM
+				todo_tags tt
+				JOIN tags tg ON tg.id=tt.tag_id
+			WHERE
+				tt.todo_id=t.id`,
+	"watchers": `
+			SELECT
+				COALESCE(
+					jsonb_agg(
+						w.user_id
+						ORDER BY
+							w.created_at ASC
+					),
+					'[]'::JSONB
+				) AS items
+			FROM
+				todo_watchers w
+			WHERE
+				w.todo_id=t.id`,
+}
+
+// populatedTodoSelect returns the SELECT and FROM clauses reading todos t as
+// PopulatedTodo rows, for a WHERE clause to follow. Each relation is
+// aggregated in its own LATERAL subquery, so a todo lists every child,
+// comment and attachment exactly once however many of the others it has.
+// Relations missing from include come back as null. The category is read from
+// the todo's own workspace, where in an organization it may belong to any
+// member, and is left out once trashed. Blockers and blocked todos are
+// limited to those @user_id can see in the todo's workspace. joins are added
+// to the FROM clause after the todos and their categories.
+func populatedTodoSelect(include []string, joins ...string) string {
+	columns := []string{
+		"t.*",
+		"CASE WHEN c.id IS NOT NULL THEN to_jsonb(camel (c)) ELSE NULL END AS category",
+	}
+	joins = append([]string{
+		"todos t",
+		"LEFT JOIN todo_categories c ON c.id=t.category_id AND c.org_id IS NOT DISTINCT FROM t.org_id " +
+			"AND (t.org_id IS NOT NULL OR c.user_id=t.user_id) AND c.deleted_at IS NULL",
+	}, joins...)
+
+	for _, relation := range []string{
+		todo.RelationChildren, todo.RelationComments, todo.RelationAttachments, "blocked_by", "blocking", "watchers", "tags",
+	} {
+		// Only the relations a caller can ask for are optional
+		if slices.Contains(todo.Relations, relation) && !slices.Contains(include, relation) {
+			columns = append(columns, "NULL::JSONB AS "+relation)
+			continue
+		}
+
+		columns = append(columns, relation+"_rel.items AS "+relation)
+		joins = append(joins, "CROSS JOIN LATERAL ("+todoRelationQueries[relation]+"\n\t\t) "+relation+"_rel")
+	}
+
+	return `
 	SELECT
-		t.*,
-		CASE
-			WHEN c.id IS NOT NULL THEN to_jsonb(camel (c))
-			ELSE NULL
-		END AS category,
-		COALESCE(
-			jsonb_agg(
-				to_jsonb(camel (child))
-				ORDER BY
-					child.sort_order ASC,
-					child.created_at ASC
-			) FILTER (
-				WHERE
-					child.id IS NOT NULL
-			),
-			'[]'::JSONB
-		) AS children,
-		COALESCE(
-			jsonb_agg(
-				to_jsonb(camel (com))
-				ORDER BY
-					com.created_at ASC
-			) FILTER (
-				WHERE
-					com.id IS NOT NULL
-			),
-			'[]'::JSONB
-		) AS comments,
-		 COALESCE(
-				jsonb_agg(
-					to_jsonb(camel (att))
-					ORDER BY
-						att.created_at DESC
-				) FILTER (
-					WHERE
-						att.id IS NOT NULL
-				),
-				'[]'::JSONB
-			) AS attachments
+		` + strings.Join(columns, ",\n\t\t") + `
 	FROM
-		todos t
-		LEFT JOIN todo_categories c ON c.id=t.category_id
-		AND c.user_id=@user_id
-		LEFT JOIN todos child ON child.parent_todo_id=t.id
-		AND child.user_id=@user_id
-		LEFT JOIN todo_comments com ON com.todo_id=t.id
-		AND com.user_id=@user_id
-		LEFT JOIN todo_attachments att ON att.todo_id=t.id
+		` + strings.Join(joins, "\n\t\t") + `
+`
+}
+
+func (r *TodoRepository) GetTodoByID(ctx context.Context, userID string, todoID uuid.UUID) (*todo.PopulatedTodo, error) {
+	stmt := populatedTodoSelect(todo.Relations) + `
 	WHERE
 		t.id=@id
-		AND t.user_id=@user_id
-	GROUP BY
-		t.id,
-		c.id
+		AND todo_role(t.id, @user_id, @org_id) IS NOT NULL
+		AND t.deleted_at IS NULL
 `
 
 	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{
 		"id":      todoID,
 		"user_id": userID,
+		"org_id":  workspace.OrgID(ctx),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute get todo by id query for todo_id=%s user_id=%s: %w", todoID.String(), userID, err)
@@ -152,206 +313,418 @@ func (r *TodoRepository) GetTodoByID(ctx context.Context, userID string, todoID
 	return &todoItem, nil
 }
 
+// CheckTodoExists returns the todo when the user can access it with any role.
 func (r *TodoRepository) CheckTodoExists(ctx context.Context, userID string, todoID uuid.UUID) (*todo.Todo, error) {
+	todoItem, _, err := r.GetTodoWithRole(ctx, userID, todoID)
+	return todoItem, err
+}
+
+// GetTodoWithRole returns the todo together with the strongest role the user
+// holds on it, either as its owner or through a share.
+func (r *TodoRepository) GetTodoWithRole(ctx context.Context, userID string, todoID uuid.UUID) (*todo.Todo, share.Role, error) {
 	stmt := `
 		SELECT
 			*
 		FROM
-			todos
+			(
+				SELECT
+					t.*,
+					todo_role(t.id, @user_id, @org_id) AS role
+				FROM
+					todos t
+				WHERE
+					t.id=@id
+					AND t.deleted_at IS NULL
+			) accessible
 		WHERE
-			id=@id
-			AND user_id=@user_id
+			role IS NOT NULL
 	`
 
 	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{
 		"id":      todoID,
 		"user_id": userID,
+		"org_id":  workspace.OrgID(ctx),
 	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to check if todo exists for todo_id=%s user_id=%s: %w", todoID.String(), userID, err)
+		return nil, "", fmt.Errorf("failed to check if todo exists for todo_id=%s user_id=%s: %w", todoID.String(), userID, err)
 	}
 
-	todoItem, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[todo.Todo])
+	item, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[struct {
+		todo.Todo
+		Role share.Role `db:"role"`
+	}])
 	if err != nil {
-		return nil, fmt.Errorf("failed to collect row from table:todos for todo_id=%s user_id=%s: %w", todoID.String(), userID, err)
+		return nil, "", fmt.Errorf("failed to collect row from table:todos for todo_id=%s user_id=%s: %w", todoID.String(), userID, err)
 	}
 
-	return &todoItem, nil
+	return &item.Todo, item.Role, nil
 }
 
-func (r *TodoRepository) GetTodos(ctx context.Context, userID string, query *todo.GetTodosQuery) (*model.PaginatedResponse[todo.PopulatedTodo], error) {
-	stmt := `
-	SELECT
-		t.*,
-		CASE
-			WHEN c.id IS NOT NULL THEN to_jsonb(camel (c))
-			ELSE NULL
-		END AS category,
-		COALESCE(
-			jsonb_agg(
-				to_jsonb(camel (child))
-				ORDER BY
-					child.sort_order ASC,
-					child.created_at ASC
-			) FILTER (
-				WHERE
-					child.id IS NOT NULL
-			),
-			'[]'::JSONB
-		) AS children,
-		COALESCE(
-			jsonb_agg(
-				to_jsonb(camel (com))
-				ORDER BY
-					com.created_at ASC
-			) FILTER (
-				WHERE
-					com.id IS NOT NULL
-			),
-			'[]'::JSONB
-		) AS comments,
-		COALESCE(
-				jsonb_agg(
-					to_jsonb(camel (att))
-					ORDER BY
-						att.created_at DESC
-				) FILTER (
-					WHERE
-						att.id IS NOT NULL
-				),
-				'[]'::JSONB
-			) AS attachments
-	FROM
-		todos t
-		LEFT JOIN todo_categories c ON c.id=t.category_id
-		AND c.user_id=@user_id
-		LEFT JOIN todos child ON child.parent_todo_id=t.id
-		AND child.user_id=@user_id
-		LEFT JOIN todo_comments com ON com.todo_id=t.id
-		AND com.user_id=@user_id
-		LEFT JOIN todo_attachments att ON att.todo_id=t.id
-`
+func (r *TodoRepository) GetTodos(ctx context.Context, userID string, query *todo.GetTodosQuery) (*model.CursorPaginatedResponse[todo.PopulatedTodo], error) {
+	stmt := populatedTodoSelect(query.IncludedRelations())
+
+	args := pgx.NamedArgs{
+		"user_id": userID,
+		"org_id":  workspace.OrgID(ctx),
+	}
+	conditions, err := todoFilterConditions(userID, &query.TodoFilters, args)
+	if err != nil {
+		return nil, err
+	}
+
+	pageKeys, err := newKeyset(query.Cursor, *query.Sort, *query.Order, todoSortKeys[*query.Sort], "t.id")
+	if err != nil {
+		return nil, err
+	}
+
+	var total *int
+	if *query.Count {
+		countStmt := "SELECT COUNT(*) FROM todos t WHERE " + strings.Join(conditions, " AND ")
+
+		var count int
+		if err := r.server.DB.Pool.QueryRow(ctx, countStmt, args).Scan(&count); err != nil {
+			return nil, fmt.Errorf("failed to get total count for todos user_id=%s: %w", userID, err)
+		}
+		total = &count
+	}
+
+	if condition := pageKeys.condition(args); condition != "" {
+		conditions = append(conditions, condition)
+	}
+
+	stmt += " WHERE " + strings.Join(conditions, " AND ")
+	stmt += pageKeys.orderBy()
+
+	args["limit"] = *query.Limit
+	if *query.Pagination == "cursor" {
+		// One extra row tells whether there's a next page
+		stmt += " LIMIT @limit + 1"
+	} else {
+		stmt += " LIMIT @limit OFFSET @offset"
+		args["offset"] = (*query.Page - 1) * (*query.Limit)
+	}
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get todos query for user_id=%s: %w", userID, err)
+	}
+
+	todos, err := pgx.CollectRows(rows, pgx.RowToStructByName[todo.PopulatedTodo])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:todos for user_id=%s: %w", userID, err)
+	}
+
+	if *query.Pagination == "cursor" {
+		page := keysetPage(pageKeys, todos, *query.Limit, func(t *todo.PopulatedTodo) (string, uuid.UUID) {
+			return todoSortValue(&t.Todo, *query.Sort), t.ID
+		})
+		page.Total = total
+		return page, nil
+	}
+
+	page := &model.CursorPaginatedResponse[todo.PopulatedTodo]{
+		Data:  todos,
+		Limit: *query.Limit,
+		Page:  query.Page,
+		Total: total,
+	}
+	if total != nil {
+		totalPages := (*total + *query.Limit - 1) / *query.Limit
+		page.TotalPages = &totalPages
+	}
+
+	return page, nil
+}
 
+// ExportTodos calls fn with every todo matching the query in turn, with its
+// category, comments and subtasks, reading rows as they arrive instead of
+// collecting them. fn must not use the connection, which stays busy until
+// the last row is read.
+func (r *TodoRepository) ExportTodos(ctx context.Context, userID string, query *todo.ExportTodosQuery,
+	fn func(t *todo.PopulatedTodo, subtasks []todo.PopulatedTodo) error,
+) error {
 	args := pgx.NamedArgs{
 		"user_id": userID,
+		"org_id":  workspace.OrgID(ctx),
+	}
+	conditions, err := todoFilterConditions(userID, &query.TodoFilters, args)
+	if err != nil {
+		return err
+	}
+
+	pageKeys, err := newKeyset(nil, *query.Sort, *query.Order, todoSortKeys[*query.Sort], "t.id")
+	if err != nil {
+		return err
+	}
+
+	// Subtasks are read as rows of their own, right after their parent, so
+	// they come with their category and comments too
+	stmt := `
+	WITH
+		exported AS (
+			SELECT
+				t.id,
+				ROW_NUMBER() OVER (` + strings.TrimSpace(pageKeys.orderBy()) + `) AS position
+			FROM
+				todos t
+			WHERE
+				` + strings.Join(conditions, " AND ") + `
+		)
+` + populatedTodoSelect([]string{todo.RelationComments}, "JOIN exported e ON e.id IN (t.id, t.parent_todo_id)") + `
+	WHERE
+		t.deleted_at IS NULL
+		AND t.id IN (SELECT todo_id FROM accessible_todos(@user_id, @org_id))
+	ORDER BY
+		e.position ASC,
+		t.id<>e.id ASC,
+		t.sort_order ASC,
+		t.created_at ASC
+`
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, args)
+	if err != nil {
+		return fmt.Errorf("failed to execute export todos query for user_id=%s: %w", userID, err)
+	}
+	defer rows.Close()
+
+	var (
+		current  *todo.PopulatedTodo
+		subtasks []todo.PopulatedTodo
+	)
+	for rows.Next() {
+		todoItem, err := pgx.RowToStructByName[todo.PopulatedTodo](rows)
+		if err != nil {
+			return fmt.Errorf("failed to scan row from table:todos for user_id=%s: %w", userID, err)
+		}
+
+		if current != nil && todoItem.ParentTodoID != nil && *todoItem.ParentTodoID == current.ID {
+			subtasks = append(subtasks, todoItem)
+			continue
+		}
+
+		if current != nil {
+			if err := fn(current, subtasks); err != nil {
+				return err
+			}
+		}
+		current, subtasks = &todoItem, nil
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read rows from table:todos for user_id=%s: %w", userID, err)
+	}
+
+	if current != nil {
+		return fn(current, subtasks)
+	}
+	return nil
+}
+
+// todoFilterConditions returns the conditions selecting the todos t the user
+// can access that match filters, adding their arguments to args, which must
+// already hold user_id and org_id.
+func todoFilterConditions(userID string, filters *todo.TodoFilters, args pgx.NamedArgs) ([]string, error) {
+	conditions := []string{
+		"t.id IN (SELECT todo_id FROM accessible_todos(@user_id, @org_id))",
+		"t.deleted_at IS NULL",
 	}
-	conditions := []string{"t.user_id = @user_id"}
 
-	if query.Status != nil {
+	if filters.Status != nil {
 		conditions = append(conditions, "t.status = @status")
-		args["status"] = *query.Status
+		args["status"] = *filters.Status
 	}
 
-	if query.Priority != nil {
+	if filters.Priority != nil {
 		conditions = append(conditions, "t.priority = @priority")
-		args["priority"] = *query.Priority
+		args["priority"] = *filters.Priority
 	}
 
-	if query.CategoryID != nil {
+	if filters.CategoryID != nil {
 		conditions = append(conditions, "t.category_id = @category_id")
-		args["category_id"] = *query.CategoryID
+		args["category_id"] = *filters.CategoryID
 	}
 
-	if query.ParentTodoID != nil {
+	if filters.ParentTodoID != nil {
 		conditions = append(conditions, "t.parent_todo_id = @parent_todo_id")
-		args["parent_todo_id"] = *query.ParentTodoID
+		args["parent_todo_id"] = *filters.ParentTodoID
 	} else {
 		// By default, only show root todos (no parent)
 		conditions = append(conditions, "t.parent_todo_id IS NULL")
 	}
 
-	if query.DueFrom != nil {
+	if filters.DueFrom != nil {
 		conditions = append(conditions, "t.due_date >= @due_from")
-		args["due_from"] = *query.DueFrom
+		args["due_from"] = *filters.DueFrom
 	}
 
-	if query.DueTo != nil {
+	if filters.DueTo != nil {
 		conditions = append(conditions, "t.due_date <= @due_to")
-		args["due_to"] = *query.DueTo
+		args["due_to"] = *filters.DueTo
+	}
+
+	if filters.HasDueDate != nil {
+		if *filters.HasDueDate {
+			conditions = append(conditions, "t.due_date IS NOT NULL")
+		} else {
+			conditions = append(conditions, "t.due_date IS NULL")
+		}
 	}
 
-	if query.Overdue != nil && *query.Overdue {
+	if filters.Overdue != nil && *filters.Overdue {
 		conditions = append(conditions, "t.due_date < NOW() AND t.status != 'completed'")
 	}
 
-	if query.Completed != nil {
-		if *query.Completed {
+	if filters.Completed != nil {
+		if *filters.Completed {
 			conditions = append(conditions, "t.status = 'completed'")
 		} else {
 			conditions = append(conditions, "t.status != 'completed'")
 		}
 	}
 
-	if query.Search != nil {
-		conditions = append(conditions, "(t.title ILIKE @search OR t.description ILIKE @search)")
-		args["search"] = "%" + *query.Search + "%"
+	if filters.Blocked != nil {
+		blockedCondition := `EXISTS (
+			SELECT 1
+			FROM todo_dependencies dep
+			JOIN todos blocker ON blocker.id=dep.blocking_todo_id
+			WHERE dep.blocked_todo_id=t.id
+				AND blocker.deleted_at IS NULL
+				AND blocker.status NOT IN ('completed', 'archived')
+		)`
+		if *filters.Blocked {
+			conditions = append(conditions, blockedCondition)
+		} else {
+			conditions = append(conditions, "NOT "+blockedCondition)
+		}
 	}
 
-	if len(conditions) > 0 {
-		stmt += " WHERE " + strings.Join(conditions, " AND ")
+	if filters.Assignee != nil {
+		conditions = append(conditions, "t.assignee_id = @assignee_id")
+		if *filters.Assignee == "me" {
+			args["assignee_id"] = userID
+		} else {
+			args["assignee_id"] = *filters.Assignee
+		}
 	}
 
-	countStmt := "SELECT COUNT(*) FROM todos t"
-	if len(conditions) > 0 {
-		countStmt += " WHERE " + strings.Join(conditions, " AND ")
+	if filters.Watching != nil {
+		watchingCondition := "EXISTS (SELECT 1 FROM todo_watchers w WHERE w.todo_id=t.id AND w.user_id=@user_id)"
+		if *filters.Watching {
+			conditions = append(conditions, watchingCondition)
+		} else {
+			conditions = append(conditions, "NOT "+watchingCondition)
+		}
 	}
 
-	var total int
-	err := r.server.DB.Pool.QueryRow(ctx, countStmt, args).Scan(&total)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get total count for todos user_id=%s: %w", userID, err)
+	if len(filters.Tags) > 0 {
+		tags := []string{}
+		for _, name := range filters.Tags {
+			if !slices.Contains(tags, name) {
+				tags = append(tags, name)
+			}
+		}
+		args["tags"] = tags
+
+		taggedCount := `(
+			SELECT COUNT(*)
+			FROM todo_tags tt
+			JOIN tags tg ON tg.id=tt.tag_id
+			WHERE tt.todo_id=t.id
+				AND tg.name=ANY(@tags::TEXT[])
+		)`
+		if filters.TagMatch != nil && *filters.TagMatch == "all" {
+			conditions = append(conditions, taggedCount+" = @tag_count")
+			args["tag_count"] = len(tags)
+		} else {
+			conditions = append(conditions, taggedCount+" > 0")
+		}
 	}
 
-	stmt += " GROUP BY t.id, c.id"
+	if filters.Search != nil {
+		conditions = append(conditions, "t.search_vector @@ websearch_to_tsquery('english', @search)")
+		args["search"] = *filters.Search
+	}
 
-	if query.Sort != nil {
-		stmt += " ORDER BY t." + *query.Sort
-		if query.Order != nil && *query.Order == "desc" {
-			stmt += " DESC"
-		} else {
-			stmt += " ASC"
+	if filters.Q != nil {
+		node, err := filterql.Parse(*filters.Q)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse filter query q=%q: %w", *filters.Q, err)
 		}
-	} else {
-		stmt += " ORDER BY t.created_at DESC"
+
+		location := time.UTC
+		if filters.Timezone != nil {
+			if loc, err := time.LoadLocation(*filters.Timezone); err == nil {
+				location = loc
+			}
+		}
+
+		conditions = append(conditions, filterql.Compile(node, time.Now().In(location), args))
 	}
 
-	stmt += " LIMIT @limit OFFSET @offset"
-	args["limit"] = *query.Limit
-	args["offset"] = (*query.Page - 1) * (*query.Limit)
+	return conditions, nil
+}
 
-	rows, err := r.server.DB.Pool.Query(ctx, stmt, args)
+// todoSortKeys are the columns GetTodos sorts by. Undated todos sort last
+// ascending and first descending, the way Postgres orders NULLs.
+var todoSortKeys = map[string]sortKey{
+	"created_at": {expr: "t.created_at", sqlType: "timestamptz"},
+	"updated_at": {expr: "t.updated_at", sqlType: "timestamptz"},
+	"title":      {expr: "t.title", sqlType: "text"},
+	"priority":   {expr: "t.priority", sqlType: "text"},
+	"due_date":   {expr: "COALESCE(t.due_date, 'infinity'::timestamptz)", sqlType: "timestamptz"},
+	"status":     {expr: "t.status", sqlType: "text"},
+	"sort_order": {expr: "t.sort_order", sqlType: "bigint"},
+}
+
+// todoSortValue returns a todo's value for a todoSortKeys column, formatted
+// for a cursor.
+func todoSortValue(t *todo.Todo, sort string) string {
+	switch sort {
+	case "updated_at":
+		return t.UpdatedAt.Format(time.RFC3339Nano)
+	case "title":
+		return t.Title
+	case "priority":
+		return string(t.Priority)
+	case "due_date":
+		if t.DueDate == nil {
+			return "infinity"
+		}
+		return t.DueDate.Format(time.RFC3339Nano)
+	case "status":
+		return string(t.Status)
+	case "sort_order":
+		return strconv.FormatInt(t.SortOrder, 10)
+	default:
+		return t.CreatedAt.Format(time.RFC3339Nano)
+	}
+}
+
+func (r *TodoRepository) UpdateTodo(ctx context.Context, userID string, payload *todo.UpdateTodoPayload) (*todo.Todo, error) {
+	tx, err := r.server.DB.Pool.Begin(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute get todos query for user_id=%s: %w", userID, err)
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
 	}
+	defer tx.Rollback(ctx)
 
-	todos, err := pgx.CollectRows(rows, pgx.RowToStructByName[todo.PopulatedTodo])
+	current, err := r.getTodoForUpdate(ctx, tx, userID, payload.ID)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return &model.PaginatedResponse[todo.PopulatedTodo]{
-				Data:       []todo.PopulatedTodo{},
-				Page:       *query.Page,
-				Limit:      *query.Limit,
-				Total:      0,
-				TotalPages: 0,
-			}, nil
-		}
-		return nil, fmt.Errorf("failed to collect rows from table:todos for user_id=%s: %w", userID, err)
+		return nil, err
 	}
 
-	return &model.PaginatedResponse[todo.PopulatedTodo]{
-		Data:       todos,
-		Page:       *query.Page,
-		Limit:      *query.Limit,
-		Total:      total,
-		TotalPages: (total + *query.Limit - 1) / *query.Limit,
-	}, nil
-}
+	// Checked under the row lock, so a concurrent writer can't slip in
+	// between the check and the update
+	if err := etag.Check(payload.IfMatch, current); err != nil {
+		return nil, err
+	}
+
+	if err := resolveMetadataPatch(current, payload); err != nil {
+		return nil, err
+	}
 
-func (r *TodoRepository) UpdateTodo(ctx context.Context, userID string, payload *todo.UpdateTodoPayload) (*todo.Todo, error) {
 	stmt := "UPDATE todos SET "
 	args := pgx.NamedArgs{
 		"todo_id": payload.ID,
-		"user_id": userID,
 	}
 	setClauses := []string{}
 
@@ -363,6 +736,8 @@ func (r *TodoRepository) UpdateTodo(ctx context.Context, userID string, payload
 	if payload.Description != nil {
 		setClauses = append(setClauses, "description = @description")
 		args["description"] = *payload.Description
+	} else if payload.Clears(todo.FieldDescription) {
+		setClauses = append(setClauses, "description = NULL")
 	}
 
 	if payload.Status != nil {
@@ -386,21 +761,44 @@ func (r *TodoRepository) UpdateTodo(ctx context.Context, userID string, payload
 	if payload.DueDate != nil {
 		setClauses = append(setClauses, "due_date = @due_date")
 		args["due_date"] = *payload.DueDate
+	} else if payload.Clears(todo.FieldDueDate) {
+		setClauses = append(setClauses, "due_date = NULL")
 	}
 
 	if payload.ParentTodoID != nil {
 		setClauses = append(setClauses, "parent_todo_id = @parent_todo_id")
 		args["parent_todo_id"] = *payload.ParentTodoID
+	} else if payload.Clears(todo.FieldParentTodoID) {
+		setClauses = append(setClauses, "parent_todo_id = NULL")
 	}
 
 	if payload.CategoryID != nil {
 		setClauses = append(setClauses, "category_id = @category_id")
 		args["category_id"] = *payload.CategoryID
+	} else if payload.Clears(todo.FieldCategoryID) {
+		setClauses = append(setClauses, "category_id = NULL")
 	}
 
 	if payload.Metadata != nil {
 		setClauses = append(setClauses, "metadata = @metadata")
 		args["metadata"] = payload.Metadata
+	} else if payload.Clears(todo.FieldMetadata) {
+		setClauses = append(setClauses, "metadata = NULL")
+	}
+
+	setClauses = append(setClauses, assigneeSetClauses(payload, args)...)
+
+	recurrenceClauses, err := recurrenceSetClauses(current, payload, args)
+	if err != nil {
+		return nil, err
+	}
+	setClauses = append(setClauses, recurrenceClauses...)
+
+	// Tags live in their own table, but changing them still counts as
+	// changing the todo, so its version moves on
+	setsTags := payload.Tags != nil || payload.Clears(todo.FieldTags)
+	if setsTags {
+		setClauses = append(setClauses, "updated_at = CURRENT_TIMESTAMP")
 	}
 
 	if len(setClauses) == 0 {
@@ -408,9 +806,9 @@ func (r *TodoRepository) UpdateTodo(ctx context.Context, userID string, payload
 	}
 
 	stmt += strings.Join(setClauses, ", ")
-	stmt += " WHERE id = @todo_id AND user_id = @user_id RETURNING *"
+	stmt += " WHERE id = @todo_id RETURNING *"
 
-	rows, err := r.server.DB.Pool.Query(ctx, stmt, args)
+	rows, err := tx.Query(ctx, stmt, args)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute query: %w", err)
 	}
@@ -420,108 +818,882 @@ func (r *TodoRepository) UpdateTodo(ctx context.Context, userID string, payload
 		return nil, fmt.Errorf("failed to collect row from table:todos: %w", err)
 	}
 
-	return &updatedTodo, nil
-}
+	if setsTags {
+		if err := setTodoTags(ctx, tx, &updatedTodo, payload.Tags); err != nil {
+			return nil, err
+		}
+	}
 
-func (r *TodoRepository) DeleteTodo(ctx context.Context, userID string, todoID uuid.UUID) error {
-	stmt := `
-		DELETE FROM todos
-		WHERE
-			id=@todo_id
-			AND user_id=@user_id
-	`
+	if current.IsRecurring() && payload.Scope != nil && *payload.Scope == todo.RecurrenceScopeFuture {
+		if err := r.updateFutureOccurrences(ctx, tx, current, payload); err != nil {
+			return nil, err
+		}
+	}
 
-	result, err := r.server.DB.Pool.Exec(ctx, stmt, pgx.NamedArgs{
-		"todo_id": todoID,
-		"user_id": userID,
-	})
-	if err != nil {
-		return fmt.Errorf("failed to execute query: %w", err)
+	// Completing an occurrence schedules the next one in its series
+	if payload.Status != nil && *payload.Status == todo.StatusCompleted && updatedTodo.IsRecurring() {
+		if _, err := r.createNextOccurrence(ctx, tx, &updatedTodo); err != nil {
+			return nil, err
+		}
 	}
 
-	if result.RowsAffected() == 0 {
-		code := "TODO_NOT_FOUND"
-		return errs.NewNotFoundError("todo not found", false, &code)
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
-	return nil
+	return &updatedTodo, nil
 }
 
-func (r *TodoRepository) GetTodoStats(ctx context.Context, userID string) (*todo.TodoStats, error) {
+func (r *TodoRepository) getTodoForUpdate(ctx context.Context, q querier, userID string, todoID uuid.UUID) (*todo.Todo, error) {
 	stmt := `
 		SELECT
-			COUNT(*) AS total,
-			COUNT(
-				CASE
-					WHEN status='draft' THEN 1
-				END
-			) AS draft,
-			COUNT(
-				CASE
-					WHEN status='active' THEN 1
-				END
-			) AS active,
-			COUNT(
-				CASE
-					WHEN status='completed' THEN 1
-				END
-			) AS completed,
-			COUNT(
-				CASE
-					WHEN status='archived' THEN 1
-				END
-			) AS archived,
-			COUNT(
-				CASE
-					WHEN due_date<NOW()
-					AND status!='completed' THEN 1
-				END
-			) AS overdue
+			*
 		FROM
 			todos
 		WHERE
-			user_id=@user_id
+			id=@id
+			AND share_role_rank(todo_role(id, @user_id, @org_id)) >= share_role_rank('editor')
+			AND deleted_at IS NULL
+		FOR UPDATE
 	`
 
-	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{
+	rows, err := q.Query(ctx, stmt, pgx.NamedArgs{
+		"id":      todoID,
 		"user_id": userID,
+		"org_id":  workspace.OrgID(ctx),
 	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute query: %w", err)
+		return nil, fmt.Errorf("failed to lock todo for todo_id=%s user_id=%s: %w", todoID.String(), userID, err)
 	}
 
-	stats, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[todo.TodoStats])
+	todoItem, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[todo.Todo])
 	if err != nil {
-		return nil, fmt.Errorf("failed to collect row from table:todos: %w", err)
+		return nil, fmt.Errorf("failed to collect row from table:todos for todo_id=%s user_id=%s: %w", todoID.String(), userID, err)
 	}
 
-	return &stats, nil
+	return &todoItem, nil
 }
 
-func (r *TodoRepository) GetTodoAttachment(
-	ctx context.Context,
-	todoID uuid.UUID,
-	attachmentID uuid.UUID,
-) (*todo.TodoAttachment, error) {
-	stmt := `
-		SELECT
-			*
-		FROM
-			todo_attachments
-		WHERE
-			todo_id = @todo_id
-			AND id = @attachment_id
-	`
+// assigneeSetClauses returns the SET clause for a new assignee, where an
+// empty ID clears the assignment.
+func assigneeSetClauses(payload *todo.UpdateTodoPayload, args pgx.NamedArgs) []string {
+	if payload.AssigneeID == nil {
+		return nil
+	}
 
-	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{
-		"todo_id":       todoID,
-		"attachment_id": attachmentID,
-	})
-	if err != nil {
-		return nil, fmt.Errorf("failed to get todo attachment: %w", err)
+	if *payload.AssigneeID == "" {
+		return []string{"assignee_id = NULL"}
 	}
 
-	attachment, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[todo.TodoAttachment])
+	args["assignee_id"] = *payload.AssigneeID
+	return []string{"assignee_id = @assignee_id"}
+}
+
+// recurrenceSetClauses returns the SET clauses that keep the recurrence
+// columns consistent with the update: starting or stopping a series, and
+// moving the scheduled slot when the whole series is rescheduled.
+func recurrenceSetClauses(current *todo.Todo, payload *todo.UpdateTodoPayload, args pgx.NamedArgs) ([]string, error) {
+	var setClauses []string
+	future := payload.Scope != nil && *payload.Scope == todo.RecurrenceScopeFuture
+
+	if payload.Recurrence != nil {
+		if *payload.Recurrence == "" {
+			setClauses = append(setClauses, "recurrence = NULL")
+			return setClauses, nil
+		}
+
+		rule, err := rrule.Parse(*payload.Recurrence)
+		if err != nil {
+			return nil, errs.NewBadRequestError("invalid recurrence rule: "+err.Error(), false, nil, nil, nil)
+		}
+		setClauses = append(setClauses, "recurrence = @recurrence")
+		args["recurrence"] = rule.String()
+
+		if current.RecurrenceSeriesID == nil {
+			dueDate := current.DueDate
+			if payload.DueDate != nil {
+				dueDate = payload.DueDate
+			}
+			if dueDate == nil {
+				return nil, errs.NewBadRequestError("dueDate is required for recurring todos", false, nil, nil, nil)
+			}
+
+			setClauses = append(setClauses,
+				"recurrence_series_id = @recurrence_series_id",
+				"recurrence_index = 0",
+				"recurrence_date = @recurrence_date",
+			)
+			args["recurrence_series_id"] = uuid.New()
+			args["recurrence_date"] = *dueDate
+			return setClauses, nil
+		}
+	}
+
+	// Moving a single occurrence keeps its slot; moving the series moves it too
+	if payload.DueDate != nil && current.RecurrenceSeriesID != nil && future {
+		setClauses = append(setClauses, "recurrence_date = @due_date")
+	}
+
+	return setClauses, nil
+}
+
+// resolveMetadataPatch merges a merge patch's metadata into the todo's
+// current metadata, so the rest of the update can treat it as a replacement.
+// It runs under the row lock, so concurrent patches to different keys both
+// stick.
+func resolveMetadataPatch(current *todo.Todo, payload *todo.UpdateTodoPayload) error {
+	if payload.MetadataPatch == nil {
+		return nil
+	}
+
+	var target []byte
+	if current.Metadata != nil {
+		var err error
+		if target, err = json.Marshal(current.Metadata); err != nil {
+			return fmt.Errorf("failed to encode metadata for todo_id=%s: %w", current.ID.String(), err)
+		}
+	}
+
+	merged, err := mergepatch.Apply(target, payload.MetadataPatch)
+	if err != nil {
+		return fmt.Errorf("failed to merge metadata for todo_id=%s: %w", current.ID.String(), err)
+	}
+
+	var metadata todo.Metadata
+	if err := json.Unmarshal(merged, &metadata); err != nil {
+		return errs.NewBadRequestError("Validation failed", true, nil, []errs.FieldError{
+			{Field: todo.FieldMetadata, Error: "is not valid after applying the patch"},
+		}, nil)
+	}
+
+	payload.Metadata = &metadata
+	payload.MetadataPatch = nil
+	return nil
+}
+
+// updateFutureOccurrences applies an update with scope=future to the
+// already generated, not yet completed occurrences after current. Changing
+// the rule drops them instead so they are regenerated from the new rule.
+func (r *TodoRepository) updateFutureOccurrences(ctx context.Context, q querier,
+	current *todo.Todo, payload *todo.UpdateTodoPayload,
+) error {
+	args := pgx.NamedArgs{
+		"user_id":              current.UserID,
+		"recurrence_series_id": *current.RecurrenceSeriesID,
+		"recurrence_index":     *current.RecurrenceIndex,
+	}
+
+	if payload.Recurrence != nil {
+		stmt := `
+			DELETE FROM todos
+			WHERE
+				user_id = @user_id
+				AND recurrence_series_id = @recurrence_series_id
+				AND recurrence_index > @recurrence_index
+				AND status != 'completed'
+		`
+		if _, err := q.Exec(ctx, stmt, args); err != nil {
+			return fmt.Errorf("failed to delete future occurrences for series_id=%s: %w", current.RecurrenceSeriesID.String(), err)
+		}
+		return nil
+	}
+
+	// The occurrences take on the tags just given to current
+	if payload.Tags != nil || payload.Clears(todo.FieldTags) {
+		args["current_id"] = current.ID
+		future := `
+			SELECT
+				id
+			FROM
+				todos
+			WHERE
+				user_id = @user_id
+				AND recurrence_series_id = @recurrence_series_id
+				AND recurrence_index > @recurrence_index
+				AND status != 'completed'
+				AND deleted_at IS NULL`
+
+		stmt := `
+			DELETE FROM todo_tags
+			WHERE
+				todo_id IN (` + future + `
+				)
+		`
+		if _, err := q.Exec(ctx, stmt, args); err != nil {
+			return fmt.Errorf("failed to clear tags of future occurrences for series_id=%s: %w", current.RecurrenceSeriesID.String(), err)
+		}
+
+		stmt = `
+			INSERT INTO
+				todo_tags (todo_id, tag_id)
+			SELECT
+				future.id,
+				tt.tag_id
+			FROM
+				(` + future + `
+				) future
+				CROSS JOIN todo_tags tt
+			WHERE
+				tt.todo_id = @current_id
+		`
+		if _, err := q.Exec(ctx, stmt, args); err != nil {
+			return fmt.Errorf("failed to tag future occurrences for series_id=%s: %w", current.RecurrenceSeriesID.String(), err)
+		}
+	}
+
+	setClauses := []string{}
+
+	if payload.Title != nil {
+		setClauses = append(setClauses, "title = @title")
+		args["title"] = *payload.Title
+	}
+
+	if payload.Description != nil {
+		setClauses = append(setClauses, "description = @description")
+		args["description"] = *payload.Description
+	} else if payload.Clears(todo.FieldDescription) {
+		setClauses = append(setClauses, "description = NULL")
+	}
+
+	if payload.Priority != nil {
+		setClauses = append(setClauses, "priority = @priority")
+		args["priority"] = *payload.Priority
+	}
+
+	if payload.CategoryID != nil {
+		setClauses = append(setClauses, "category_id = @category_id")
+		args["category_id"] = *payload.CategoryID
+	} else if payload.Clears(todo.FieldCategoryID) {
+		setClauses = append(setClauses, "category_id = NULL")
+	}
+
+	if payload.Metadata != nil {
+		setClauses = append(setClauses, "metadata = @metadata")
+		args["metadata"] = payload.Metadata
+	} else if payload.Clears(todo.FieldMetadata) {
+		setClauses = append(setClauses, "metadata = NULL")
+	}
+
+	setClauses = append(setClauses, assigneeSetClauses(payload, args)...)
+
+	if payload.DueDate != nil && current.RecurrenceDate != nil {
+		setClauses = append(setClauses,
+			"due_date = due_date + @shift::INTERVAL",
+			"recurrence_date = recurrence_date + @shift::INTERVAL",
+		)
+		args["shift"] = payload.DueDate.Sub(*current.RecurrenceDate)
+	}
+
+	if len(setClauses) == 0 {
+		return nil
+	}
+
+	stmt := "UPDATE todos SET " + strings.Join(setClauses, ", ") + `
+		WHERE
+			user_id = @user_id
+			AND recurrence_series_id = @recurrence_series_id
+			AND recurrence_index > @recurrence_index
+			AND status != 'completed'
+			AND deleted_at IS NULL
+	`
+
+	if _, err := q.Exec(ctx, stmt, args); err != nil {
+		return fmt.Errorf("failed to update future occurrences for series_id=%s: %w", current.RecurrenceSeriesID.String(), err)
+	}
+
+	return nil
+}
+
+// CreateNextOccurrence generates the occurrence that follows prev in its
+// series. It returns nil when the series has ended or the occurrence exists.
+func (r *TodoRepository) CreateNextOccurrence(ctx context.Context, prev *todo.Todo) (*todo.Todo, error) {
+	return r.createNextOccurrence(ctx, r.server.DB.Pool, prev)
+}
+
+func (r *TodoRepository) createNextOccurrence(ctx context.Context, q querier, prev *todo.Todo) (*todo.Todo, error) {
+	if !prev.IsRecurring() || prev.RecurrenceIndex == nil || prev.RecurrenceDate == nil {
+		return nil, nil
+	}
+
+	rule, err := rrule.Parse(*prev.Recurrence)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse recurrence rule for todo_id=%s: %w", prev.ID.String(), err)
+	}
+
+	nextDate, ok := rule.Next(*prev.RecurrenceDate, *prev.RecurrenceIndex)
+	if !ok {
+		return nil, nil
+	}
+
+	stmt := `
+		INSERT INTO
+			todos (
+				user_id,
+				org_id,
+				title,
+				description,
+				status,
+				priority,
+				due_date,
+				category_id,
+				metadata,
+				recurrence,
+				recurrence_series_id,
+				recurrence_index,
+				recurrence_date,
+				assignee_id
+			)
+		SELECT
+			user_id,
+			org_id,
+			title,
+			description,
+			'active',
+			priority,
+			@recurrence_date::TIMESTAMPTZ,
+			category_id,
+			metadata,
+			recurrence,
+			recurrence_series_id,
+			@recurrence_index::INTEGER,
+			@recurrence_date::TIMESTAMPTZ,
+			assignee_id
+		FROM
+			todos
+		WHERE
+			id = @id
+		ON CONFLICT (recurrence_series_id, recurrence_index) DO NOTHING
+		RETURNING
+			*
+	`
+
+	rows, err := q.Query(ctx, stmt, pgx.NamedArgs{
+		"id":               prev.ID,
+		"recurrence_index": *prev.RecurrenceIndex + 1,
+		"recurrence_date":  nextDate,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create next occurrence for todo_id=%s: %w", prev.ID.String(), err)
+	}
+
+	next, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[todo.Todo])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to collect row from table:todos for todo_id=%s: %w", prev.ID.String(), err)
+	}
+
+	// Whoever watched the series keeps watching it
+	watchStmt := `
+		INSERT INTO
+			todo_watchers (todo_id, user_id)
+		SELECT
+			@next_id,
+			user_id
+		FROM
+			todo_watchers
+		WHERE
+			todo_id = @prev_id
+	`
+	if _, err := q.Exec(ctx, watchStmt, pgx.NamedArgs{"next_id": next.ID, "prev_id": prev.ID}); err != nil {
+		return nil, fmt.Errorf("failed to copy watchers to todo_id=%s: %w", next.ID.String(), err)
+	}
+
+	tagStmt := `
+		INSERT INTO
+			todo_tags (todo_id, tag_id)
+		SELECT
+			@next_id,
+			tag_id
+		FROM
+			todo_tags
+		WHERE
+			todo_id = @prev_id
+	`
+	if _, err := q.Exec(ctx, tagStmt, pgx.NamedArgs{"next_id": next.ID, "prev_id": prev.ID}); err != nil {
+		return nil, fmt.Errorf("failed to copy tags to todo_id=%s: %w", next.ID.String(), err)
+	}
+
+	return &next, nil
+}
+
+// todoRank is the slice of a todo needed to place it among its siblings.
+type todoRank struct {
+	ID        uuid.UUID `db:"id"`
+	SortOrder int64     `db:"sort_order"`
+}
+
+// MoveTodo rewrites the todo's sort_order so it lands at the requested spot
+// among its siblings. Usually only the moved row changes; the siblings are
+// renumbered only once two neighbours have no gap left between them.
+func (r *TodoRepository) MoveTodo(ctx context.Context, userID string, payload *todo.MoveTodoPayload) (*todo.Todo, error) {
+	tx, err := r.server.DB.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	current, err := r.getTodoForUpdate(ctx, tx, userID, payload.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	siblingsStmt := `
+		SELECT
+			id,
+			sort_order
+		FROM
+			todos
+		WHERE
+			org_id IS NOT DISTINCT FROM @org_id::TEXT
+			AND (
+				@org_id::TEXT IS NOT NULL
+				OR user_id = @user_id
+			)
+			AND id != @todo_id
+			AND deleted_at IS NULL
+			AND parent_todo_id IS NOT DISTINCT FROM @parent_todo_id::UUID
+			AND (
+				@parent_todo_id::UUID IS NOT NULL
+				OR category_id IS NOT DISTINCT FROM @category_id::UUID
+			)
+		ORDER BY
+			sort_order ASC,
+			created_at ASC
+		FOR UPDATE
+	`
+
+	rows, err := tx.Query(ctx, siblingsStmt, pgx.NamedArgs{
+		"user_id":        current.UserID,
+		"org_id":         current.OrgID,
+		"todo_id":        current.ID,
+		"parent_todo_id": current.ParentTodoID,
+		"category_id":    current.CategoryID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get siblings for todo_id=%s: %w", current.ID.String(), err)
+	}
+
+	siblings, err := pgx.CollectRows(rows, pgx.RowToStructByName[todoRank])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:todos: %w", err)
+	}
+
+	index, err := moveIndex(siblings, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	sortOrder, ok := rankAt(siblings, index)
+	if !ok {
+		sortOrder, err = r.renumberSiblings(ctx, tx, siblings, index)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	stmt := `
+		UPDATE todos
+		SET
+			sort_order = @sort_order
+		WHERE
+			id = @todo_id
+			AND user_id = @user_id
+		RETURNING
+			*
+	`
+
+	rows, err = tx.Query(ctx, stmt, pgx.NamedArgs{
+		"sort_order": sortOrder,
+		"todo_id":    current.ID,
+		"user_id":    current.UserID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute move todo query for todo_id=%s: %w", current.ID.String(), err)
+	}
+
+	movedTodo, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[todo.Todo])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect row from table:todos: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return &movedTodo, nil
+}
+
+// moveIndex resolves the payload to the index the todo should occupy in
+// siblings, which excludes the todo itself.
+func moveIndex(siblings []todoRank, payload *todo.MoveTodoPayload) (int, error) {
+	if payload.Position != nil {
+		return min(*payload.Position, len(siblings)), nil
+	}
+
+	targetID := payload.BeforeID
+	offset := 0
+	if payload.AfterID != nil {
+		targetID = payload.AfterID
+		offset = 1
+	}
+
+	for i, sibling := range siblings {
+		if sibling.ID == *targetID {
+			return i + offset, nil
+		}
+	}
+
+	code := "TODO_NOT_SIBLING"
+	return 0, errs.NewBadRequestError("Todos can only be moved relative to their siblings", false, &code, nil, nil)
+}
+
+// rankAt returns a sort_order between the neighbours around index, or false
+// when they are adjacent and the list has to be renumbered.
+func rankAt(siblings []todoRank, index int) (int64, bool) {
+	switch {
+	case len(siblings) == 0:
+		return todo.SortOrderGap, true
+	case index == 0:
+		return siblings[0].SortOrder - todo.SortOrderGap, true
+	case index == len(siblings):
+		return siblings[len(siblings)-1].SortOrder + todo.SortOrderGap, true
+	}
+
+	prev, next := siblings[index-1].SortOrder, siblings[index].SortOrder
+	if next-prev < 2 {
+		return 0, false
+	}
+
+	return prev + (next-prev)/2, true
+}
+
+// renumberSiblings spaces the siblings SortOrderGap apart, leaving a slot at
+// index, and returns the sort_order for that slot.
+func (r *TodoRepository) renumberSiblings(ctx context.Context, q querier,
+	siblings []todoRank, index int,
+) (int64, error) {
+	ids := make([]uuid.UUID, len(siblings))
+	sortOrders := make([]int64, len(siblings))
+	for i, sibling := range siblings {
+		slot := int64(i + 1)
+		if i >= index {
+			slot++
+		}
+		ids[i] = sibling.ID
+		sortOrders[i] = slot * todo.SortOrderGap
+	}
+
+	stmt := `
+		UPDATE todos t
+		SET
+			sort_order = v.sort_order
+		FROM
+			UNNEST(@ids::UUID[], @sort_orders::BIGINT[]) AS v (id, sort_order)
+		WHERE
+			t.id = v.id
+	`
+
+	_, err := q.Exec(ctx, stmt, pgx.NamedArgs{
+		"ids":         ids,
+		"sort_orders": sortOrders,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to renumber sibling todos: %w", err)
+	}
+
+	return int64(index+1) * todo.SortOrderGap, nil
+}
+
+// DeleteTodo moves the todo and its subtasks to the trash. They all share one
+// deleted_at so RestoreTodo can bring back exactly the subtasks trashed with it.
+// The todo is locked while ifMatch is checked, so it fails with
+// etag.PreconditionFailedError when the todo was written in the meantime.
+func (r *TodoRepository) DeleteTodo(ctx context.Context, userID string, todoID uuid.UUID, ifMatch *string) error {
+	tx, err := r.server.DB.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	current, err := r.getTodoForUpdate(ctx, tx, userID, todoID)
+	if err != nil {
+		return err
+	}
+
+	if err := etag.Check(ifMatch, current); err != nil {
+		return err
+	}
+
+	args := pgx.NamedArgs{
+		"todo_id": todoID,
+	}
+	scope := workspaceScope(ctx, "", userID, args)
+
+	stmt := `
+		UPDATE todos
+		SET
+			deleted_at=NOW()
+		WHERE
+			` + scope + `
+			AND deleted_at IS NULL
+			AND (
+				id=@todo_id
+				OR parent_todo_id IN (
+					SELECT
+						id
+					FROM
+						todos
+					WHERE
+						id=@todo_id
+						AND ` + scope + `
+						AND deleted_at IS NULL
+				)
+			)
+	`
+
+	result, err := tx.Exec(ctx, stmt, args)
+	if err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		code := "TODO_NOT_FOUND"
+		return errs.NewNotFoundError("todo not found", false, &code)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *TodoRepository) GetTrashedTodos(ctx context.Context, userID string,
+	query *todo.GetTrashedTodosQuery,
+) (*model.PaginatedResponse[todo.Todo], error) {
+	args := pgx.NamedArgs{
+		"limit":  *query.Limit,
+		"offset": (*query.Page - 1) * (*query.Limit),
+	}
+
+	// Subtasks trashed along with their parent are restored through it, so
+	// they aren't listed separately
+	conditions := `
+			` + workspaceScope(ctx, "t", userID, args) + `
+			AND t.deleted_at IS NOT NULL
+			AND NOT EXISTS (
+				SELECT
+					1
+				FROM
+					todos parent
+				WHERE
+					parent.id=t.parent_todo_id
+					AND parent.deleted_at IS NOT NULL
+			)
+	`
+
+	stmt := `
+		SELECT
+			t.*
+		FROM
+			todos t
+		WHERE` + conditions + `
+		ORDER BY
+			t.deleted_at DESC
+		LIMIT
+			@limit
+		OFFSET
+			@offset
+	`
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get trashed todos query for user_id=%s: %w", userID, err)
+	}
+
+	todos, err := pgx.CollectRows(rows, pgx.RowToStructByName[todo.Todo])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:todos for user_id=%s: %w", userID, err)
+	}
+
+	var total int
+	err = r.server.DB.Pool.QueryRow(ctx, "SELECT COUNT(*) FROM todos t WHERE"+conditions, args).Scan(&total)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get total count of trashed todos for user_id=%s: %w", userID, err)
+	}
+
+	return &model.PaginatedResponse[todo.Todo]{
+		Data:       todos,
+		Page:       *query.Page,
+		Limit:      *query.Limit,
+		Total:      total,
+		TotalPages: (total + *query.Limit - 1) / *query.Limit,
+	}, nil
+}
+
+func (r *TodoRepository) GetTrashedTodo(ctx context.Context, userID string, todoID uuid.UUID) (*todo.Todo, error) {
+	args := pgx.NamedArgs{
+		"id": todoID,
+	}
+
+	stmt := `
+		SELECT
+			*
+		FROM
+			todos
+		WHERE
+			id=@id
+			AND ` + workspaceScope(ctx, "", userID, args) + `
+			AND deleted_at IS NOT NULL
+	`
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trashed todo for todo_id=%s user_id=%s: %w", todoID.String(), userID, err)
+	}
+
+	todoItem, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[todo.Todo])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect row from table:todos for todo_id=%s user_id=%s: %w", todoID.String(), userID, err)
+	}
+
+	return &todoItem, nil
+}
+
+// RestoreTodo takes a trashed todo out of the trash together with the
+// subtasks that were trashed with it.
+func (r *TodoRepository) RestoreTodo(ctx context.Context, userID string, trashed *todo.Todo) (*todo.Todo, error) {
+	tx, err := r.server.DB.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	args := pgx.NamedArgs{
+		"todo_id":    trashed.ID,
+		"deleted_at": trashed.DeletedAt,
+	}
+	scope := workspaceScope(ctx, "", userID, args)
+
+	_, err = tx.Exec(ctx, `
+		UPDATE todos
+		SET
+			deleted_at=NULL
+		WHERE
+			parent_todo_id=@todo_id
+			AND `+scope+`
+			AND deleted_at=@deleted_at
+	`, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore subtasks for todo_id=%s: %w", trashed.ID.String(), err)
+	}
+
+	rows, err := tx.Query(ctx, `
+		UPDATE todos
+		SET
+			deleted_at=NULL
+		WHERE
+			id=@todo_id
+			AND `+scope+`
+			AND deleted_at IS NOT NULL
+		RETURNING
+			*
+	`, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute restore todo query for todo_id=%s: %w", trashed.ID.String(), err)
+	}
+
+	restoredTodo, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[todo.Todo])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect row from table:todos for todo_id=%s: %w", trashed.ID.String(), err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return &restoredTodo, nil
+}
+
+func (r *TodoRepository) GetTodoStats(ctx context.Context, userID string) (*todo.TodoStats, error) {
+	args := pgx.NamedArgs{}
+	scope := workspaceScope(ctx, "", userID, args)
+
+	stmt := `
+		SELECT
+			COUNT(*) AS total,
+			COUNT(
+				CASE
+					WHEN status='draft' THEN 1
+				END
+			) AS draft,
+			COUNT(
+				CASE
+					WHEN status='active' THEN 1
+				END
+			) AS active,
+			COUNT(
+				CASE
+					WHEN status='completed' THEN 1
+				END
+			) AS completed,
+			COUNT(
+				CASE
+					WHEN status='archived' THEN 1
+				END
+			) AS archived,
+			COUNT(
+				CASE
+					WHEN due_date<NOW()
+					AND status!='completed' THEN 1
+				END
+			) AS overdue
+		FROM
+			todos
+		WHERE
+			` + scope + `
+			AND deleted_at IS NULL
+	`
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	stats, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[todo.TodoStats])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect row from table:todos: %w", err)
+	}
+
+	return &stats, nil
+}
+
+func (r *TodoRepository) GetTodoAttachment(
+	ctx context.Context,
+	todoID uuid.UUID,
+	attachmentID uuid.UUID,
+) (*todo.TodoAttachment, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			todo_attachments
+		WHERE
+			todo_id = @todo_id
+			AND id = @attachment_id
+	`
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{
+		"todo_id":       todoID,
+		"attachment_id": attachmentID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get todo attachment: %w", err)
+	}
+
+	attachment, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[todo.TodoAttachment])
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			code := "ATTACHMENT_NOT_FOUND"
@@ -604,46 +1776,303 @@ func (r *TodoRepository) UploadTodoAttachment(
 	mimeType string,
 ) (*todo.TodoAttachment, error) {
 	stmt := `
-		INSERT INTO
-			todo_attachments (
-				todo_id,
-				name,
-				uploaded_by,
-				download_key,
-				file_size,
-				mime_type
-			)
-		VALUES
-			(
-				@todo_id,
-				@name,
-				@uploaded_by,
-				@download_key,
-				@file_size,
-				@mime_type
-			)
-		RETURNING
-			*
+		INSERT INTO
+			todo_attachments (
+				todo_id,
+				name,
+				uploaded_by,
+				download_key,
+				file_size,
+				mime_type
+			)
+		VALUES
+			(
+				@todo_id,
+				@name,
+				@uploaded_by,
+				@download_key,
+				@file_size,
+				@mime_type
+			)
+		RETURNING
+			*
+	`
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{
+		"todo_id":      todoID,
+		"name":         fileName,
+		"uploaded_by":  userID,
+		"download_key": s3Key,
+		"file_size":    fileSize,
+		"mime_type":    mimeType,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create todo attachment for todo_id=%s: %w", todoID.String(), err)
+	}
+
+	attachment, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[todo.TodoAttachment])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect row from table:todo_attachments: %w", err)
+	}
+
+	return &attachment, nil
+}
+
+// AddTodoDependency records that blockingTodoID blocks blockedTodoID, unless
+// blockedTodoID already blocks it through a chain of dependencies. Additions
+// are serialized per workspace so two requests can't each add one half of a
+// cycle. Dependencies never leave an organization, but shares link the
+// todos of different personal workspaces, so those all take the same lock.
+func (r *TodoRepository) AddTodoDependency(ctx context.Context, userID string,
+	blockingTodoID, blockedTodoID uuid.UUID,
+) (*todo.TodoDependency, error) {
+	tx, err := r.server.DB.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	args := pgx.NamedArgs{
+		"user_id":          userID,
+		"org_id":           workspace.OrgID(ctx),
+		"blocking_todo_id": blockingTodoID,
+		"blocked_todo_id":  blockedTodoID,
+	}
+
+	lockStmt := `SELECT pg_advisory_xact_lock(hashtext('todo_dependencies:' || COALESCE(@org_id::TEXT, '')))`
+	if _, err := tx.Exec(ctx, lockStmt, args); err != nil {
+		return nil, fmt.Errorf("failed to lock todo dependencies: %w", err)
+	}
+
+	// Checked after taking the lock, so every dependency added before is seen
+	cycleStmt := `
+		WITH RECURSIVE
+			downstream AS (
+				SELECT
+					blocked_todo_id AS todo_id
+				FROM
+					todo_dependencies
+				WHERE
+					blocking_todo_id = @blocked_todo_id
+				UNION
+				SELECT
+					dep.blocked_todo_id
+				FROM
+					todo_dependencies dep
+					JOIN downstream ON dep.blocking_todo_id = downstream.todo_id
+			)
+		SELECT
+			EXISTS (
+				SELECT
+					1
+				FROM
+					downstream
+				WHERE
+					todo_id = @blocking_todo_id
+			)
+	`
+
+	var createsCycle bool
+	if err := tx.QueryRow(ctx, cycleStmt, args).Scan(&createsCycle); err != nil {
+		return nil, fmt.Errorf("failed to check todo dependencies for blocking_todo_id=%s blocked_todo_id=%s: %w",
+			blockingTodoID.String(), blockedTodoID.String(), err)
+	}
+	if createsCycle {
+		code := "DEPENDENCY_CYCLE"
+		return nil, errs.NewBadRequestError("Dependency would create a cycle", false, &code, nil, nil)
+	}
+
+	stmt := `
+		INSERT INTO
+			todo_dependencies (
+				user_id,
+				blocking_todo_id,
+				blocked_todo_id
+			)
+		VALUES
+			(
+				@user_id,
+				@blocking_todo_id,
+				@blocked_todo_id
+			)
+		RETURNING
+			*
+	`
+
+	rows, err := tx.Query(ctx, stmt, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create todo dependency blocking_todo_id=%s blocked_todo_id=%s: %w",
+			blockingTodoID.String(), blockedTodoID.String(), err)
+	}
+
+	dependency, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[todo.TodoDependency])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect row from table:todo_dependencies: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return &dependency, nil
+}
+
+func (r *TodoRepository) DeleteTodoDependency(ctx context.Context, userID string,
+	blockingTodoID, blockedTodoID uuid.UUID,
+) error {
+	stmt := `
+		DELETE FROM todo_dependencies
+		WHERE
+			blocking_todo_id = @blocking_todo_id
+			AND blocked_todo_id = @blocked_todo_id
+			AND share_role_rank(todo_role(@blocked_todo_id, @user_id, @org_id)) >= share_role_rank('editor')
+	`
+
+	result, err := r.server.DB.Pool.Exec(ctx, stmt, pgx.NamedArgs{
+		"user_id":          userID,
+		"org_id":           workspace.OrgID(ctx),
+		"blocking_todo_id": blockingTodoID,
+		"blocked_todo_id":  blockedTodoID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete todo dependency: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		code := "DEPENDENCY_NOT_FOUND"
+		return errs.NewNotFoundError("dependency not found", false, &code)
+	}
+
+	return nil
+}
+
+// CountIncompleteBlockers returns how many todos blocking todoID are neither
+// completed nor archived.
+func (r *TodoRepository) CountIncompleteBlockers(ctx context.Context, todoID uuid.UUID) (int, error) {
+	stmt := `
+		SELECT
+			COUNT(*)
+		FROM
+			todo_dependencies dep
+			JOIN todos blocker ON blocker.id = dep.blocking_todo_id
+		WHERE
+			dep.blocked_todo_id = @todo_id
+			AND blocker.deleted_at IS NULL
+			AND blocker.status NOT IN ('completed', 'archived')
+	`
+
+	var count int
+	err := r.server.DB.Pool.QueryRow(ctx, stmt, pgx.NamedArgs{
+		"todo_id": todoID,
+	}).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count incomplete blockers for todo_id=%s: %w", todoID.String(), err)
+	}
+
+	return count, nil
+}
+
+// CanAccessTodo reports whether userID can see the todo in the current
+// workspace, e.g. before it is assigned to them.
+func (r *TodoRepository) CanAccessTodo(ctx context.Context, userID string, todoID uuid.UUID) (bool, error) {
+	stmt := `
+		SELECT
+			todo_role(@id, @user_id, @org_id) IS NOT NULL
+	`
+
+	var ok bool
+	err := r.server.DB.Pool.QueryRow(ctx, stmt, pgx.NamedArgs{
+		"id":      todoID,
+		"user_id": userID,
+		"org_id":  workspace.OrgID(ctx),
+	}).Scan(&ok)
+	if err != nil {
+		return false, fmt.Errorf("failed to check todo access for todo_id=%s user_id=%s: %w", todoID.String(), userID, err)
+	}
+
+	return ok, nil
+}
+
+func (r *TodoRepository) AddTodoWatcher(ctx context.Context, todoID uuid.UUID, userID string) (*todo.TodoWatcher, error) {
+	// Watching twice is a no-op that still returns the subscription
+	stmt := `
+		INSERT INTO
+			todo_watchers (todo_id, user_id)
+		VALUES
+			(@todo_id, @user_id)
+		ON CONFLICT (todo_id, user_id) DO UPDATE
+		SET
+			todo_id = EXCLUDED.todo_id
+		RETURNING
+			*
+	`
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{
+		"todo_id": todoID,
+		"user_id": userID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to add watcher for todo_id=%s user_id=%s: %w", todoID.String(), userID, err)
+	}
+
+	watcher, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[todo.TodoWatcher])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect row from table:todo_watchers: %w", err)
+	}
+
+	return &watcher, nil
+}
+
+func (r *TodoRepository) DeleteTodoWatcher(ctx context.Context, todoID uuid.UUID, userID string) error {
+	stmt := `
+		DELETE FROM todo_watchers
+		WHERE
+			todo_id = @todo_id
+			AND user_id = @user_id
+	`
+
+	result, err := r.server.DB.Pool.Exec(ctx, stmt, pgx.NamedArgs{
+		"todo_id": todoID,
+		"user_id": userID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete watcher for todo_id=%s user_id=%s: %w", todoID.String(), userID, err)
+	}
+
+	if result.RowsAffected() == 0 {
+		code := "WATCHER_NOT_FOUND"
+		return errs.NewNotFoundError("watcher not found", false, &code)
+	}
+
+	return nil
+}
+
+// GetTodoWatchers returns the IDs of the users watching the todo.
+func (r *TodoRepository) GetTodoWatchers(ctx context.Context, todoID uuid.UUID) ([]string, error) {
+	stmt := `
+		SELECT
+			user_id
+		FROM
+			todo_watchers
+		WHERE
+			todo_id = @todo_id
+		ORDER BY
+			created_at ASC
 	`
 
 	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{
-		"todo_id":      todoID,
-		"name":         fileName,
-		"uploaded_by":  userID,
-		"download_key": s3Key,
-		"file_size":    fileSize,
-		"mime_type":    mimeType,
+		"todo_id": todoID,
 	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to create todo attachment for todo_id=%s: %w", todoID.String(), err)
+		return nil, fmt.Errorf("failed to get watchers for todo_id=%s: %w", todoID.String(), err)
 	}
 
-	attachment, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[todo.TodoAttachment])
+	watchers, err := pgx.CollectRows(rows, pgx.RowTo[string])
 	if err != nil {
-		return nil, fmt.Errorf("failed to collect row from table:todo_attachments: %w", err)
+		return nil, fmt.Errorf("failed to collect rows from table:todo_watchers for todo_id=%s: %w", todoID.String(), err)
 	}
 
-	return &attachment, nil
+	return watchers, nil
 }
 
 // CRON REQUIREMENTS
@@ -659,6 +2088,7 @@ func (r *TodoRepository) GetTodosDueInHours(ctx context.Context, hours int, limi
 			AND due_date > NOW()
 			AND due_date <= NOW() + INTERVAL '%d hours'
 			AND status NOT IN ('completed', 'archived')
+			AND deleted_at IS NULL
 		ORDER BY
 			due_date ASC
 		LIMIT
@@ -692,6 +2122,7 @@ func (r *TodoRepository) GetOverdueTodos(ctx context.Context, limit int) ([]todo
 			due_date IS NOT NULL
 			AND due_date < NOW()
 			AND status NOT IN ('completed', 'archived')
+			AND deleted_at IS NULL
 		ORDER BY
 			due_date ASC
 		LIMIT
@@ -726,6 +2157,7 @@ func (r *TodoRepository) GetCompletedTodosOlderThan(ctx context.Context, cutoffD
 			status = 'completed'
 			AND completed_at IS NOT NULL
 			AND completed_at < @cutoff_date
+			AND deleted_at IS NULL
 		ORDER BY
 			completed_at ASC
 		LIMIT
@@ -751,27 +2183,351 @@ func (r *TodoRepository) GetCompletedTodosOlderThan(ctx context.Context, cutoffD
 	return todos, nil
 }
 
+// ArchiveTodos archives todos on behalf of the cleanup job, which works
+// across all users and so isn't scoped to one.
 func (r *TodoRepository) ArchiveTodos(ctx context.Context, todoIDs []uuid.UUID) error {
+	archived, err := updateTodos(ctx, r.server.DB.Pool, todoIDs, []string{"status = 'archived'"}, pgx.NamedArgs{}, "TRUE")
+	if err != nil {
+		return fmt.Errorf("failed to archive todos: %w", err)
+	}
+
+	if len(archived) != len(todoIDs) {
+		return fmt.Errorf("expected to archive %d todos, but archived %d", len(todoIDs), len(archived))
+	}
+
+	return nil
+}
+
+// BulkUpdateTodos applies a bulk action to todos in one transaction and
+// returns the ones it changed. Todos the user can no longer change are left
+// out, unless requireAll is set, in which case nothing is changed. Deleting
+// a todo trashes its subtasks along with it.
+func (r *TodoRepository) BulkUpdateTodos(ctx context.Context, userID string, todoIDs []uuid.UUID,
+	payload *todo.BulkTodosPayload, requireAll bool,
+) ([]todo.Todo, error) {
+	tx, err := r.server.DB.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Locking in id order keeps two overlapping bulk updates from deadlocking
+	rows, err := tx.Query(ctx, `
+		SELECT
+			id,
+			status
+		FROM
+			todos
+		WHERE
+			id = ANY(@todo_ids::uuid[])
+			AND share_role_rank(todo_role(id, @user_id, @org_id)) >= share_role_rank('editor')
+			AND deleted_at IS NULL
+		ORDER BY
+			id
+		FOR UPDATE
+	`, pgx.NamedArgs{
+		"todo_ids": todoIDs,
+		"user_id":  userID,
+		"org_id":   workspace.OrgID(ctx),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock todos for bulk update: %w", err)
+	}
+
+	type lockedTodo struct {
+		ID     uuid.UUID   `db:"id"`
+		Status todo.Status `db:"status"`
+	}
+	locked, err := pgx.CollectRows(rows, pgx.RowToStructByName[lockedTodo])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:todos: %w", err)
+	}
+
+	previousStatus := make(map[uuid.UUID]todo.Status, len(locked))
+	for _, t := range locked {
+		previousStatus[t.ID] = t.Status
+	}
+
+	args := pgx.NamedArgs{}
+	var updated []todo.Todo
+
+	if payload.Action == todo.BulkActionDelete {
+		updated, err = r.trashTodos(ctx, tx, userID, todoIDs)
+	} else {
+		scope := "share_role_rank(todo_role(id, @user_id, @org_id)) >= share_role_rank('editor') AND deleted_at IS NULL"
+		args["user_id"] = userID
+		args["org_id"] = workspace.OrgID(ctx)
+
+		updated, err = updateTodos(ctx, tx, todoIDs, bulkSetClauses(payload, args), args, scope)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Subtasks trashed with their parent aren't part of the result
+	updated = slices.DeleteFunc(updated, func(t todo.Todo) bool {
+		return !slices.Contains(todoIDs, t.ID)
+	})
+
+	if requireAll && len(updated) != len(todoIDs) {
+		code := "BULK_UPDATE_CONFLICT"
+		return nil, errs.NewConflictError("Some todos changed during the bulk update, nothing was updated", false, &code)
+	}
+
+	// Completing an occurrence schedules the next one in its series
+	for i := range updated {
+		if updated[i].Status == todo.StatusCompleted && previousStatus[updated[i].ID] != todo.StatusCompleted &&
+			updated[i].IsRecurring() {
+			if _, err := r.createNextOccurrence(ctx, tx, &updated[i]); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return updated, nil
+}
+
+// bulkSetClauses returns the SET clauses for a bulk action other than delete.
+func bulkSetClauses(payload *todo.BulkTodosPayload, args pgx.NamedArgs) []string {
+	switch payload.Action {
+	case todo.BulkActionSetStatus:
+		args["status"] = *payload.Status
+		if *payload.Status == todo.StatusCompleted {
+			args["completed_at"] = time.Now()
+			return []string{"status = @status", "completed_at = @completed_at"}
+		}
+		return []string{"status = @status", "completed_at = NULL"}
+	case todo.BulkActionSetPriority:
+		args["priority"] = *payload.Priority
+		return []string{"priority = @priority"}
+	case todo.BulkActionMoveCategory:
+		args["category_id"] = payload.CategoryID
+		return []string{"category_id = @category_id"}
+	case todo.BulkActionSetDueDate:
+		args["due_date"] = payload.DueDate
+		return []string{"due_date = @due_date"}
+	default:
+		return []string{"status = 'archived'"}
+	}
+}
+
+// updateTodos applies setClauses to the todos matching scope among todoIDs
+// and returns them as updated.
+func updateTodos(ctx context.Context, q querier, todoIDs []uuid.UUID, setClauses []string,
+	args pgx.NamedArgs, scope string,
+) ([]todo.Todo, error) {
+	args["todo_ids"] = todoIDs
+
+	stmt := "UPDATE todos SET " + strings.Join(setClauses, ", ") + `
+		WHERE
+			id = ANY(@todo_ids::uuid[])
+			AND ` + scope + `
+		RETURNING *
+	`
+
+	rows, err := q.Query(ctx, stmt, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute update todos query: %w", err)
+	}
+
+	todos, err := pgx.CollectRows(rows, pgx.RowToStructByName[todo.Todo])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:todos: %w", err)
+	}
+
+	return todos, nil
+}
+
+// trashTodos moves todos the user owns to the trash along with their
+// subtasks, as DeleteTodo does for a single todo.
+func (r *TodoRepository) trashTodos(ctx context.Context, q querier, userID string, todoIDs []uuid.UUID) ([]todo.Todo, error) {
+	args := pgx.NamedArgs{
+		"todo_ids": todoIDs,
+	}
+	scope := workspaceScope(ctx, "", userID, args)
+
 	stmt := `
 		UPDATE todos
 		SET
-			status = 'archived'
+			deleted_at=NOW()
 		WHERE
-			id = ANY(@todo_ids::uuid[])
+			` + scope + `
+			AND deleted_at IS NULL
+			AND (
+				id = ANY(@todo_ids::uuid[])
+				OR parent_todo_id IN (
+					SELECT
+						id
+					FROM
+						todos
+					WHERE
+						id = ANY(@todo_ids::uuid[])
+						AND ` + scope + `
+						AND deleted_at IS NULL
+				)
+			)
+		RETURNING *
+	`
+
+	rows, err := q.Query(ctx, stmt, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute trash todos query: %w", err)
+	}
+
+	todos, err := pgx.CollectRows(rows, pgx.RowToStructByName[todo.Todo])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:todos: %w", err)
+	}
+
+	return todos, nil
+}
+
+// GetTrashedTodosOlderThan returns todos trashed before cutoff. Subtasks of a
+// returned todo are purged with it and aren't returned separately.
+func (r *TodoRepository) GetTrashedTodosOlderThan(ctx context.Context, cutoff time.Time, limit int) ([]todo.Todo, error) {
+	stmt := `
+		SELECT
+			t.*
+		FROM
+			todos t
+		WHERE
+			t.deleted_at < @cutoff
+			AND NOT EXISTS (
+				SELECT
+					1
+				FROM
+					todos parent
+				WHERE
+					parent.id=t.parent_todo_id
+					AND parent.deleted_at < @cutoff
+			)
+		ORDER BY
+			t.deleted_at ASC
+		LIMIT
+			@limit
+	`
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{
+		"cutoff": cutoff,
+		"limit":  limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get trashed todos older than %s query: %w", cutoff.Format("2006-01-02"), err)
+	}
+
+	todos, err := pgx.CollectRows(rows, pgx.RowToStructByName[todo.Todo])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:todos: %w", err)
+	}
+
+	return todos, nil
+}
+
+// GetTodoTreeAttachments returns the attachments of a todo and its subtasks.
+func (r *TodoRepository) GetTodoTreeAttachments(ctx context.Context, todoID uuid.UUID) ([]todo.TodoAttachment, error) {
+	stmt := `
+		SELECT
+			att.*
+		FROM
+			todo_attachments att
+			JOIN todos t ON t.id=att.todo_id
+		WHERE
+			t.id=@todo_id
+			OR t.parent_todo_id=@todo_id
+	`
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{
+		"todo_id": todoID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attachments for todo_id=%s: %w", todoID.String(), err)
+	}
+
+	attachments, err := pgx.CollectRows(rows, pgx.RowToStructByName[todo.TodoAttachment])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:todo_attachments: %w", err)
+	}
+
+	return attachments, nil
+}
+
+// PurgeTodos permanently deletes the given trashed todos and their subtasks.
+// Comments, attachment rows and dependencies go with them.
+func (r *TodoRepository) PurgeTodos(ctx context.Context, todoIDs []uuid.UUID) (int64, error) {
+	stmt := `
+		DELETE FROM todos
+		WHERE
+			(
+				id = ANY(@todo_ids::uuid[])
+				OR parent_todo_id = ANY(@todo_ids::uuid[])
+			)
+			AND deleted_at IS NOT NULL
 	`
 
 	result, err := r.server.DB.Pool.Exec(ctx, stmt, pgx.NamedArgs{
 		"todo_ids": todoIDs,
 	})
 	if err != nil {
-		return fmt.Errorf("failed to archive todos: %w", err)
+		return 0, fmt.Errorf("failed to purge todos: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// GetLatestOccurrencesBefore returns the most recent occurrence of every
+// still-recurring series scheduled on or before horizon, paging by series ID.
+func (r *TodoRepository) GetLatestOccurrencesBefore(ctx context.Context, horizon time.Time,
+	afterSeriesID uuid.UUID, limit int,
+) ([]todo.Todo, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			(
+				SELECT DISTINCT ON (recurrence_series_id)
+					*
+				FROM
+					todos
+				WHERE
+					recurrence_series_id IS NOT NULL
+					AND recurrence_series_id > @after_series_id
+				ORDER BY
+					recurrence_series_id,
+					recurrence_index DESC
+			) latest
+		WHERE
+			recurrence IS NOT NULL
+			AND recurrence_date <= @horizon
+			-- Trashing the latest occurrence pauses the series until it's restored
+			AND deleted_at IS NULL
+		ORDER BY
+			recurrence_series_id
+		LIMIT
+			@limit
+	`
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{
+		"horizon":         horizon,
+		"after_series_id": afterSeriesID,
+		"limit":           limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get latest occurrences query: %w", err)
 	}
 
-	if result.RowsAffected() != int64(len(todoIDs)) {
-		return fmt.Errorf("expected to archive %d todos, but archived %d", len(todoIDs), result.RowsAffected())
+	todos, err := pgx.CollectRows(rows, pgx.RowToStructByName[todo.Todo])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return []todo.Todo{}, nil
+		}
+		return nil, fmt.Errorf("failed to collect rows from table:todos: %w", err)
 	}
 
-	return nil
+	return todos, nil
 }
 
 func (r *TodoRepository) GetWeeklyStatsForUsers(ctx context.Context, startDate, endDate time.Time) ([]todo.UserWeeklyStats, error) {
@@ -784,6 +2540,8 @@ func (r *TodoRepository) GetWeeklyStatsForUsers(ctx context.Context, startDate,
 			COUNT(*) FILTER (WHERE due_date < NOW() AND status NOT IN ('completed', 'archived')) AS overdue_count
 		FROM
 			todos
+		WHERE
+			deleted_at IS NULL
 		GROUP BY
 			user_id
 		HAVING
@@ -812,59 +2570,13 @@ func (r *TodoRepository) GetWeeklyStatsForUsers(ctx context.Context, startDate,
 func (r *TodoRepository) GetCompletedTodosForUser(ctx context.Context, userID string,
 	startDate, endDate time.Time,
 ) ([]todo.PopulatedTodo, error) {
-	stmt := `
-		SELECT
-			t.*,
-			CASE
-				WHEN c.id IS NOT NULL THEN to_jsonb(camel(c))
-				ELSE NULL
-			END AS category,
-			COALESCE(
-				jsonb_agg(
-					CASE
-						WHEN child.id IS NOT NULL THEN to_jsonb(camel(child))
-						ELSE NULL
-					END
-				) FILTER (
-					WHERE child.id IS NOT NULL
-				),
-				'[]'::JSONB
-			) AS children,
-			COALESCE(
-				jsonb_agg(
-					CASE
-						WHEN com.id IS NOT NULL THEN to_jsonb(camel(com))
-						ELSE NULL
-					END
-				) FILTER (
-					WHERE com.id IS NOT NULL
-				),
-				'[]'::JSONB
-			) AS comments,
-			 		 COALESCE(
-				jsonb_agg(
-					to_jsonb(camel (att))
-					ORDER BY
-						att.created_at DESC
-				) FILTER (
-					WHERE
-						att.id IS NOT NULL
-				),
-				'[]'::JSONB
-			) AS attachments
-		FROM
-			todos t
-			LEFT JOIN todo_categories c ON c.id = t.category_id AND c.user_id = @user_id
-			LEFT JOIN todos child ON child.parent_todo_id = t.id AND child.user_id = @user_id
-			LEFT JOIN todo_comments com ON com.todo_id = t.id AND com.user_id = @user_id
-			LEFT JOIN todo_attachments att ON att.todo_id=t.id
+	stmt := populatedTodoSelect(todo.Relations) + `
 		WHERE
 			t.user_id = @user_id
+			AND t.deleted_at IS NULL
 			AND t.status = 'completed'
 			AND t.completed_at >= @start_date
 			AND t.completed_at <= @end_date
-		GROUP BY
-			t.id, c.id
 		ORDER BY
 			t.completed_at DESC
 		LIMIT 10
@@ -891,58 +2603,12 @@ func (r *TodoRepository) GetCompletedTodosForUser(ctx context.Context, userID st
 }
 
 func (r *TodoRepository) GetOverdueTodosForUser(ctx context.Context, userID string) ([]todo.PopulatedTodo, error) {
-	stmt := `
-		SELECT
-			t.*,
-			CASE
-				WHEN c.id IS NOT NULL THEN to_jsonb(camel(c))
-				ELSE NULL
-			END AS category,
-			COALESCE(
-				jsonb_agg(
-					CASE
-						WHEN child.id IS NOT NULL THEN to_jsonb(camel(child))
-						ELSE NULL
-					END
-				) FILTER (
-					WHERE child.id IS NOT NULL
-				),
-				'[]'::JSONB
-			) AS children,
-			COALESCE(
-				jsonb_agg(
-					CASE
-						WHEN com.id IS NOT NULL THEN to_jsonb(camel(com))
-						ELSE NULL
-					END
-				) FILTER (
-					WHERE com.id IS NOT NULL
-				),
-				'[]'::JSONB
-			) AS comments,
-					 COALESCE(
-				jsonb_agg(
-					to_jsonb(camel (att))
-					ORDER BY
-						att.created_at DESC
-				) FILTER (
-					WHERE
-						att.id IS NOT NULL
-				),
-				'[]'::JSONB
-			) AS attachments
-		FROM
-			todos t
-			LEFT JOIN todo_categories c ON c.id = t.category_id AND c.user_id = @user_id
-			LEFT JOIN todos child ON child.parent_todo_id = t.id AND child.user_id = @user_id
-			LEFT JOIN todo_comments com ON com.todo_id = t.id AND com.user_id = @user_id
-			LEFT JOIN todo_attachments att ON att.todo_id=t.id
+	stmt := populatedTodoSelect(todo.Relations) + `
 		WHERE
 			t.user_id = @user_id
+			AND t.deleted_at IS NULL
 			AND t.due_date < NOW()
 			AND t.status NOT IN ('completed', 'archived')
-		GROUP BY
-			t.id, c.id
 		ORDER BY
 			t.due_date ASC
 		LIMIT 10
@@ -965,3 +2631,56 @@ func (r *TodoRepository) GetOverdueTodosForUser(ctx context.Context, userID stri
 
 	return overdueTodos, nil
 }
+
+// GetTodoAudience returns every user who can see the todo outside an
+// organization workspace: its owner and assignee, its watchers, and anyone it
+// is shared with directly, through its parent or through either's category.
+func (r *TodoRepository) GetTodoAudience(ctx context.Context, todoID uuid.UUID) ([]string, error) {
+	stmt := `
+		SELECT
+			t.user_id
+		FROM
+			todos t
+		WHERE
+			t.id = @todo_id
+		UNION
+		SELECT
+			t.assignee_id
+		FROM
+			todos t
+		WHERE
+			t.id = @todo_id
+			AND t.assignee_id IS NOT NULL
+		UNION
+		SELECT
+			w.user_id
+		FROM
+			todo_watchers w
+		WHERE
+			w.todo_id = @todo_id
+		UNION
+		SELECT
+			s.grantee_id
+		FROM
+			todos t
+			LEFT JOIN todos parent ON parent.id = t.parent_todo_id
+			JOIN todo_shares s ON s.todo_id IN (t.id, t.parent_todo_id)
+			OR s.category_id IN (t.category_id, parent.category_id)
+		WHERE
+			t.id = @todo_id
+	`
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{
+		"todo_id": todoID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get audience for todo_id=%s: %w", todoID.String(), err)
+	}
+
+	audience, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:todos for todo_id=%s: %w", todoID.String(), err)
+	}
+
+	return audience, nil
+}
@@ -0,0 +1,434 @@
+package repository_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uttam282005/tasker/internal/errs"
+	"github.com/uttam282005/tasker/internal/lib/workspace"
+	"github.com/uttam282005/tasker/internal/model/todo"
+	"github.com/uttam282005/tasker/internal/repository"
+	"github.com/uttam282005/tasker/internal/server"
+	testhelpers "github.com/uttam282005/tasker/internal/testing"
+)
+
+// seededTodo is a todo inserted with a number of each relation.
+type seededTodo struct {
+	ID          uuid.UUID
+	Children    []string
+	Comments    []string
+	Attachments []string
+	Tags        []string
+}
+
+// relationCounts says how many of each relation seedTodo inserts.
+type relationCounts struct {
+	children, comments, attachments, tags int
+}
+
+// fanOutStmt reads a todo's relations the way populated todos were read
+// before each relation got its own LATERAL subquery: every relation LEFT
+// JOINed at once and grouped by todo. DISTINCT undoes the duplicates the
+// joins multiply out, which fanned_out_rows counts.
+const fanOutStmt = `
+	SELECT
+		COUNT(*) AS fanned_out_rows,
+		COALESCE(array_agg(DISTINCT child.id::TEXT) FILTER (WHERE child.id IS NOT NULL), '{}') AS children,
+		COALESCE(array_agg(DISTINCT com.id::TEXT) FILTER (WHERE com.id IS NOT NULL), '{}') AS comments,
+		COALESCE(array_agg(DISTINCT att.id::TEXT) FILTER (WHERE att.id IS NOT NULL), '{}') AS attachments,
+		COALESCE(array_agg(DISTINCT tt.tag_id::TEXT) FILTER (WHERE tt.tag_id IS NOT NULL), '{}') AS tags
+	FROM
+		todos t
+		LEFT JOIN todos child ON child.parent_todo_id=t.id
+		AND child.deleted_at IS NULL
+		LEFT JOIN todo_comments com ON com.todo_id=t.id
+		AND com.deleted_at IS NULL
+		LEFT JOIN todo_attachments att ON att.todo_id=t.id
+		LEFT JOIN todo_tags tt ON tt.todo_id=t.id
+	WHERE
+		t.id=@id
+	GROUP BY
+		t.id
+`
+
+func setupTodoRepository(t testing.TB) (*server.Server, *repository.TodoRepository) {
+	t.Helper()
+
+	_, srv, cleanup := testhelpers.SetupTest(t)
+	t.Cleanup(cleanup)
+
+	return srv, repository.NewTodoRepository(srv)
+}
+
+// seedTodo inserts a todo for userID in orgID's workspace, or their personal
+// one when orgID is nil, with counts of each relation.
+func seedTodo(t testing.TB, pool *pgxpool.Pool, userID string, orgID *string, categoryID *uuid.UUID,
+	counts relationCounts,
+) seededTodo {
+	t.Helper()
+
+	ctx := context.Background()
+	args := pgx.NamedArgs{
+		"user_id":     userID,
+		"org_id":      orgID,
+		"category_id": categoryID,
+	}
+
+	var seeded seededTodo
+	err := pool.QueryRow(ctx, `
+		INSERT INTO
+			todos (user_id, org_id, title, status, category_id)
+		VALUES
+			(@user_id, @org_id, 'Seeded todo', 'active', @category_id)
+		RETURNING
+			id
+	`, args).Scan(&seeded.ID)
+	require.NoError(t, err, "failed to seed todo")
+	args["todo_id"] = seeded.ID
+
+	insert := func(n int, stmt string) []string {
+		args["n"] = n
+		rows, err := pool.Query(ctx, stmt, args)
+		require.NoError(t, err, "failed to seed relation")
+		ids, err := pgx.CollectRows(rows, pgx.RowTo[string])
+		require.NoError(t, err, "failed to seed relation")
+		return ids
+	}
+
+	seeded.Children = insert(counts.children, `
+		INSERT INTO
+			todos (user_id, org_id, title, parent_todo_id)
+		SELECT
+			@user_id, @org_id, 'Subtask ' || i, @todo_id
+		FROM
+			generate_series(1, @n::INT) i
+		RETURNING
+			id::TEXT
+	`)
+	seeded.Comments = insert(counts.comments, `
+		INSERT INTO
+			todo_comments (todo_id, user_id, content)
+		SELECT
+			@todo_id, @user_id, 'Comment ' || i
+		FROM
+			generate_series(1, @n::INT) i
+		RETURNING
+			id::TEXT
+	`)
+	seeded.Attachments = insert(counts.attachments, `
+		INSERT INTO
+			todo_attachments (todo_id, name, uploaded_by, download_key)
+		SELECT
+			@todo_id, 'file-' || i || '.txt', @user_id, 'attachments/' || gen_random_uuid()
+		FROM
+			generate_series(1, @n::INT) i
+		RETURNING
+			id::TEXT
+	`)
+	seeded.Tags = insert(counts.tags, `
+		WITH
+			new_tags AS (
+				INSERT INTO
+					tags (user_id, org_id, name)
+				SELECT
+					@user_id, @org_id, 'tag-' || gen_random_uuid()
+				FROM
+					generate_series(1, @n::INT) i
+				RETURNING
+					id
+			)
+		INSERT INTO
+			todo_tags (todo_id, tag_id)
+		SELECT
+			@todo_id, id
+		FROM
+			new_tags
+		RETURNING
+			tag_id::TEXT
+	`)
+
+	return seeded
+}
+
+// readFanOut reads a todo with fanOutStmt.
+func readFanOut(t testing.TB, pool *pgxpool.Pool, todoID uuid.UUID) (int, seededTodo) {
+	t.Helper()
+
+	fanOut := seededTodo{ID: todoID}
+	var rows int
+	err := pool.QueryRow(context.Background(), fanOutStmt, pgx.NamedArgs{"id": todoID}).
+		Scan(&rows, &fanOut.Children, &fanOut.Comments, &fanOut.Attachments, &fanOut.Tags)
+	require.NoError(t, err, "failed to read todo with the fan-out query")
+
+	return rows, fanOut
+}
+
+// assertRelations checks that a populated todo lists exactly the expected
+// relations, each once.
+func assertRelations(t *testing.T, expected seededTodo, actual *todo.PopulatedTodo) {
+	t.Helper()
+
+	var children, comments, attachments, tags []string
+	for _, child := range actual.Children {
+		children = append(children, child.ID.String())
+	}
+	for _, c := range actual.Comments {
+		comments = append(comments, c.ID.String())
+	}
+	for _, a := range actual.Attachments {
+		attachments = append(attachments, a.ID.String())
+	}
+	for _, tg := range actual.Tags {
+		tags = append(tags, tg.ID.String())
+	}
+
+	assert.ElementsMatch(t, expected.Children, children, "children")
+	assert.ElementsMatch(t, expected.Comments, comments, "comments")
+	assert.ElementsMatch(t, expected.Attachments, attachments, "attachments")
+	assert.ElementsMatch(t, expected.Tags, tags, "tags")
+}
+
+func TestGetTodoByIDListsEachRelationOnce(t *testing.T) {
+	srv, repo := setupTodoRepository(t)
+	ctx := context.Background()
+
+	seeded := seedTodo(t, srv.DB.Pool, "user_alice", nil, nil, relationCounts{children: 3, comments: 2, attachments: 2, tags: 2})
+
+	// A trashed comment is left out by both queries
+	_, err := srv.DB.Pool.Exec(ctx, `
+		INSERT INTO
+			todo_comments (todo_id, user_id, content, deleted_at)
+		VALUES
+			($1, 'user_alice', 'Trashed', NOW())
+	`, seeded.ID)
+	require.NoError(t, err)
+
+	fannedOutRows, fanOut := readFanOut(t, srv.DB.Pool, seeded.ID)
+	// What the joins multiplied every relation into
+	assert.Equal(t, 3*2*2*2, fannedOutRows)
+	assert.ElementsMatch(t, seeded.Children, fanOut.Children)
+	assert.ElementsMatch(t, seeded.Comments, fanOut.Comments)
+	assert.ElementsMatch(t, seeded.Attachments, fanOut.Attachments)
+	assert.ElementsMatch(t, seeded.Tags, fanOut.Tags)
+
+	todoItem, err := repo.GetTodoByID(ctx, "user_alice", seeded.ID)
+	require.NoError(t, err)
+
+	assertRelations(t, fanOut, todoItem)
+	assert.Len(t, todoItem.Children, 3)
+	assert.Len(t, todoItem.Comments, 2)
+	assert.Len(t, todoItem.Attachments, 2)
+	assert.Len(t, todoItem.Tags, 2)
+	assert.Empty(t, todoItem.BlockedBy)
+	assert.Empty(t, todoItem.Blocking)
+	assert.Empty(t, todoItem.Watchers)
+}
+
+func TestGetTodosMatchesFanOutQuery(t *testing.T) {
+	srv, repo := setupTodoRepository(t)
+	ctx := context.Background()
+
+	seeded := map[uuid.UUID]bool{}
+	for _, counts := range []relationCounts{
+		{},
+		{children: 1},
+		{comments: 3, attachments: 1},
+		{children: 2, comments: 2, attachments: 2, tags: 2},
+		{children: 4, comments: 1, attachments: 3, tags: 1},
+	} {
+		seeded[seedTodo(t, srv.DB.Pool, "user_alice", nil, nil, counts).ID] = true
+	}
+
+	// Someone else's todo doesn't show up
+	seedTodo(t, srv.DB.Pool, "user_bob", nil, nil, relationCounts{children: 1, comments: 1})
+
+	query := &todo.GetTodosQuery{}
+	require.NoError(t, query.Validate())
+
+	page, err := repo.GetTodos(ctx, "user_alice", query)
+	require.NoError(t, err)
+	require.NotNil(t, page.Total)
+	assert.Equal(t, len(seeded), *page.Total)
+	require.Len(t, page.Data, len(seeded))
+
+	for i := range page.Data {
+		todoItem := &page.Data[i]
+		assert.True(t, seeded[todoItem.ID], "unexpected todo %s", todoItem.ID)
+
+		_, fanOut := readFanOut(t, srv.DB.Pool, todoItem.ID)
+		assertRelations(t, fanOut, todoItem)
+	}
+}
+
+func TestGetTodosIncludesOnlyRequestedRelations(t *testing.T) {
+	srv, repo := setupTodoRepository(t)
+	ctx := context.Background()
+
+	seeded := seedTodo(t, srv.DB.Pool, "user_alice", nil, nil, relationCounts{children: 2, comments: 2, attachments: 2, tags: 1})
+
+	include := todo.RelationComments
+	query := &todo.GetTodosQuery{Include: &include}
+	require.NoError(t, query.Validate())
+
+	page, err := repo.GetTodos(ctx, "user_alice", query)
+	require.NoError(t, err)
+	require.Len(t, page.Data, 1)
+
+	todoItem := page.Data[0]
+	assert.Equal(t, seeded.ID, todoItem.ID)
+	assert.Len(t, todoItem.Comments, 2)
+	assert.Nil(t, todoItem.Children)
+	assert.Nil(t, todoItem.Attachments)
+	// Relations that can't be left out are always there
+	assert.Len(t, todoItem.Tags, 1)
+}
+
+func TestGetTodoByIDReadsCategory(t *testing.T) {
+	srv, repo := setupTodoRepository(t)
+	ctx := context.Background()
+	orgID := "org_acme"
+
+	createCategory := func(userID string, orgID *string, name string) uuid.UUID {
+		var id uuid.UUID
+		err := srv.DB.Pool.QueryRow(ctx, `
+			INSERT INTO
+				todo_categories (user_id, org_id, name)
+			VALUES
+				($1, $2, $3)
+			RETURNING
+				id
+		`, userID, orgID, name).Scan(&id)
+		require.NoError(t, err)
+		return id
+	}
+
+	t.Run("another member's category in an organization", func(t *testing.T) {
+		categoryID := createCategory("user_bob", &orgID, "Shared")
+		seeded := seedTodo(t, srv.DB.Pool, "user_alice", &orgID, &categoryID, relationCounts{})
+
+		todoItem, err := repo.GetTodoByID(workspace.WithOrgID(ctx, orgID), "user_alice", seeded.ID)
+		require.NoError(t, err)
+		require.NotNil(t, todoItem.Category)
+		assert.Equal(t, categoryID, todoItem.Category.ID)
+	})
+
+	t.Run("own personal category", func(t *testing.T) {
+		categoryID := createCategory("user_alice", nil, "Mine")
+		seeded := seedTodo(t, srv.DB.Pool, "user_alice", nil, &categoryID, relationCounts{})
+
+		todoItem, err := repo.GetTodoByID(ctx, "user_alice", seeded.ID)
+		require.NoError(t, err)
+		require.NotNil(t, todoItem.Category)
+		assert.Equal(t, categoryID, todoItem.Category.ID)
+	})
+
+	t.Run("someone else's personal category", func(t *testing.T) {
+		categoryID := createCategory("user_bob", nil, "Theirs")
+		seeded := seedTodo(t, srv.DB.Pool, "user_alice", nil, &categoryID, relationCounts{})
+
+		todoItem, err := repo.GetTodoByID(ctx, "user_alice", seeded.ID)
+		require.NoError(t, err)
+		assert.Nil(t, todoItem.Category)
+	})
+
+	t.Run("category from another workspace", func(t *testing.T) {
+		categoryID := createCategory("user_alice", nil, "Personal")
+		seeded := seedTodo(t, srv.DB.Pool, "user_alice", &orgID, &categoryID, relationCounts{})
+
+		todoItem, err := repo.GetTodoByID(workspace.WithOrgID(ctx, orgID), "user_alice", seeded.ID)
+		require.NoError(t, err)
+		assert.Nil(t, todoItem.Category)
+	})
+
+	t.Run("trashed category", func(t *testing.T) {
+		categoryID := createCategory("user_alice", nil, "Trashed")
+		seeded := seedTodo(t, srv.DB.Pool, "user_alice", nil, &categoryID, relationCounts{})
+
+		_, err := srv.DB.Pool.Exec(ctx, "UPDATE todo_categories SET deleted_at = NOW() WHERE id = $1", categoryID)
+		require.NoError(t, err)
+
+		todoItem, err := repo.GetTodoByID(ctx, "user_alice", seeded.ID)
+		require.NoError(t, err)
+		assert.Nil(t, todoItem.Category)
+	})
+}
+
+func TestAddTodoDependencyRefusesCycles(t *testing.T) {
+	srv, repo := setupTodoRepository(t)
+	ctx := context.Background()
+
+	a := seedTodo(t, srv.DB.Pool, "user_alice", nil, nil, relationCounts{}).ID
+	b := seedTodo(t, srv.DB.Pool, "user_alice", nil, nil, relationCounts{}).ID
+	c := seedTodo(t, srv.DB.Pool, "user_alice", nil, nil, relationCounts{}).ID
+
+	_, err := repo.AddTodoDependency(ctx, "user_alice", a, b)
+	require.NoError(t, err)
+	_, err = repo.AddTodoDependency(ctx, "user_alice", b, c)
+	require.NoError(t, err)
+
+	_, err = repo.AddTodoDependency(ctx, "user_alice", c, a)
+	var httpErr *errs.HTTPError
+	require.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, "DEPENDENCY_CYCLE", httpErr.Code)
+
+	// Both halves of a cycle added at once: only one of them goes in
+	for range 10 {
+		x := seedTodo(t, srv.DB.Pool, "user_alice", nil, nil, relationCounts{}).ID
+		y := seedTodo(t, srv.DB.Pool, "user_alice", nil, nil, relationCounts{}).ID
+
+		var wg sync.WaitGroup
+		results := make([]error, 2)
+		for i, edge := range [][2]uuid.UUID{{x, y}, {y, x}} {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, results[i] = repo.AddTodoDependency(ctx, "user_alice", edge[0], edge[1])
+			}()
+		}
+		wg.Wait()
+
+		failed := 0
+		for _, err := range results {
+			if err != nil {
+				require.ErrorAs(t, err, &httpErr)
+				assert.Equal(t, "DEPENDENCY_CYCLE", httpErr.Code)
+				failed++
+			}
+		}
+		assert.Equal(t, 1, failed)
+	}
+}
+
+// BenchmarkGetTodos reads a page of todos that each have several of every
+// relation, which the fan-out query multiplied into thousands of rows.
+func BenchmarkGetTodos(b *testing.B) {
+	srv, repo := setupTodoRepository(b)
+	ctx := context.Background()
+
+	for range 200 {
+		seedTodo(b, srv.DB.Pool, "user_alice", nil, nil, relationCounts{children: 5, comments: 5, attachments: 3, tags: 3})
+	}
+
+	_, err := srv.DB.Pool.Exec(ctx, "ANALYZE")
+	require.NoError(b, err)
+
+	limit := 50
+	query := &todo.GetTodosQuery{Limit: &limit}
+	require.NoError(b, query.Validate())
+
+	for b.Loop() {
+		page, err := repo.GetTodos(ctx, "user_alice", query)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if len(page.Data) != limit {
+			b.Fatalf("got %d todos, want %d", len(page.Data), limit)
+		}
+	}
+}
@@ -0,0 +1,286 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/uttam282005/tasker/internal/errs"
+	"github.com/uttam282005/tasker/internal/lib/workspace"
+	"github.com/uttam282005/tasker/internal/model/view"
+	"github.com/uttam282005/tasker/internal/server"
+)
+
+type ViewRepository struct {
+	server *server.Server
+}
+
+func NewViewRepository(server *server.Server) *ViewRepository {
+	return &ViewRepository{server: server}
+}
+
+// viewPinnedColumn tells whether the user pinned the view v.
+const viewPinnedColumn = `EXISTS (
+				SELECT
+					1
+				FROM
+					saved_view_pins p
+				WHERE
+					p.view_id=v.id
+					AND p.user_id=@user_id
+			) AS pinned`
+
+// viewScope limits views v to the ones the user can open: their own in the
+// current workspace and, in an organization, those shared with its members.
+func viewScope(ctx context.Context, userID string, args pgx.NamedArgs) string {
+	args["user_id"] = userID
+
+	if orgID := workspace.OrgID(ctx); orgID != nil {
+		args["org_id"] = *orgID
+		return "v.org_id = @org_id AND (v.user_id = @user_id OR v.shared)"
+	}
+
+	return "v.user_id = @user_id AND v.org_id IS NULL"
+}
+
+func (r *ViewRepository) CreateView(ctx context.Context, userID string, payload *view.CreateViewPayload) (*view.View, error) {
+	stmt := `
+		INSERT INTO
+			saved_views AS v (
+				user_id,
+				org_id,
+				name,
+				filters,
+				sort,
+				sort_direction,
+				group_by,
+				shared
+			)
+		VALUES
+			(
+				@user_id,
+				@org_id,
+				@name,
+				@filters,
+				@sort,
+				@sort_direction,
+				@group_by,
+				@shared
+			)
+		RETURNING
+			v.*,
+			FALSE AS pinned
+	`
+
+	shared := payload.Shared != nil && *payload.Shared
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{
+		"user_id":        userID,
+		"org_id":         workspace.OrgID(ctx),
+		"name":           payload.Name,
+		"filters":        payload.Filters,
+		"sort":           *payload.Sort,
+		"sort_direction": *payload.Order,
+		"group_by":       *payload.GroupBy,
+		"shared":         shared,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute create view query for user_id=%s name=%s: %w", userID, payload.Name, err)
+	}
+
+	viewItem, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[view.View])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect row from table:saved_views for user_id=%s name=%s: %w", userID, payload.Name, err)
+	}
+
+	return &viewItem, nil
+}
+
+// GetViews lists the views the user can open, their pinned ones first.
+func (r *ViewRepository) GetViews(ctx context.Context, userID string) ([]view.View, error) {
+	args := pgx.NamedArgs{}
+	stmt := `
+		SELECT
+			v.*,
+			` + viewPinnedColumn + `
+		FROM
+			saved_views v
+		WHERE
+			` + viewScope(ctx, userID, args) + `
+		ORDER BY
+			pinned DESC,
+			v.name ASC,
+			v.created_at ASC
+	`
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get views query for user_id=%s: %w", userID, err)
+	}
+
+	views, err := pgx.CollectRows(rows, pgx.RowToStructByName[view.View])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:saved_views for user_id=%s: %w", userID, err)
+	}
+
+	return views, nil
+}
+
+func (r *ViewRepository) GetViewByID(ctx context.Context, userID string, viewID uuid.UUID) (*view.View, error) {
+	args := pgx.NamedArgs{"id": viewID}
+	stmt := `
+		SELECT
+			v.*,
+			` + viewPinnedColumn + `
+		FROM
+			saved_views v
+		WHERE
+			v.id=@id
+			AND ` + viewScope(ctx, userID, args) + `
+	`
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get view by id query for view_id=%s user_id=%s: %w", viewID.String(), userID, err)
+	}
+
+	viewItem, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[view.View])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			code := "VIEW_NOT_FOUND"
+			return nil, errs.NewNotFoundError("view not found", false, &code)
+		}
+		return nil, fmt.Errorf("failed to collect row from table:saved_views for view_id=%s user_id=%s: %w", viewID.String(), userID, err)
+	}
+
+	return &viewItem, nil
+}
+
+// UpdateView changes a view the user created.
+func (r *ViewRepository) UpdateView(ctx context.Context, userID string, payload *view.UpdateViewPayload) (*view.View, error) {
+	args := pgx.NamedArgs{"id": payload.ID}
+	setClauses := []string{}
+
+	if payload.Name != nil {
+		setClauses = append(setClauses, "name = @name")
+		args["name"] = *payload.Name
+	}
+
+	if payload.Filters != nil {
+		setClauses = append(setClauses, "filters = @filters")
+		args["filters"] = *payload.Filters
+	}
+
+	if payload.Sort != nil {
+		setClauses = append(setClauses, "sort = @sort")
+		args["sort"] = *payload.Sort
+	}
+
+	if payload.Order != nil {
+		setClauses = append(setClauses, "sort_direction = @sort_direction")
+		args["sort_direction"] = *payload.Order
+	}
+
+	if payload.GroupBy != nil {
+		setClauses = append(setClauses, "group_by = @group_by")
+		args["group_by"] = *payload.GroupBy
+	}
+
+	if payload.Shared != nil {
+		setClauses = append(setClauses, "shared = @shared")
+		args["shared"] = *payload.Shared
+	}
+
+	if len(setClauses) == 0 {
+		return nil, errs.NewBadRequestError("no fields to update", false, nil, nil, nil)
+	}
+
+	stmt := "UPDATE saved_views AS v SET " + strings.Join(setClauses, ", ") +
+		" WHERE v.id = @id AND " + ownerScope(ctx, userID, args) +
+		" RETURNING v.*, " + viewPinnedColumn
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute update view query for view_id=%s user_id=%s: %w", payload.ID.String(), userID, err)
+	}
+
+	viewItem, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[view.View])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			code := "VIEW_NOT_FOUND"
+			return nil, errs.NewNotFoundError("view not found", false, &code)
+		}
+		return nil, fmt.Errorf("failed to collect row from table:saved_views for view_id=%s user_id=%s: %w", payload.ID.String(), userID, err)
+	}
+
+	return &viewItem, nil
+}
+
+// DeleteView deletes a view the user created, along with everyone's pins
+// of it.
+func (r *ViewRepository) DeleteView(ctx context.Context, userID string, viewID uuid.UUID) error {
+	args := pgx.NamedArgs{"id": viewID}
+	stmt := `
+		DELETE FROM saved_views
+		WHERE
+			id=@id
+			AND ` + ownerScope(ctx, userID, args) + `
+	`
+
+	result, err := r.server.DB.Pool.Exec(ctx, stmt, args)
+	if err != nil {
+		return fmt.Errorf("failed to execute delete view query for view_id=%s user_id=%s: %w", viewID.String(), userID, err)
+	}
+
+	if result.RowsAffected() == 0 {
+		code := "VIEW_NOT_FOUND"
+		return errs.NewNotFoundError("view not found", false, &code)
+	}
+
+	return nil
+}
+
+// PinView pins the view for the user. Pinning it again does nothing.
+func (r *ViewRepository) PinView(ctx context.Context, userID string, viewID uuid.UUID) error {
+	stmt := `
+		INSERT INTO
+			saved_view_pins (view_id, user_id)
+		VALUES
+			(@view_id, @user_id)
+		ON CONFLICT (view_id, user_id) DO NOTHING
+	`
+
+	_, err := r.server.DB.Pool.Exec(ctx, stmt, pgx.NamedArgs{
+		"view_id": viewID,
+		"user_id": userID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to pin view_id=%s for user_id=%s: %w", viewID.String(), userID, err)
+	}
+
+	return nil
+}
+
+// UnpinView unpins the view for the user. Unpinning a view that isn't
+// pinned does nothing.
+func (r *ViewRepository) UnpinView(ctx context.Context, userID string, viewID uuid.UUID) error {
+	stmt := `
+		DELETE FROM saved_view_pins
+		WHERE
+			view_id=@view_id
+			AND user_id=@user_id
+	`
+
+	_, err := r.server.DB.Pool.Exec(ctx, stmt, pgx.NamedArgs{
+		"view_id": viewID,
+		"user_id": userID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to unpin view_id=%s for user_id=%s: %w", viewID.String(), userID, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,414 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/uttam282005/tasker/internal/errs"
+	"github.com/uttam282005/tasker/internal/lib/workspace"
+	"github.com/uttam282005/tasker/internal/model"
+	"github.com/uttam282005/tasker/internal/model/webhook"
+	"github.com/uttam282005/tasker/internal/server"
+)
+
+type WebhookRepository struct {
+	server *server.Server
+}
+
+func NewWebhookRepository(server *server.Server) *WebhookRepository {
+	return &WebhookRepository{server: server}
+}
+
+func (r *WebhookRepository) CreateWebhook(ctx context.Context, userID string,
+	payload *webhook.CreateWebhookPayload, secret string,
+) (*webhook.Webhook, error) {
+	stmt := `
+		INSERT INTO
+			webhooks (
+				user_id,
+				org_id,
+				url,
+				secret,
+				events
+			)
+		VALUES
+			(
+				@user_id,
+				@org_id,
+				@url,
+				@secret,
+				@events
+			)
+		RETURNING
+			*
+	`
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{
+		"user_id": userID,
+		"org_id":  workspace.OrgID(ctx),
+		"url":     payload.URL,
+		"secret":  secret,
+		"events":  payload.Events,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute create webhook query for user_id=%s: %w", userID, err)
+	}
+
+	webhookItem, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[webhook.Webhook])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect row from table:webhooks for user_id=%s: %w", userID, err)
+	}
+
+	return &webhookItem, nil
+}
+
+func (r *WebhookRepository) GetWebhooks(ctx context.Context, userID string) ([]webhook.Webhook, error) {
+	args := pgx.NamedArgs{}
+	stmt := `
+		SELECT
+			*
+		FROM
+			webhooks
+		WHERE
+			` + workspaceScope(ctx, "", userID, args) + `
+		ORDER BY
+			created_at ASC
+	`
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get webhooks query for user_id=%s: %w", userID, err)
+	}
+
+	webhooks, err := pgx.CollectRows(rows, pgx.RowToStructByName[webhook.Webhook])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:webhooks for user_id=%s: %w", userID, err)
+	}
+
+	return webhooks, nil
+}
+
+func (r *WebhookRepository) GetWebhookByID(ctx context.Context, userID string, webhookID uuid.UUID) (*webhook.Webhook, error) {
+	args := pgx.NamedArgs{"id": webhookID}
+	stmt := `
+		SELECT
+			*
+		FROM
+			webhooks
+		WHERE
+			id=@id
+			AND ` + workspaceScope(ctx, "", userID, args) + `
+	`
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get webhook by id query for webhook_id=%s user_id=%s: %w", webhookID.String(), userID, err)
+	}
+
+	webhookItem, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[webhook.Webhook])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect row from table:webhooks for webhook_id=%s user_id=%s: %w", webhookID.String(), userID, err)
+	}
+
+	return &webhookItem, nil
+}
+
+func (r *WebhookRepository) UpdateWebhook(ctx context.Context, userID string, payload *webhook.UpdateWebhookPayload) (*webhook.Webhook, error) {
+	args := pgx.NamedArgs{"id": payload.ID}
+	setClauses := []string{}
+
+	if payload.URL != nil {
+		setClauses = append(setClauses, "url = @url")
+		args["url"] = *payload.URL
+	}
+
+	if payload.Events != nil {
+		setClauses = append(setClauses, "events = @events")
+		args["events"] = payload.Events
+	}
+
+	if payload.Active != nil {
+		setClauses = append(setClauses, "active = @active")
+		args["active"] = *payload.Active
+	}
+
+	if len(setClauses) == 0 {
+		return nil, errs.NewBadRequestError("no fields to update", false, nil, nil, nil)
+	}
+
+	stmt := "UPDATE webhooks SET " + strings.Join(setClauses, ", ") +
+		" WHERE id = @id AND " + workspaceScope(ctx, "", userID, args) + " RETURNING *"
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute update webhook query for webhook_id=%s user_id=%s: %w", payload.ID.String(), userID, err)
+	}
+
+	webhookItem, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[webhook.Webhook])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect row from table:webhooks for webhook_id=%s user_id=%s: %w", payload.ID.String(), userID, err)
+	}
+
+	return &webhookItem, nil
+}
+
+func (r *WebhookRepository) DeleteWebhook(ctx context.Context, userID string, webhookID uuid.UUID) error {
+	args := pgx.NamedArgs{"id": webhookID}
+	stmt := `
+		DELETE FROM webhooks
+		WHERE
+			id=@id
+			AND ` + workspaceScope(ctx, "", userID, args) + `
+	`
+
+	result, err := r.server.DB.Pool.Exec(ctx, stmt, args)
+	if err != nil {
+		return fmt.Errorf("failed to execute delete webhook query for webhook_id=%s user_id=%s: %w", webhookID.String(), userID, err)
+	}
+
+	if result.RowsAffected() == 0 {
+		code := "WEBHOOK_NOT_FOUND"
+		return errs.NewNotFoundError("webhook not found", false, &code)
+	}
+
+	return nil
+}
+
+// GetSubscribedWebhooks returns the active webhooks that receive event for a
+// todo owned by ownerID in orgID's workspace, or ownerID's personal one when
+// orgID is nil.
+func (r *WebhookRepository) GetSubscribedWebhooks(ctx context.Context, ownerID string, orgID *string,
+	event webhook.Event,
+) ([]webhook.Webhook, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			webhooks
+		WHERE
+			active
+			AND events ? @event
+			AND (
+				(@org_id::TEXT IS NOT NULL AND org_id = @org_id::TEXT)
+				OR (@org_id::TEXT IS NULL AND org_id IS NULL AND user_id = @user_id)
+			)
+	`
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{
+		"user_id": ownerID,
+		"org_id":  orgID,
+		"event":   event,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get subscribed webhooks query for user_id=%s event=%s: %w", ownerID, event, err)
+	}
+
+	webhooks, err := pgx.CollectRows(rows, pgx.RowToStructByName[webhook.Webhook])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:webhooks for user_id=%s: %w", ownerID, err)
+	}
+
+	return webhooks, nil
+}
+
+// GetWebhookForDelivery loads a webhook without a workspace check, for the
+// worker sending its deliveries.
+func (r *WebhookRepository) GetWebhookForDelivery(ctx context.Context, webhookID uuid.UUID) (*webhook.Webhook, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			webhooks
+		WHERE
+			id=@id
+	`
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{
+		"id": webhookID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get webhook query for webhook_id=%s: %w", webhookID.String(), err)
+	}
+
+	webhookItem, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[webhook.Webhook])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect row from table:webhooks for webhook_id=%s: %w", webhookID.String(), err)
+	}
+
+	return &webhookItem, nil
+}
+
+func (r *WebhookRepository) CreateDelivery(ctx context.Context, webhookID uuid.UUID, event webhook.Event,
+	payload json.RawMessage,
+) (*webhook.Delivery, error) {
+	stmt := `
+		INSERT INTO
+			webhook_deliveries (
+				webhook_id,
+				event,
+				payload
+			)
+		VALUES
+			(
+				@webhook_id,
+				@event,
+				@payload
+			)
+		RETURNING
+			*
+	`
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{
+		"webhook_id": webhookID,
+		"event":      event,
+		"payload":    payload,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute create delivery query for webhook_id=%s: %w", webhookID.String(), err)
+	}
+
+	delivery, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[webhook.Delivery])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect row from table:webhook_deliveries for webhook_id=%s: %w", webhookID.String(), err)
+	}
+
+	return &delivery, nil
+}
+
+// GetDeliveryByID loads a delivery without a workspace check, for the worker
+// sending it.
+func (r *WebhookRepository) GetDeliveryByID(ctx context.Context, deliveryID uuid.UUID) (*webhook.Delivery, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			webhook_deliveries
+		WHERE
+			id=@id
+	`
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{
+		"id": deliveryID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get delivery query for delivery_id=%s: %w", deliveryID.String(), err)
+	}
+
+	delivery, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[webhook.Delivery])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect row from table:webhook_deliveries for delivery_id=%s: %w", deliveryID.String(), err)
+	}
+
+	return &delivery, nil
+}
+
+func (r *WebhookRepository) GetWebhookDelivery(ctx context.Context, webhookID, deliveryID uuid.UUID) (*webhook.Delivery, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			webhook_deliveries
+		WHERE
+			id=@id
+			AND webhook_id=@webhook_id
+	`
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{
+		"id":         deliveryID,
+		"webhook_id": webhookID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get delivery query for delivery_id=%s: %w", deliveryID.String(), err)
+	}
+
+	delivery, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[webhook.Delivery])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect row from table:webhook_deliveries for delivery_id=%s: %w", deliveryID.String(), err)
+	}
+
+	return &delivery, nil
+}
+
+func (r *WebhookRepository) GetDeliveries(ctx context.Context, query *webhook.GetDeliveriesQuery) (*model.PaginatedResponse[webhook.Delivery], error) {
+	args := pgx.NamedArgs{
+		"webhook_id": query.WebhookID,
+	}
+	conditions := []string{"webhook_id = @webhook_id"}
+
+	if query.Status != nil {
+		conditions = append(conditions, "status = @status")
+		args["status"] = *query.Status
+	}
+
+	where := " WHERE " + strings.Join(conditions, " AND ")
+
+	var total int
+	err := r.server.DB.Pool.QueryRow(ctx, "SELECT COUNT(*) FROM webhook_deliveries"+where, args).Scan(&total)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get total count for deliveries webhook_id=%s: %w", query.WebhookID.String(), err)
+	}
+
+	stmt := "SELECT * FROM webhook_deliveries" + where + " ORDER BY created_at DESC LIMIT @limit OFFSET @offset"
+	args["limit"] = *query.Limit
+	args["offset"] = (*query.Page - 1) * (*query.Limit)
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get deliveries query for webhook_id=%s: %w", query.WebhookID.String(), err)
+	}
+
+	deliveries, err := pgx.CollectRows(rows, pgx.RowToStructByName[webhook.Delivery])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:webhook_deliveries for webhook_id=%s: %w", query.WebhookID.String(), err)
+	}
+
+	return &model.PaginatedResponse[webhook.Delivery]{
+		Data:       deliveries,
+		Page:       *query.Page,
+		Limit:      *query.Limit,
+		Total:      total,
+		TotalPages: (total + *query.Limit - 1) / *query.Limit,
+	}, nil
+}
+
+// RecordDeliveryAttempt stores the outcome of one attempt at sending a
+// delivery. status stays pending while retries remain.
+func (r *WebhookRepository) RecordDeliveryAttempt(ctx context.Context, deliveryID uuid.UUID,
+	status webhook.DeliveryStatus, responseCode *int, responseBody, errMsg *string, duration time.Duration,
+) error {
+	stmt := `
+		UPDATE webhook_deliveries
+		SET
+			status = @status,
+			attempts = attempts + 1,
+			response_code = @response_code,
+			response_body = @response_body,
+			error = @error,
+			duration_ms = @duration_ms,
+			delivered_at = CASE
+				WHEN @status = 'succeeded' THEN NOW()
+				ELSE NULL
+			END
+		WHERE
+			id = @id
+	`
+
+	_, err := r.server.DB.Pool.Exec(ctx, stmt, pgx.NamedArgs{
+		"id":            deliveryID,
+		"status":        status,
+		"response_code": responseCode,
+		"response_body": responseBody,
+		"error":         errMsg,
+		"duration_ms":   duration.Milliseconds(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record attempt for delivery_id=%s: %w", deliveryID.String(), err)
+	}
+
+	return nil
+}
@@ -0,0 +1,241 @@
+package router_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uttam282005/tasker/internal/middleware"
+	"github.com/uttam282005/tasker/internal/model"
+	"github.com/uttam282005/tasker/internal/model/category"
+	"github.com/uttam282005/tasker/internal/model/comment"
+	"github.com/uttam282005/tasker/internal/model/todo"
+	testhelpers "github.com/uttam282005/tasker/internal/testing"
+)
+
+func TestRoutesRequireAuth(t *testing.T) {
+	api := testhelpers.SetupTestAPI(t)
+	id := uuid.NewString()
+
+	routes := []struct {
+		method string
+		path   string
+	}{
+		{http.MethodGet, "/api/v1/todos"},
+		{http.MethodPost, "/api/v1/todos"},
+		{http.MethodGet, "/api/v1/todos/stats"},
+		{http.MethodGet, "/api/v1/todos/" + id},
+		{http.MethodPut, "/api/v1/todos/" + id},
+		{http.MethodDelete, "/api/v1/todos/" + id},
+		{http.MethodGet, "/api/v1/todos/" + id + "/comments"},
+		{http.MethodPost, "/api/v1/todos/" + id + "/comments"},
+		{http.MethodPost, "/api/v1/todos/" + id + "/attachments"},
+		{http.MethodGet, "/api/v1/categories"},
+		{http.MethodPost, "/api/v1/categories"},
+		{http.MethodPut, "/api/v1/categories/" + id},
+		{http.MethodDelete, "/api/v1/categories/" + id},
+		{http.MethodGet, "/api/v1/comments/trash"},
+		{http.MethodPut, "/api/v1/comments/" + id},
+		{http.MethodDelete, "/api/v1/comments/" + id},
+	}
+
+	for _, route := range routes {
+		t.Run(route.method+" "+route.path, func(t *testing.T) {
+			rec := api.Request(t, route.method, route.path, "", nil)
+			assert.Equal(t, http.StatusUnauthorized, rec.Code, rec.Body.String())
+		})
+	}
+}
+
+func TestCategoryRoutes(t *testing.T) {
+	api := testhelpers.SetupTestAPI(t)
+	token := api.Auth.Token(t, "user_alice")
+
+	rec := api.Request(t, http.MethodPost, "/api/v1/categories", token, map[string]any{
+		"name":  "Work",
+		"color": "#ff0000",
+	})
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	created := testhelpers.DecodeJSON[category.Category](t, rec)
+	testhelpers.AssertValidUUID(t, created.ID)
+	assert.Equal(t, "Work", created.Name)
+	assert.Equal(t, "user_alice", created.UserID)
+
+	rec = api.Request(t, http.MethodGet, "/api/v1/categories", token, nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	list := testhelpers.DecodeJSON[model.CursorPaginatedResponse[category.Category]](t, rec)
+	require.Len(t, list.Data, 1)
+	assert.Equal(t, created.ID, list.Data[0].ID)
+
+	rec = api.Request(t, http.MethodPut, "/api/v1/categories/"+created.ID.String(), token, map[string]any{
+		"name": "Office",
+	})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	updated := testhelpers.DecodeJSON[category.Category](t, rec)
+	assert.Equal(t, "Office", updated.Name)
+	assert.Equal(t, created.Version+1, updated.Version)
+
+	// Other users can't see it
+	rec = api.Request(t, http.MethodGet, "/api/v1/categories/"+created.ID.String(), api.Auth.Token(t, "user_bob"), nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code, rec.Body.String())
+
+	rec = api.Request(t, http.MethodDelete, "/api/v1/categories/"+created.ID.String(), token, nil)
+	require.Equal(t, http.StatusNoContent, rec.Code, rec.Body.String())
+
+	rec = api.Request(t, http.MethodGet, "/api/v1/categories/"+created.ID.String(), token, nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code, rec.Body.String())
+}
+
+func TestTodoRoutes(t *testing.T) {
+	api := testhelpers.SetupTestAPI(t)
+	token := api.Auth.Token(t, "user_alice")
+
+	rec := api.Request(t, http.MethodPost, "/api/v1/categories", token, map[string]any{
+		"name":  "Home",
+		"color": "#00ff00",
+	})
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	categoryItem := testhelpers.DecodeJSON[category.Category](t, rec)
+
+	rec = api.Request(t, http.MethodPost, "/api/v1/todos", token, map[string]any{
+		"title":      "Water the plants",
+		"priority":   "high",
+		"categoryId": categoryItem.ID,
+	})
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	created := testhelpers.DecodeJSON[todo.Todo](t, rec)
+	testhelpers.AssertValidUUID(t, created.ID)
+	assert.Equal(t, "Water the plants", created.Title)
+	assert.Equal(t, todo.PriorityHigh, created.Priority)
+	testhelpers.AssertTimestampsValid(t, created)
+
+	rec = api.Request(t, http.MethodGet, "/api/v1/todos/"+created.ID.String(), token, nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.NotEmpty(t, rec.Header().Get("ETag"))
+	fetched := testhelpers.DecodeJSON[todo.PopulatedTodo](t, rec)
+	require.NotNil(t, fetched.Category)
+	assert.Equal(t, categoryItem.ID, fetched.Category.ID)
+
+	rec = api.Request(t, http.MethodGet, "/api/v1/todos", token, nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	list := testhelpers.DecodeJSON[model.CursorPaginatedResponse[todo.PopulatedTodo]](t, rec)
+	require.Len(t, list.Data, 1)
+	assert.Equal(t, created.ID, list.Data[0].ID)
+
+	rec = api.Request(t, http.MethodPut, "/api/v1/todos/"+created.ID.String(), token, map[string]any{
+		"status": "completed",
+	})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	updated := testhelpers.DecodeJSON[todo.Todo](t, rec)
+	assert.Equal(t, todo.StatusCompleted, updated.Status)
+	assert.NotNil(t, updated.CompletedAt)
+
+	rec = api.Request(t, http.MethodGet, "/api/v1/todos/stats", token, nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	stats := testhelpers.DecodeJSON[todo.TodoStats](t, rec)
+	assert.Equal(t, 1, stats.Total)
+	assert.Equal(t, 1, stats.Completed)
+
+	// Attachment routes answer for the todo too
+	rec = api.Request(t, http.MethodDelete, "/api/v1/todos/"+created.ID.String()+"/attachments/"+uuid.NewString(), token, nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code, rec.Body.String())
+
+	// Other users can't see it
+	rec = api.Request(t, http.MethodGet, "/api/v1/todos/"+created.ID.String(), api.Auth.Token(t, "user_bob"), nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code, rec.Body.String())
+
+	rec = api.Request(t, http.MethodDelete, "/api/v1/todos/"+created.ID.String(), token, nil)
+	require.Equal(t, http.StatusNoContent, rec.Code, rec.Body.String())
+
+	rec = api.Request(t, http.MethodGet, "/api/v1/todos/"+created.ID.String(), token, nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code, rec.Body.String())
+}
+
+func TestCommentRoutes(t *testing.T) {
+	api := testhelpers.SetupTestAPI(t)
+	token := api.Auth.Token(t, "user_alice")
+
+	rec := api.Request(t, http.MethodPost, "/api/v1/todos", token, map[string]any{
+		"title": "Plan the trip",
+	})
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	todoItem := testhelpers.DecodeJSON[todo.Todo](t, rec)
+	commentsPath := "/api/v1/todos/" + todoItem.ID.String() + "/comments"
+
+	rec = api.Request(t, http.MethodPost, commentsPath, token, map[string]any{
+		"content": "Book the flights first",
+	})
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	created := testhelpers.DecodeJSON[comment.Comment](t, rec)
+	assert.Equal(t, todoItem.ID, created.TodoID)
+	assert.Equal(t, "user_alice", created.UserID)
+
+	rec = api.Request(t, http.MethodGet, commentsPath, token, nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	comments := testhelpers.DecodeJSON[[]comment.Comment](t, rec)
+	require.Len(t, comments, 1)
+	assert.Equal(t, created.ID, comments[0].ID)
+
+	commentPath := "/api/v1/comments/" + created.ID.String()
+
+	rec = api.Request(t, http.MethodPut, commentPath, token, map[string]any{
+		"content": "Book the hotel first",
+	})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	updated := testhelpers.DecodeJSON[comment.Comment](t, rec)
+	assert.Equal(t, "Book the hotel first", updated.Content)
+
+	// Other users can't reach it
+	rec = api.Request(t, http.MethodPut, commentPath, api.Auth.Token(t, "user_bob"), map[string]any{
+		"content": "Hijacked",
+	})
+	assert.Equal(t, http.StatusNotFound, rec.Code, rec.Body.String())
+
+	rec = api.Request(t, http.MethodDelete, commentPath, token, nil)
+	require.Equal(t, http.StatusNoContent, rec.Code, rec.Body.String())
+
+	rec = api.Request(t, http.MethodGet, commentsPath, token, nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Empty(t, testhelpers.DecodeJSON[[]comment.Comment](t, rec))
+
+	rec = api.Request(t, http.MethodGet, "/api/v1/comments/trash", token, nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	trash := testhelpers.DecodeJSON[model.PaginatedResponse[comment.Comment]](t, rec)
+	require.Len(t, trash.Data, 1)
+	assert.Equal(t, created.ID, trash.Data[0].ID)
+
+	rec = api.Request(t, http.MethodPost, commentPath+"/restore", token, nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	rec = api.Request(t, http.MethodGet, commentsPath, token, nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Len(t, testhelpers.DecodeJSON[[]comment.Comment](t, rec), 1)
+}
+
+func TestOrganizationPermissions(t *testing.T) {
+	api := testhelpers.SetupTestAPI(t)
+
+	readOnly := api.Auth.OrgToken(t, "user_alice", "org_acme", middleware.PermissionTodosRead)
+	rec := api.Request(t, http.MethodPost, "/api/v1/todos", readOnly, map[string]any{
+		"title": "Ship the release",
+	})
+	assert.Equal(t, http.StatusForbidden, rec.Code, rec.Body.String())
+
+	writer := api.Auth.OrgToken(t, "user_alice", "org_acme", middleware.PermissionTodosRead, middleware.PermissionTodosCreate)
+	rec = api.Request(t, http.MethodPost, "/api/v1/todos", writer, map[string]any{
+		"title": "Ship the release",
+	})
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	created := testhelpers.DecodeJSON[todo.Todo](t, rec)
+	require.NotNil(t, created.OrgID)
+	assert.Equal(t, "org_acme", *created.OrgID)
+
+	// Every member of the organization sees its todos, and nobody outside it
+	rec = api.Request(t, http.MethodGet, "/api/v1/todos/"+created.ID.String(), api.Auth.OrgToken(t, "user_bob", "org_acme", middleware.PermissionTodosRead), nil)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	rec = api.Request(t, http.MethodGet, "/api/v1/todos/"+created.ID.String(), api.Auth.Token(t, "user_bob"), nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code, rec.Body.String())
+}
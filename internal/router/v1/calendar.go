@@ -0,0 +1,22 @@
+package v1
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/uttam282005/tasker/internal/handler"
+	"github.com/uttam282005/tasker/internal/middleware"
+)
+
+func registerCalendarRoutes(r *echo.Group, h *handler.CalendarHandler, auth *middleware.AuthMiddleware) {
+	calendar := r.Group("/calendar")
+
+	// Feed management
+	feeds := calendar.Group("/feeds")
+	feeds.Use(auth.RequireAuth, auth.RequirePermission(middleware.PermissionTodosRead))
+	feeds.POST("", h.CreateFeed)
+	feeds.GET("", h.GetFeeds)
+	feeds.PUT("/:id", h.UpdateFeed)
+	feeds.DELETE("/:id", h.DeleteFeed)
+
+	// Subscriptions, authenticated by the feed token in the path
+	calendar.GET("/:token", h.GetFeed)
+}
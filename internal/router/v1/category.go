@@ -0,0 +1,26 @@
+package v1
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/uttam282005/tasker/internal/handler"
+	"github.com/uttam282005/tasker/internal/middleware"
+)
+
+func registerCategoryRoutes(r *echo.Group, h *handler.CategoryHandler, auth *middleware.AuthMiddleware) {
+	// Category operations
+	categories := r.Group("/categories")
+	categories.Use(auth.RequireAuth)
+
+	// Collection operations
+	categories.POST("", h.CreateCategory, auth.RequirePermission(middleware.PermissionCategoriesCreate))
+	categories.GET("", h.GetCategories, auth.RequirePermission(middleware.PermissionCategoriesRead))
+	categories.GET("/trash", h.GetTrashedCategories, auth.RequirePermission(middleware.PermissionCategoriesRead))
+
+	// Individual category operations
+	dynamicCategory := categories.Group("/:id")
+	dynamicCategory.GET("", h.GetCategoryByID, auth.RequirePermission(middleware.PermissionCategoriesRead))
+	dynamicCategory.PUT("", h.UpdateCategory, auth.RequirePermission(middleware.PermissionCategoriesUpdate))
+	dynamicCategory.PATCH("", h.PatchCategory, auth.RequirePermission(middleware.PermissionCategoriesUpdate))
+	dynamicCategory.DELETE("", h.DeleteCategory, auth.RequirePermission(middleware.PermissionCategoriesDelete))
+	dynamicCategory.POST("/restore", h.RestoreCategory, auth.RequirePermission(middleware.PermissionCategoriesDelete))
+}
@@ -0,0 +1,22 @@
+package v1
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/uttam282005/tasker/internal/handler"
+	"github.com/uttam282005/tasker/internal/middleware"
+)
+
+func registerCommentRoutes(r *echo.Group, h *handler.CommentHandler, auth *middleware.AuthMiddleware) {
+	// Comment operations
+	comments := r.Group("/comments")
+	comments.Use(auth.RequireAuth)
+
+	// Collection operations
+	comments.GET("/trash", h.GetTrashedComments, auth.RequirePermission(middleware.PermissionTodosRead))
+
+	// Individual comment operations
+	dynamicComment := comments.Group("/:id")
+	dynamicComment.PUT("", h.UpdateComment, auth.RequirePermission(middleware.PermissionCommentsCreate))
+	dynamicComment.DELETE("", h.DeleteComment, auth.RequirePermission(middleware.PermissionCommentsCreate))
+	dynamicComment.POST("/restore", h.RestoreComment, auth.RequirePermission(middleware.PermissionCommentsCreate))
+}
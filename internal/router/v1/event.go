@@ -0,0 +1,11 @@
+package v1
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/uttam282005/tasker/internal/handler"
+	"github.com/uttam282005/tasker/internal/middleware"
+)
+
+func registerEventRoutes(r *echo.Group, h *handler.EventHandler, auth *middleware.AuthMiddleware) {
+	r.GET("/events", h.StreamEvents, auth.RequireAuth, auth.RequirePermission(middleware.PermissionTodosRead))
+}
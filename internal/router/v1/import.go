@@ -0,0 +1,17 @@
+package v1
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/uttam282005/tasker/internal/handler"
+	"github.com/uttam282005/tasker/internal/middleware"
+)
+
+func registerImportRoutes(r *echo.Group, h *handler.ImportHandler, auth *middleware.AuthMiddleware,
+	idempotency *middleware.IdempotencyMiddleware,
+) {
+	imports := r.Group("/import")
+	imports.Use(auth.RequireAuth)
+
+	imports.POST("", h.CreateImport, auth.RequirePermission(middleware.PermissionTodosCreate), idempotency.Idempotent)
+	imports.GET("/:id", h.GetImportByID, auth.RequirePermission(middleware.PermissionTodosRead))
+}
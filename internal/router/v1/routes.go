@@ -0,0 +1,42 @@
+package v1
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/uttam282005/tasker/internal/handler"
+	"github.com/uttam282005/tasker/internal/middleware"
+)
+
+func RegisterV1Routes(router *echo.Group, handlers *handler.Handlers, middleware *middleware.Middlewares) {
+	// Register todo routes
+	registerTodoRoutes(router, handlers.Todo, handlers.Comment, handlers.Activity, middleware.Auth, middleware.Idempotency)
+
+	// Register category routes
+	registerCategoryRoutes(router, handlers.Category, middleware.Auth)
+
+	// Register comment routes
+	registerCommentRoutes(router, handlers.Comment, middleware.Auth)
+
+	// Register search routes
+	registerSearchRoutes(router, handlers.Search, middleware.Auth)
+
+	// Register share routes
+	registerShareRoutes(router, handlers.Share, middleware.Auth)
+
+	// Register webhook routes
+	registerWebhookRoutes(router, handlers.Webhook, middleware.Auth)
+
+	// Register event stream routes
+	registerEventRoutes(router, handlers.Event, middleware.Auth)
+
+	// Register calendar feed routes
+	registerCalendarRoutes(router, handlers.Calendar, middleware.Auth)
+
+	// Register import routes
+	registerImportRoutes(router, handlers.Import, middleware.Auth, middleware.Idempotency)
+
+	// Register tag routes
+	registerTagRoutes(router, handlers.Tag, middleware.Auth)
+
+	// Register view routes
+	registerViewRoutes(router, handlers.View, middleware.Auth)
+}
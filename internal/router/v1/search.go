@@ -0,0 +1,11 @@
+package v1
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/uttam282005/tasker/internal/handler"
+	"github.com/uttam282005/tasker/internal/middleware"
+)
+
+func registerSearchRoutes(r *echo.Group, h *handler.SearchHandler, auth *middleware.AuthMiddleware) {
+	r.GET("/search", h.Search, auth.RequireAuth, auth.RequirePermission(middleware.PermissionTodosRead))
+}
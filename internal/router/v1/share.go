@@ -0,0 +1,27 @@
+package v1
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/uttam282005/tasker/internal/handler"
+	"github.com/uttam282005/tasker/internal/middleware"
+)
+
+func registerShareRoutes(r *echo.Group, h *handler.ShareHandler, auth *middleware.AuthMiddleware) {
+	// Granting access to a todo or a whole category
+	r.POST("/todos/:id/shares", h.ShareTodo, auth.RequireAuth)
+	r.GET("/todos/:id/shares", h.GetTodoShares, auth.RequireAuth)
+	r.POST("/categories/:id/shares", h.ShareCategory, auth.RequireAuth)
+	r.GET("/categories/:id/shares", h.GetCategoryShares, auth.RequireAuth)
+
+	// Share operations
+	shares := r.Group("/shares")
+	shares.Use(auth.RequireAuth)
+
+	// Collection operations
+	shares.GET("/with-me", h.GetSharedWithMe)
+
+	// Individual share operations
+	dynamicShare := shares.Group("/:id")
+	dynamicShare.PUT("", h.UpdateShare)
+	dynamicShare.DELETE("", h.DeleteShare)
+}
@@ -0,0 +1,18 @@
+package v1
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/uttam282005/tasker/internal/handler"
+	"github.com/uttam282005/tasker/internal/middleware"
+)
+
+func registerTagRoutes(r *echo.Group, h *handler.TagHandler, auth *middleware.AuthMiddleware) {
+	tags := r.Group("/tags")
+	tags.Use(auth.RequireAuth)
+
+	tags.POST("", h.CreateTag, auth.RequirePermission(middleware.PermissionTagsManage))
+	tags.GET("", h.GetTags, auth.RequirePermission(middleware.PermissionTodosRead))
+	tags.GET("/:id", h.GetTagByID, auth.RequirePermission(middleware.PermissionTodosRead))
+	tags.PUT("/:id", h.UpdateTag, auth.RequirePermission(middleware.PermissionTagsManage))
+	tags.DELETE("/:id", h.DeleteTag, auth.RequirePermission(middleware.PermissionTagsManage))
+}
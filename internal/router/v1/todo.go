@@ -0,0 +1,56 @@
+package v1
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/uttam282005/tasker/internal/handler"
+	"github.com/uttam282005/tasker/internal/middleware"
+)
+
+func registerTodoRoutes(r *echo.Group, h *handler.TodoHandler, ch *handler.CommentHandler,
+	ah *handler.ActivityHandler, auth *middleware.AuthMiddleware, idempotency *middleware.IdempotencyMiddleware,
+) {
+	// Todo operations
+	todos := r.Group("/todos")
+	todos.Use(auth.RequireAuth)
+
+	// Collection operations
+	todos.POST("", h.CreateTodo, auth.RequirePermission(middleware.PermissionTodosCreate), idempotency.Idempotent)
+	todos.GET("", h.GetTodos, auth.RequirePermission(middleware.PermissionTodosRead))
+	todos.GET("/stats", h.GetTodoStats, auth.RequirePermission(middleware.PermissionTodosRead))
+	todos.GET("/export", h.ExportTodos, auth.RequirePermission(middleware.PermissionTodosRead))
+	todos.GET("/trash", h.GetTrashedTodos, auth.RequirePermission(middleware.PermissionTodosRead))
+	todos.POST("/bulk", h.BulkUpdateTodos, auth.RequirePermission(middleware.PermissionTodosUpdate))
+
+	// Individual todo operations
+	dynamicTodo := todos.Group("/:id")
+	dynamicTodo.GET("", h.GetTodoByID, auth.RequirePermission(middleware.PermissionTodosRead))
+	dynamicTodo.PUT("", h.UpdateTodo, auth.RequirePermission(middleware.PermissionTodosUpdate))
+	dynamicTodo.PATCH("", h.PatchTodo, auth.RequirePermission(middleware.PermissionTodosUpdate))
+	dynamicTodo.DELETE("", h.DeleteTodo, auth.RequirePermission(middleware.PermissionTodosDelete))
+	dynamicTodo.POST("/move", h.MoveTodo, auth.RequirePermission(middleware.PermissionTodosUpdate))
+	dynamicTodo.POST("/restore", h.RestoreTodo, auth.RequirePermission(middleware.PermissionTodosDelete))
+
+	// Todo comments
+	todoComments := dynamicTodo.Group("/comments")
+	todoComments.POST("", ch.AddComment, auth.RequirePermission(middleware.PermissionCommentsCreate), idempotency.Idempotent)
+	todoComments.GET("", ch.GetCommentsByTodoID, auth.RequirePermission(middleware.PermissionTodosRead))
+
+	// Todo attachments
+	todoAttachments := dynamicTodo.Group("/attachments")
+	todoAttachments.POST("", h.UploadTodoAttachment, auth.RequirePermission(middleware.PermissionTodosUpdate), idempotency.Idempotent)
+	todoAttachments.DELETE("/:attachmentId", h.DeleteTodoAttachment, auth.RequirePermission(middleware.PermissionTodosUpdate))
+	todoAttachments.GET("/:attachmentId/download", h.GetAttachmentPresignedURL, auth.RequirePermission(middleware.PermissionTodosRead))
+
+	// Todo activity
+	dynamicTodo.GET("/activity", ah.GetTodoActivity, auth.RequirePermission(middleware.PermissionTodosRead))
+
+	// Todo dependencies
+	todoBlockers := dynamicTodo.Group("/blockers")
+	todoBlockers.POST("", h.AddTodoBlocker, auth.RequirePermission(middleware.PermissionTodosUpdate))
+	todoBlockers.DELETE("/:blockerId", h.RemoveTodoBlocker, auth.RequirePermission(middleware.PermissionTodosUpdate))
+
+	// Todo watchers
+	todoWatchers := dynamicTodo.Group("/watchers")
+	todoWatchers.POST("", h.AddTodoWatcher, auth.RequirePermission(middleware.PermissionTodosRead))
+	todoWatchers.DELETE("/:userId", h.RemoveTodoWatcher, auth.RequirePermission(middleware.PermissionTodosRead))
+}
@@ -0,0 +1,29 @@
+package v1
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/uttam282005/tasker/internal/handler"
+	"github.com/uttam282005/tasker/internal/middleware"
+)
+
+// registerViewRoutes registers saved views. A view only reads todos, so
+// creating and pinning views needs no more than reading todos does.
+func registerViewRoutes(r *echo.Group, h *handler.ViewHandler, auth *middleware.AuthMiddleware) {
+	views := r.Group("/views")
+	views.Use(auth.RequireAuth)
+
+	views.POST("", h.CreateView, auth.RequirePermission(middleware.PermissionTodosRead))
+	views.GET("", h.GetViews, auth.RequirePermission(middleware.PermissionTodosRead))
+
+	// Built-in views
+	views.GET("/system", h.GetSystemViews, auth.RequirePermission(middleware.PermissionTodosRead))
+	views.GET("/system/:key/todos", h.GetSystemViewTodos, auth.RequirePermission(middleware.PermissionTodosRead))
+
+	dynamicView := views.Group("/:id")
+	dynamicView.GET("", h.GetViewByID, auth.RequirePermission(middleware.PermissionTodosRead))
+	dynamicView.PUT("", h.UpdateView, auth.RequirePermission(middleware.PermissionTodosRead))
+	dynamicView.DELETE("", h.DeleteView, auth.RequirePermission(middleware.PermissionTodosRead))
+	dynamicView.GET("/todos", h.GetViewTodos, auth.RequirePermission(middleware.PermissionTodosRead))
+	dynamicView.POST("/pin", h.PinView, auth.RequirePermission(middleware.PermissionTodosRead))
+	dynamicView.DELETE("/pin", h.UnpinView, auth.RequirePermission(middleware.PermissionTodosRead))
+}
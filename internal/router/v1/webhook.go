@@ -0,0 +1,27 @@
+package v1
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/uttam282005/tasker/internal/handler"
+	"github.com/uttam282005/tasker/internal/middleware"
+)
+
+func registerWebhookRoutes(r *echo.Group, h *handler.WebhookHandler, auth *middleware.AuthMiddleware) {
+	// Webhook operations
+	webhooks := r.Group("/webhooks")
+	webhooks.Use(auth.RequireAuth, auth.RequirePermission(middleware.PermissionWebhooksManage))
+
+	// Collection operations
+	webhooks.POST("", h.CreateWebhook)
+	webhooks.GET("", h.GetWebhooks)
+
+	// Individual webhook operations
+	dynamicWebhook := webhooks.Group("/:id")
+	dynamicWebhook.GET("", h.GetWebhookByID)
+	dynamicWebhook.PUT("", h.UpdateWebhook)
+	dynamicWebhook.DELETE("", h.DeleteWebhook)
+
+	// Delivery log
+	dynamicWebhook.GET("/deliveries", h.GetDeliveries)
+	dynamicWebhook.POST("/deliveries/:deliveryId/redeliver", h.Redeliver)
+}
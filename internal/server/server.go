@@ -24,6 +24,7 @@ type Server struct {
 	Redis         *redis.Client
 	httpServer    *http.Server
 	Job           *job.JobService
+	done          chan struct{}
 }
 
 func New(cfg *config.Config, logger *zerolog.Logger, loggerService *loggerPkg.LoggerService) (*Server, error) {
@@ -67,6 +68,7 @@ func New(cfg *config.Config, logger *zerolog.Logger, loggerService *loggerPkg.Lo
 		DB:            db,
 		Redis:         redisClient,
 		Job:           jobService,
+		done:          make(chan struct{}),
 	}
 
 	// Start metrics collection
@@ -98,7 +100,15 @@ func (s *Server) Start() error {
 	return s.httpServer.ListenAndServe()
 }
 
+// Done is closed when the server starts shutting down, so long-lived
+// responses such as event streams can end instead of holding Shutdown up.
+func (s *Server) Done() <-chan struct{} {
+	return s.done
+}
+
 func (s *Server) Shutdown(ctx context.Context) error {
+	close(s.done)
+
 	if err := s.httpServer.Shutdown(ctx); err != nil {
 		return fmt.Errorf("failed to shutdown HTTP server: %w", err)
 	}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/uttam282005/tasker/internal/middleware"
+	"github.com/uttam282005/tasker/internal/model"
+	"github.com/uttam282005/tasker/internal/model/activity"
+	"github.com/uttam282005/tasker/internal/repository"
+	"github.com/uttam282005/tasker/internal/server"
+)
+
+type ActivityService struct {
+	server       *server.Server
+	activityRepo *repository.ActivityRepository
+	todoRepo     *repository.TodoRepository
+}
+
+func NewActivityService(server *server.Server, activityRepo *repository.ActivityRepository,
+	todoRepo *repository.TodoRepository,
+) *ActivityService {
+	return &ActivityService{
+		server:       server,
+		activityRepo: activityRepo,
+		todoRepo:     todoRepo,
+	}
+}
+
+// Record persists an audit entry on behalf of the authenticated user. It runs
+// after the mutation it describes has been written, so a failure is logged
+// instead of failing the request.
+func (s *ActivityService) Record(ctx echo.Context, entry *activity.Activity) {
+	logger := middleware.GetLogger(ctx)
+
+	entry.ActorID = middleware.GetUserID(ctx)
+
+	if _, err := s.activityRepo.CreateActivity(ctx.Request().Context(), entry); err != nil {
+		logger.Error().
+			Err(err).
+			Str("entity_type", string(entry.EntityType)).
+			Str("entity_id", entry.EntityID.String()).
+			Str("action", string(entry.Action)).
+			Msg("failed to record activity")
+	}
+}
+
+func (s *ActivityService) GetTodoActivity(ctx echo.Context, userID string,
+	query *activity.GetTodoActivityQuery,
+) (*model.PaginatedResponse[activity.Activity], error) {
+	logger := middleware.GetLogger(ctx)
+
+	// Verify todo exists and is visible to the user
+	_, err := s.todoRepo.CheckTodoExists(ctx.Request().Context(), userID, query.TodoID)
+	if err != nil {
+		logger.Error().Err(err).Msg("todo validation failed")
+		return nil, err
+	}
+
+	result, err := s.activityRepo.GetTodoActivity(ctx.Request().Context(), userID, query)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch todo activity")
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -3,12 +3,20 @@ package service
 import (
 	"context"
 	"fmt"
+	"time"
+
 	"github.com/uttam282005/tasker/internal/server"
 
 	"github.com/clerk/clerk-sdk-go/v2"
+	clerkMembership "github.com/clerk/clerk-sdk-go/v2/organizationmembership"
 	clerkUser "github.com/clerk/clerk-sdk-go/v2/user"
 )
 
+// orgMembershipTTL is how long a membership lookup is cached, and so how long
+// a member who left an organization keeps access through things that don't
+// carry a session, like calendar feeds.
+const orgMembershipTTL = 5 * time.Minute
+
 type AuthService struct {
 	server *server.Server
 }
@@ -38,3 +46,33 @@ func (s *AuthService) GetUserEmail(ctx context.Context, userID string) (string,
 
 	return user.EmailAddresses[0].EmailAddress, nil
 }
+
+// IsOrgMember reports whether the user still belongs to the organization.
+// Answers are cached in Redis for orgMembershipTTL; without Redis, Clerk is
+// asked every time.
+func (s *AuthService) IsOrgMember(ctx context.Context, orgID, userID string) (bool, error) {
+	key := "org_member:" + orgID + ":" + userID
+
+	if cached, err := s.server.Redis.Get(ctx, key).Result(); err == nil {
+		return cached == "1", nil
+	}
+
+	memberships, err := clerkMembership.List(ctx, &clerkMembership.ListParams{
+		OrganizationID: orgID,
+		UserIDs:        []string{userID},
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to list organization memberships from Clerk: %w", err)
+	}
+
+	member := memberships.TotalCount > 0
+	value := "0"
+	if member {
+		value = "1"
+	}
+	if err := s.server.Redis.Set(ctx, key, value, orgMembershipTTL).Err(); err != nil {
+		s.server.Logger.Warn().Err(err).Str("org_id", orgID).Msg("failed to cache organization membership")
+	}
+
+	return member, nil
+}
@@ -0,0 +1,369 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"github.com/uttam282005/tasker/internal/errs"
+	"github.com/uttam282005/tasker/internal/lib/ical"
+	"github.com/uttam282005/tasker/internal/lib/workspace"
+	"github.com/uttam282005/tasker/internal/middleware"
+	"github.com/uttam282005/tasker/internal/model/calendar"
+	"github.com/uttam282005/tasker/internal/model/todo"
+	"github.com/uttam282005/tasker/internal/repository"
+	"github.com/uttam282005/tasker/internal/server"
+)
+
+// calendarRefreshInterval is how often subscribed calendars are asked to
+// fetch a feed again.
+const calendarRefreshInterval = "PT1H"
+
+type CalendarService struct {
+	server       *server.Server
+	calendarRepo *repository.CalendarRepository
+	categoryRepo *repository.CategoryRepository
+	authService  *AuthService
+}
+
+func NewCalendarService(server *server.Server, calendarRepo *repository.CalendarRepository,
+	categoryRepo *repository.CategoryRepository, authService *AuthService,
+) *CalendarService {
+	return &CalendarService{
+		server:       server,
+		calendarRepo: calendarRepo,
+		categoryRepo: categoryRepo,
+		authService:  authService,
+	}
+}
+
+// newCalendarToken returns a random feed token and the hash it is stored as.
+func newCalendarToken() (string, string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	token := "cal_" + hex.EncodeToString(b)
+	return token, hashCalendarToken(token), nil
+}
+
+func hashCalendarToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// validateFeedCategories checks the user can see every category a feed is
+// limited to.
+func (s *CalendarService) validateFeedCategories(ctx echo.Context, userID string, categoryIDs []uuid.UUID) error {
+	for _, categoryID := range categoryIDs {
+		ok, err := s.categoryRepo.CanAccessCategory(ctx.Request().Context(), userID, categoryID)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			code := "CATEGORY_NOT_FOUND"
+			return errs.NewBadRequestError("Category not found", false, &code,
+				[]errs.FieldError{{Field: "categoryIds", Error: "contains an unknown category " + categoryID.String()}}, nil)
+		}
+	}
+	return nil
+}
+
+func (s *CalendarService) CreateFeed(ctx echo.Context, userID string,
+	payload *calendar.CreateFeedPayload,
+) (*calendar.CreatedFeed, error) {
+	logger := middleware.GetLogger(ctx)
+
+	if err := s.validateFeedCategories(ctx, userID, payload.CategoryIDs); err != nil {
+		logger.Error().Err(err).Msg("category validation failed")
+		return nil, err
+	}
+
+	token, tokenHash, err := newCalendarToken()
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to generate calendar feed token")
+		return nil, errors.Wrap(err, "failed to generate calendar feed token")
+	}
+
+	feed, err := s.calendarRepo.CreateFeed(ctx.Request().Context(), userID, payload, tokenHash)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to create calendar feed")
+		return nil, err
+	}
+
+	// Business event log
+	eventLogger := middleware.GetLogger(ctx)
+	eventLogger.Info().
+		Str("event", "calendar_feed_created").
+		Str("feed_id", feed.ID.String()).
+		Str("component", string(feed.Component)).
+		Msg("Calendar feed created successfully")
+
+	return &calendar.CreatedFeed{
+		Feed:  *feed,
+		Token: token,
+		URL:   ctx.Scheme() + "://" + ctx.Request().Host + "/api/v1/calendar/" + token + ".ics",
+	}, nil
+}
+
+func (s *CalendarService) GetFeeds(ctx echo.Context, userID string) ([]calendar.Feed, error) {
+	logger := middleware.GetLogger(ctx)
+
+	feeds, err := s.calendarRepo.GetFeeds(ctx.Request().Context(), userID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch calendar feeds")
+		return nil, err
+	}
+
+	return feeds, nil
+}
+
+func (s *CalendarService) UpdateFeed(ctx echo.Context, userID string,
+	payload *calendar.UpdateFeedPayload,
+) (*calendar.Feed, error) {
+	logger := middleware.GetLogger(ctx)
+
+	if err := s.validateFeedCategories(ctx, userID, payload.CategoryIDs); err != nil {
+		logger.Error().Err(err).Msg("category validation failed")
+		return nil, err
+	}
+
+	feed, err := s.calendarRepo.UpdateFeed(ctx.Request().Context(), userID, payload)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to update calendar feed")
+		return nil, err
+	}
+
+	// Business event log
+	eventLogger := middleware.GetLogger(ctx)
+	eventLogger.Info().
+		Str("event", "calendar_feed_updated").
+		Str("feed_id", feed.ID.String()).
+		Msg("Calendar feed updated successfully")
+
+	return feed, nil
+}
+
+// DeleteFeed revokes the feed's token; calendars subscribed to it get 404
+// from then on.
+func (s *CalendarService) DeleteFeed(ctx echo.Context, userID string, feedID uuid.UUID) error {
+	logger := middleware.GetLogger(ctx)
+
+	if err := s.calendarRepo.DeleteFeed(ctx.Request().Context(), userID, feedID); err != nil {
+		logger.Error().Err(err).Msg("failed to delete calendar feed")
+		return err
+	}
+
+	// Business event log
+	eventLogger := middleware.GetLogger(ctx)
+	eventLogger.Info().
+		Str("event", "calendar_feed_deleted").
+		Str("feed_id", feedID.String()).
+		Msg("Calendar feed deleted successfully")
+
+	return nil
+}
+
+// GetFeedByToken returns the feed a subscription URL's token belongs to. An
+// organization's feed stops working once its owner leaves the organization.
+func (s *CalendarService) GetFeedByToken(ctx echo.Context, token string) (*calendar.Feed, error) {
+	logger := middleware.GetLogger(ctx)
+
+	token = strings.TrimSuffix(token, ".ics")
+
+	feed, err := s.calendarRepo.GetFeedByTokenHash(ctx.Request().Context(), hashCalendarToken(token))
+	if err != nil {
+		logger.Warn().Err(err).Msg("calendar feed lookup failed")
+		return nil, err
+	}
+
+	if feed.OrgID != nil {
+		member, err := s.authService.IsOrgMember(ctx.Request().Context(), *feed.OrgID, feed.UserID)
+		if err != nil {
+			logger.Error().Err(err).Str("feed_id", feed.ID.String()).Msg("failed to check calendar feed owner's membership")
+			return nil, err
+		}
+		if !member {
+			logger.Warn().Str("feed_id", feed.ID.String()).Msg("calendar feed owner left the organization")
+			code := "CALENDAR_FEED_NOT_FOUND"
+			return nil, errs.NewNotFoundError("calendar feed not found", false, &code)
+		}
+	}
+
+	return feed, nil
+}
+
+// WriteFeed writes the feed's todos to w as an iCalendar document. The todos
+// are read as the feed's owner, in the workspace the feed was created in.
+func (s *CalendarService) WriteFeed(ctx echo.Context, feed *calendar.Feed, w io.Writer) error {
+	logger := middleware.GetLogger(ctx)
+
+	requestCtx := ctx.Request().Context()
+	if feed.OrgID != nil {
+		requestCtx = workspace.WithOrgID(requestCtx, *feed.OrgID)
+	}
+
+	cal := ical.NewWriter(w)
+	started := false
+	count := 0
+
+	err := s.calendarRepo.GetFeedTodos(requestCtx, feed, func(t *calendar.Todo) error {
+		if !started {
+			writeCalendarHeader(cal, feed)
+			started = true
+		}
+		writeCalendarTodo(cal, feed.Component, t)
+		count++
+		return cal.Err()
+	})
+	if err == nil {
+		if !started {
+			writeCalendarHeader(cal, feed)
+		}
+		cal.End("VCALENDAR")
+		err = cal.Err()
+	}
+	if err != nil {
+		logger.Error().Err(err).Str("feed_id", feed.ID.String()).Msg("failed to write calendar feed")
+		return err
+	}
+
+	// Business event log
+	eventLogger := middleware.GetLogger(ctx)
+	eventLogger.Info().
+		Str("event", "calendar_feed_fetched").
+		Str("feed_id", feed.ID.String()).
+		Int("count", count).
+		Msg("Calendar feed fetched successfully")
+
+	return nil
+}
+
+func writeCalendarHeader(cal *ical.Writer, feed *calendar.Feed) {
+	cal.Begin("VCALENDAR")
+	cal.Property("VERSION", "2.0")
+	cal.Property("PRODID", "-//Tasker//Tasker//EN")
+	cal.Property("CALSCALE", "GREGORIAN")
+	cal.Property("METHOD", "PUBLISH")
+	cal.Text("X-WR-CALNAME", feed.Name)
+	cal.Property("REFRESH-INTERVAL;VALUE=DURATION", calendarRefreshInterval)
+	cal.Property("X-PUBLISHED-TTL", calendarRefreshInterval)
+}
+
+// writeCalendarTodo writes a todo as a VTODO due at its due date, or as a
+// VEVENT starting then.
+func writeCalendarTodo(cal *ical.Writer, component calendar.Component, t *calendar.Todo) {
+	name := "VEVENT"
+	if component == calendar.ComponentTodo {
+		name = "VTODO"
+	}
+
+	cal.Begin(name)
+	cal.Property("UID", t.ID.String()+"@tasker")
+	cal.Time("DTSTAMP", t.UpdatedAt)
+	cal.Time("CREATED", t.CreatedAt)
+	cal.Time("LAST-MODIFIED", t.UpdatedAt)
+	cal.Property("SEQUENCE", strconv.Itoa(t.Version))
+	cal.Text("SUMMARY", t.Title)
+	if t.Description != nil && *t.Description != "" {
+		cal.Text("DESCRIPTION", *t.Description)
+	}
+	cal.Property("PRIORITY", calendarPriority(t.Priority))
+	if t.CategoryName != nil {
+		cal.Text("CATEGORIES", *t.CategoryName)
+	}
+
+	if component == calendar.ComponentTodo {
+		cal.Time("DUE", *t.DueDate)
+		cal.Property("STATUS", todoCalendarStatus(t.Status))
+		if t.CompletedAt != nil {
+			cal.Time("COMPLETED", *t.CompletedAt)
+			cal.Property("PERCENT-COMPLETE", "100")
+		}
+	} else {
+		cal.Time("DTSTART", *t.DueDate)
+		cal.Property("STATUS", eventCalendarStatus(t.Status))
+	}
+
+	if trigger, value, ok := calendarAlarmTrigger(component, t); ok {
+		cal.Begin("VALARM")
+		cal.Property("ACTION", "DISPLAY")
+		cal.Text("DESCRIPTION", t.Title)
+		cal.Property(trigger, value)
+		cal.End("VALARM")
+	}
+
+	cal.End(name)
+}
+
+// calendarAlarmTrigger returns the TRIGGER property for the todo's reminder,
+// which is either a time or a duration before the due date, such as PT15M.
+// Done todos and reminders in neither form get no alarm.
+func calendarAlarmTrigger(component calendar.Component, t *calendar.Todo) (string, string, bool) {
+	if t.Metadata == nil || t.Metadata.Reminder == nil {
+		return "", "", false
+	}
+	if t.Status == todo.StatusCompleted || t.Status == todo.StatusArchived {
+		return "", "", false
+	}
+
+	reminder := strings.TrimSpace(*t.Metadata.Reminder)
+
+	if at, err := time.Parse(time.RFC3339, reminder); err == nil {
+		return "TRIGGER;VALUE=DATE-TIME", ical.FormatTime(at), true
+	}
+
+	if !ical.IsDuration(reminder) {
+		return "", "", false
+	}
+	if !strings.HasPrefix(reminder, "-") && !strings.HasPrefix(reminder, "+") {
+		reminder = "-" + reminder
+	}
+
+	// Relative triggers count from DTSTART unless told otherwise, and a
+	// VTODO only has DUE
+	if component == calendar.ComponentTodo {
+		return "TRIGGER;RELATED=END", reminder, true
+	}
+	return "TRIGGER", reminder, true
+}
+
+func calendarPriority(priority todo.Priority) string {
+	switch priority {
+	case todo.PriorityHigh:
+		return "1"
+	case todo.PriorityLow:
+		return "9"
+	default:
+		return "5"
+	}
+}
+
+func todoCalendarStatus(status todo.Status) string {
+	switch status {
+	case todo.StatusCompleted:
+		return "COMPLETED"
+	case todo.StatusArchived:
+		return "CANCELLED"
+	default:
+		return "NEEDS-ACTION"
+	}
+}
+
+func eventCalendarStatus(status todo.Status) string {
+	switch status {
+	case todo.StatusDraft:
+		return "TENTATIVE"
+	case todo.StatusArchived:
+		return "CANCELLED"
+	default:
+		return "CONFIRMED"
+	}
+}
@@ -3,22 +3,29 @@ package service
 import (
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
+	"github.com/uttam282005/tasker/internal/errs"
+	"github.com/uttam282005/tasker/internal/lib/etag"
 	"github.com/uttam282005/tasker/internal/middleware"
 	"github.com/uttam282005/tasker/internal/model"
+	"github.com/uttam282005/tasker/internal/model/activity"
 	"github.com/uttam282005/tasker/internal/model/category"
 	"github.com/uttam282005/tasker/internal/repository"
 	"github.com/uttam282005/tasker/internal/server"
 )
 
 type CategoryService struct {
-	server       *server.Server
-	categoryRepo *repository.CategoryRepository
+	server          *server.Server
+	categoryRepo    *repository.CategoryRepository
+	activityService *ActivityService
 }
 
-func NewCategoryService(server *server.Server, categoryRepo *repository.CategoryRepository) *CategoryService {
+func NewCategoryService(server *server.Server, categoryRepo *repository.CategoryRepository,
+	activityService *ActivityService,
+) *CategoryService {
 	return &CategoryService{
-		server:       server,
-		categoryRepo: categoryRepo,
+		server:          server,
+		categoryRepo:    categoryRepo,
+		activityService: activityService,
 	}
 }
 
@@ -33,6 +40,14 @@ func (s *CategoryService) CreateCategory(ctx echo.Context, userID string,
 		return nil, err
 	}
 
+	s.activityService.Record(ctx, &activity.Activity{
+		UserID:     userID,
+		EntityType: activity.EntityTypeCategory,
+		EntityID:   categoryItem.ID,
+		Action:     activity.ActionCreated,
+		Changes:    activity.Diff(nil, categoryItem),
+	})
+
 	// Business event log
 	eventLogger := middleware.GetLogger(ctx)
 	eventLogger.Info().
@@ -47,7 +62,7 @@ func (s *CategoryService) CreateCategory(ctx echo.Context, userID string,
 
 func (s *CategoryService) GetCategories(ctx echo.Context, userID string,
 	query *category.GetCategoriesQuery,
-) (*model.PaginatedResponse[category.Category], error) {
+) (*model.CursorPaginatedResponse[category.Category], error) {
 	logger := middleware.GetLogger(ctx)
 
 	categories, err := s.categoryRepo.GetCategories(ctx.Request().Context(), userID, query)
@@ -76,12 +91,39 @@ func (s *CategoryService) UpdateCategory(ctx echo.Context, userID string, catego
 ) (*category.Category, error) {
 	logger := middleware.GetLogger(ctx)
 
-	categoryItem, err := s.categoryRepo.UpdateCategory(ctx.Request().Context(), userID, categoryID, payload)
+	currentCategory, err := s.categoryRepo.GetCategoryByID(ctx.Request().Context(), userID, categoryID)
+	if err != nil {
+		logger.Error().Err(err).Msg("category validation failed")
+		return nil, err
+	}
+
+	// Shared categories stay under their owner's control
+	if currentCategory.OrgID == nil && currentCategory.UserID != userID {
+		err := errs.NewForbiddenError("Only the owner can change a category", false)
+		logger.Warn().Msg("category belongs to another user")
+		return nil, err
+	}
+
+	if err := etag.Check(payload.IfMatch, currentCategory); err != nil {
+		logger.Warn().Msg("category changed since the client read it")
+		return nil, err
+	}
+
+	version := etag.ExpectedVersion(payload.IfMatch, currentCategory)
+	categoryItem, err := s.categoryRepo.UpdateCategory(ctx.Request().Context(), userID, categoryID, payload, version)
 	if err != nil {
 		logger.Error().Err(err).Msg("failed to update category")
 		return nil, err
 	}
 
+	s.activityService.Record(ctx, &activity.Activity{
+		UserID:     userID,
+		EntityType: activity.EntityTypeCategory,
+		EntityID:   categoryItem.ID,
+		Action:     activity.ActionUpdated,
+		Changes:    activity.Diff(currentCategory, categoryItem),
+	})
+
 	// Business event log
 	eventLogger := middleware.GetLogger(ctx)
 	eventLogger.Info().
@@ -93,15 +135,42 @@ func (s *CategoryService) UpdateCategory(ctx echo.Context, userID string, catego
 	return categoryItem, nil
 }
 
-func (s *CategoryService) DeleteCategory(ctx echo.Context, userID string, categoryID uuid.UUID) error {
+func (s *CategoryService) DeleteCategory(ctx echo.Context, userID string, categoryID uuid.UUID, ifMatch *string) error {
 	logger := middleware.GetLogger(ctx)
 
-	err := s.categoryRepo.DeleteCategory(ctx.Request().Context(), userID, categoryID)
+	currentCategory, err := s.categoryRepo.GetCategoryByID(ctx.Request().Context(), userID, categoryID)
+	if err != nil {
+		logger.Error().Err(err).Msg("category validation failed")
+		return err
+	}
+
+	// Shared categories stay under their owner's control
+	if currentCategory.OrgID == nil && currentCategory.UserID != userID {
+		err := errs.NewForbiddenError("Only the owner can delete a category", false)
+		logger.Warn().Msg("category belongs to another user")
+		return err
+	}
+
+	if err := etag.Check(ifMatch, currentCategory); err != nil {
+		logger.Warn().Msg("category changed since the client read it")
+		return err
+	}
+
+	version := etag.ExpectedVersion(ifMatch, currentCategory)
+	err = s.categoryRepo.DeleteCategory(ctx.Request().Context(), userID, categoryID, version)
 	if err != nil {
 		logger.Error().Err(err).Msg("failed to delete category")
 		return err
 	}
 
+	s.activityService.Record(ctx, &activity.Activity{
+		UserID:     userID,
+		EntityType: activity.EntityTypeCategory,
+		EntityID:   currentCategory.ID,
+		Action:     activity.ActionDeleted,
+		Changes:    activity.Diff(currentCategory, nil),
+	})
+
 	// Business event log
 	eventLogger := middleware.GetLogger(ctx)
 	eventLogger.Info().
@@ -111,3 +180,50 @@ func (s *CategoryService) DeleteCategory(ctx echo.Context, userID string, catego
 
 	return nil
 }
+
+func (s *CategoryService) GetTrashedCategories(ctx echo.Context, userID string,
+	query *category.GetTrashedCategoriesQuery,
+) (*model.PaginatedResponse[category.Category], error) {
+	logger := middleware.GetLogger(ctx)
+
+	categories, err := s.categoryRepo.GetTrashedCategories(ctx.Request().Context(), userID, query)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch trashed categories")
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+func (s *CategoryService) RestoreCategory(ctx echo.Context, userID string, categoryID uuid.UUID) (*category.Category, error) {
+	logger := middleware.GetLogger(ctx)
+
+	trashedCategory, err := s.categoryRepo.GetTrashedCategory(ctx.Request().Context(), userID, categoryID)
+	if err != nil {
+		logger.Error().Err(err).Msg("trashed category validation failed")
+		return nil, err
+	}
+
+	categoryItem, err := s.categoryRepo.RestoreCategory(ctx.Request().Context(), userID, categoryID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to restore category")
+		return nil, err
+	}
+
+	s.activityService.Record(ctx, &activity.Activity{
+		UserID:     userID,
+		EntityType: activity.EntityTypeCategory,
+		EntityID:   categoryItem.ID,
+		Action:     activity.ActionRestored,
+		Changes:    activity.Diff(trashedCategory, categoryItem),
+	})
+
+	// Business event log
+	eventLogger := middleware.GetLogger(ctx)
+	eventLogger.Info().
+		Str("event", "category_restored").
+		Str("category_id", categoryItem.ID.String()).
+		Msg("Category restored successfully")
+
+	return categoryItem, nil
+}
@@ -3,23 +3,42 @@ package service
 import (
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
+	"github.com/uttam282005/tasker/internal/errs"
+	"github.com/uttam282005/tasker/internal/lib/etag"
+	"github.com/uttam282005/tasker/internal/lib/job"
 	"github.com/uttam282005/tasker/internal/middleware"
+	"github.com/uttam282005/tasker/internal/model"
+	"github.com/uttam282005/tasker/internal/model/activity"
 	"github.com/uttam282005/tasker/internal/model/comment"
+	"github.com/uttam282005/tasker/internal/model/event"
+	"github.com/uttam282005/tasker/internal/model/share"
+	"github.com/uttam282005/tasker/internal/model/webhook"
 	"github.com/uttam282005/tasker/internal/repository"
 	"github.com/uttam282005/tasker/internal/server"
 )
 
 type CommentService struct {
-	server      *server.Server
-	commentRepo *repository.CommentRepository
-	todoRepo    *repository.TodoRepository
+	server              *server.Server
+	commentRepo         *repository.CommentRepository
+	todoRepo            *repository.TodoRepository
+	activityService     *ActivityService
+	notificationService *NotificationService
+	webhookService      *WebhookService
+	eventService        *EventService
 }
 
-func NewCommentService(server *server.Server, commentRepo *repository.CommentRepository, todoRepo *repository.TodoRepository) *CommentService {
+func NewCommentService(server *server.Server, commentRepo *repository.CommentRepository, todoRepo *repository.TodoRepository,
+	activityService *ActivityService, notificationService *NotificationService, webhookService *WebhookService,
+	eventService *EventService,
+) *CommentService {
 	return &CommentService{
-		server:      server,
-		commentRepo: commentRepo,
-		todoRepo:    todoRepo,
+		server:              server,
+		commentRepo:         commentRepo,
+		todoRepo:            todoRepo,
+		activityService:     activityService,
+		notificationService: notificationService,
+		webhookService:      webhookService,
+		eventService:        eventService,
 	}
 }
 
@@ -28,8 +47,8 @@ func (s *CommentService) AddComment(ctx echo.Context, userID string, todoID uuid
 ) (*comment.Comment, error) {
 	logger := middleware.GetLogger(ctx)
 
-	// Validate todo exists and belongs to user
-	_, err := s.todoRepo.CheckTodoExists(ctx.Request().Context(), userID, todoID)
+	// Validate todo exists and the user may comment on it
+	todoItem, err := authorizeTodo(ctx, s.todoRepo, userID, todoID, share.RoleCommenter)
 	if err != nil {
 		logger.Error().Err(err).Msg("todo validation failed")
 		return nil, err
@@ -41,6 +60,19 @@ func (s *CommentService) AddComment(ctx echo.Context, userID string, todoID uuid
 		return nil, err
 	}
 
+	s.activityService.Record(ctx, &activity.Activity{
+		TodoID:     &commentItem.TodoID,
+		UserID:     todoItem.UserID,
+		EntityType: activity.EntityTypeComment,
+		EntityID:   commentItem.ID,
+		Action:     activity.ActionCreated,
+		Changes:    activity.Diff(nil, commentItem),
+	})
+
+	s.notificationService.NotifyWatchers(ctx, todoItem, job.WatcherEventCommented, nil)
+	s.webhookService.Dispatch(ctx, todoItem, webhook.EventCommentAdded, commentItem)
+	s.eventService.Publish(ctx, todoItem, event.TypeCommentAdded, commentItem)
+
 	// Business event log
 	eventLogger := middleware.GetLogger(ctx)
 	eventLogger.Info().
@@ -55,7 +87,7 @@ func (s *CommentService) AddComment(ctx echo.Context, userID string, todoID uuid
 func (s *CommentService) GetCommentsByTodoID(ctx echo.Context, userID string, todoID uuid.UUID) ([]comment.Comment, error) {
 	logger := middleware.GetLogger(ctx)
 
-	// Validate todo exists and belongs to user
+	// Validate todo exists and is visible to the user
 	_, err := s.todoRepo.CheckTodoExists(ctx.Request().Context(), userID, todoID)
 	if err != nil {
 		logger.Error().Err(err).Msg("todo validation failed")
@@ -71,22 +103,53 @@ func (s *CommentService) GetCommentsByTodoID(ctx echo.Context, userID string, to
 	return comments, nil
 }
 
-func (s *CommentService) UpdateComment(ctx echo.Context, userID string, commentID uuid.UUID, content string) (*comment.Comment, error) {
+func (s *CommentService) UpdateComment(ctx echo.Context, userID string, commentID uuid.UUID, content string,
+	ifMatch *string,
+) (*comment.Comment, error) {
 	logger := middleware.GetLogger(ctx)
 
-	// Validate comment exists and belongs to user
-	_, err := s.commentRepo.GetCommentByID(ctx.Request().Context(), userID, commentID)
+	// Validate comment exists and was written by the user
+	currentComment, err := s.commentRepo.GetCommentByID(ctx.Request().Context(), userID, commentID)
 	if err != nil {
 		logger.Error().Err(err).Msg("comment validation failed")
 		return nil, err
 	}
 
-	commentItem, err := s.commentRepo.UpdateComment(ctx.Request().Context(), userID, commentID, content)
+	if currentComment.UserID != userID {
+		err := errs.NewForbiddenError("Only the author can edit a comment", false)
+		logger.Warn().Msg("comment belongs to another user")
+		return nil, err
+	}
+
+	if err := etag.Check(ifMatch, currentComment); err != nil {
+		logger.Warn().Msg("comment changed since the client read it")
+		return nil, err
+	}
+
+	todoItem, err := s.todoRepo.CheckTodoExists(ctx.Request().Context(), userID, currentComment.TodoID)
+	if err != nil {
+		logger.Error().Err(err).Msg("todo validation failed")
+		return nil, err
+	}
+
+	version := etag.ExpectedVersion(ifMatch, currentComment)
+	commentItem, err := s.commentRepo.UpdateComment(ctx.Request().Context(), userID, commentID, content, version)
 	if err != nil {
 		logger.Error().Err(err).Msg("failed to update comment")
 		return nil, err
 	}
 
+	s.activityService.Record(ctx, &activity.Activity{
+		TodoID:     &commentItem.TodoID,
+		UserID:     todoItem.UserID,
+		EntityType: activity.EntityTypeComment,
+		EntityID:   commentItem.ID,
+		Action:     activity.ActionUpdated,
+		Changes:    activity.Diff(currentComment, commentItem),
+	})
+
+	s.eventService.Publish(ctx, todoItem, event.TypeCommentUpdated, commentItem)
+
 	// Business event log
 	eventLogger := middleware.GetLogger(ctx)
 	eventLogger.Info().
@@ -97,22 +160,54 @@ func (s *CommentService) UpdateComment(ctx echo.Context, userID string, commentI
 	return commentItem, nil
 }
 
-func (s *CommentService) DeleteComment(ctx echo.Context, userID string, commentID uuid.UUID) error {
+func (s *CommentService) DeleteComment(ctx echo.Context, userID string, commentID uuid.UUID, ifMatch *string) error {
 	logger := middleware.GetLogger(ctx)
 
-	// Validate comment exists and belongs to user
-	_, err := s.commentRepo.GetCommentByID(ctx.Request().Context(), userID, commentID)
+	currentComment, err := s.commentRepo.GetCommentByID(ctx.Request().Context(), userID, commentID)
 	if err != nil {
 		logger.Error().Err(err).Msg("comment validation failed")
 		return err
 	}
 
-	err = s.commentRepo.DeleteComment(ctx.Request().Context(), userID, commentID)
+	// Authors can delete their own comments. Other people's are moderated by
+	// the todo's owner or, in an organization, where every member owns every
+	// todo, by members allowed to delete comments.
+	todoItem, role, err := s.todoRepo.GetTodoWithRole(ctx.Request().Context(), userID, currentComment.TodoID)
+	if err != nil {
+		logger.Error().Err(err).Msg("todo validation failed")
+		return err
+	}
+
+	moderate := currentComment.UserID != userID
+	if moderate && (role != share.RoleOwner || !middleware.HasPermission(ctx, middleware.PermissionCommentsDelete)) {
+		err := errs.NewForbiddenError("Only the author or a moderator can delete a comment", false)
+		logger.Warn().Msg("comment belongs to another user")
+		return err
+	}
+
+	if err := etag.Check(ifMatch, currentComment); err != nil {
+		logger.Warn().Msg("comment changed since the client read it")
+		return err
+	}
+
+	version := etag.ExpectedVersion(ifMatch, currentComment)
+	err = s.commentRepo.DeleteComment(ctx.Request().Context(), userID, commentID, moderate, version)
 	if err != nil {
 		logger.Error().Err(err).Msg("failed to delete comment")
 		return err
 	}
 
+	s.activityService.Record(ctx, &activity.Activity{
+		TodoID:     &currentComment.TodoID,
+		UserID:     todoItem.UserID,
+		EntityType: activity.EntityTypeComment,
+		EntityID:   currentComment.ID,
+		Action:     activity.ActionDeleted,
+		Changes:    activity.Diff(currentComment, nil),
+	})
+
+	s.eventService.Publish(ctx, todoItem, event.TypeCommentDeleted, currentComment)
+
 	// Business event log
 	eventLogger := middleware.GetLogger(ctx)
 	eventLogger.Info().
@@ -122,3 +217,60 @@ func (s *CommentService) DeleteComment(ctx echo.Context, userID string, commentI
 
 	return nil
 }
+
+func (s *CommentService) GetTrashedComments(ctx echo.Context, userID string,
+	query *comment.GetTrashedCommentsQuery,
+) (*model.PaginatedResponse[comment.Comment], error) {
+	logger := middleware.GetLogger(ctx)
+
+	comments, err := s.commentRepo.GetTrashedComments(ctx.Request().Context(), userID, query)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch trashed comments")
+		return nil, err
+	}
+
+	return comments, nil
+}
+
+func (s *CommentService) RestoreComment(ctx echo.Context, userID string, commentID uuid.UUID) (*comment.Comment, error) {
+	logger := middleware.GetLogger(ctx)
+
+	trashedComment, err := s.commentRepo.GetTrashedComment(ctx.Request().Context(), userID, commentID)
+	if err != nil {
+		logger.Error().Err(err).Msg("trashed comment validation failed")
+		return nil, err
+	}
+
+	// Validate todo exists, is visible to the user and isn't itself in the trash
+	todoItem, err := s.todoRepo.CheckTodoExists(ctx.Request().Context(), userID, trashedComment.TodoID)
+	if err != nil {
+		logger.Error().Err(err).Msg("todo validation failed")
+		return nil, err
+	}
+
+	commentItem, err := s.commentRepo.RestoreComment(ctx.Request().Context(), userID, commentID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to restore comment")
+		return nil, err
+	}
+
+	s.activityService.Record(ctx, &activity.Activity{
+		TodoID:     &commentItem.TodoID,
+		UserID:     todoItem.UserID,
+		EntityType: activity.EntityTypeComment,
+		EntityID:   commentItem.ID,
+		Action:     activity.ActionRestored,
+		Changes:    activity.Diff(trashedComment, commentItem),
+	})
+
+	s.eventService.Publish(ctx, todoItem, event.TypeCommentRestored, commentItem)
+
+	// Business event log
+	eventLogger := middleware.GetLogger(ctx)
+	eventLogger.Info().
+		Str("event", "comment_restored").
+		Str("comment_id", commentItem.ID.String()).
+		Msg("Comment restored successfully")
+
+	return commentItem, nil
+}
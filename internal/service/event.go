@@ -0,0 +1,119 @@
+package service
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/uttam282005/tasker/internal/lib/pubsub"
+	"github.com/uttam282005/tasker/internal/lib/workspace"
+	"github.com/uttam282005/tasker/internal/middleware"
+	"github.com/uttam282005/tasker/internal/model/event"
+	"github.com/uttam282005/tasker/internal/model/todo"
+	"github.com/uttam282005/tasker/internal/repository"
+	"github.com/uttam282005/tasker/internal/server"
+)
+
+type EventService struct {
+	server   *server.Server
+	todoRepo *repository.TodoRepository
+	broker   *pubsub.Broker
+}
+
+func NewEventService(server *server.Server, todoRepo *repository.TodoRepository) *EventService {
+	return &EventService{
+		server:   server,
+		todoRepo: todoRepo,
+		broker:   pubsub.NewBroker(server.Redis, server.Logger),
+	}
+}
+
+// Topics mirror workspaces: an organization todo is visible to every member
+// so its events go to one org topic, while a personal todo's events go to the
+// topic of each user it is visible to.
+func userTopic(userID string) string {
+	return "user:" + userID
+}
+
+func orgTopic(orgID string) string {
+	return "org:" + orgID
+}
+
+// Publish pushes a change to every client streaming the todo's workspace.
+// Like activity, it runs after the change has been written, so failures are
+// logged instead of failing the request.
+func (s *EventService) Publish(ctx echo.Context, todoItem *todo.Todo, eventType event.Type, data any) {
+	logger := middleware.GetLogger(ctx).With().
+		Str("event_type", string(eventType)).
+		Str("todo_id", todoItem.ID.String()).
+		Logger()
+
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to encode event data")
+		return
+	}
+
+	payload, err := json.Marshal(event.Event{
+		Type:      eventType,
+		TodoID:    todoItem.ID,
+		ActorID:   middleware.GetUserID(ctx),
+		CreatedAt: time.Now().UTC(),
+		Data:      encoded,
+	})
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to encode event")
+		return
+	}
+
+	var topics []string
+	if todoItem.OrgID != nil {
+		topics = []string{orgTopic(*todoItem.OrgID)}
+	} else {
+		audience, err := s.todoRepo.GetTodoAudience(ctx.Request().Context(), todoItem.ID)
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to fetch todo audience")
+			return
+		}
+		for _, userID := range audience {
+			topics = append(topics, userTopic(userID))
+		}
+	}
+
+	for _, topic := range topics {
+		if _, err := s.broker.Publish(ctx.Request().Context(), topic, payload); err != nil {
+			logger.Error().Err(err).Str("topic", topic).Msg("failed to publish event")
+		}
+	}
+}
+
+// Subscribe opens a live subscription to the caller's workspace and, when
+// resuming, returns the backlog published after lastEventID. gap reports
+// that the backlog no longer reaches back that far. The subscription is
+// opened first so nothing published while the backlog is read is lost;
+// callers skip live messages the backlog already covered.
+func (s *EventService) Subscribe(ctx echo.Context, userID, lastEventID string) (
+	sub *pubsub.Subscription, backlog []pubsub.Message, gap bool, err error,
+) {
+	topic := userTopic(userID)
+	if orgID := workspace.OrgID(ctx.Request().Context()); orgID != nil {
+		topic = orgTopic(*orgID)
+	}
+
+	sub, err = s.broker.Subscribe(ctx.Request().Context(), topic)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	if lastEventID == "" {
+		return sub, nil, false, nil
+	}
+
+	backlog, gap, err = s.broker.Since(ctx.Request().Context(), topic, lastEventID)
+	if err != nil {
+		_ = sub.Close()
+		return nil, nil, false, err
+	}
+
+	return sub, backlog, gap, nil
+}
@@ -0,0 +1,15 @@
+package service
+
+import "net/http"
+
+// Webhook deliveries only reach public addresses, so tests swap in a client
+// that can reach their local receivers.
+
+var (
+	NewWebhookClient = newWebhookClient
+	IsPublicAddr     = isPublicAddr
+)
+
+func (s *WebhookService) SetHTTPClient(client *http.Client) {
+	s.httpClient = client
+}
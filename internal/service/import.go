@@ -0,0 +1,421 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"slices"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"github.com/uttam282005/tasker/internal/errs"
+	"github.com/uttam282005/tasker/internal/lib/importer"
+	"github.com/uttam282005/tasker/internal/lib/job"
+	"github.com/uttam282005/tasker/internal/lib/workspace"
+	"github.com/uttam282005/tasker/internal/middleware"
+	"github.com/uttam282005/tasker/internal/model/imports"
+	"github.com/uttam282005/tasker/internal/model/todo"
+	"github.com/uttam282005/tasker/internal/repository"
+	"github.com/uttam282005/tasker/internal/server"
+)
+
+const (
+	// maxImportFileSize caps the size of an uploaded export
+	maxImportFileSize = 10 << 20
+	// maxImportRowErrors caps how many row errors an import reports; the
+	// counts stay exact past it
+	maxImportRowErrors = 1000
+	// importProgressInterval is how many rows are imported between saves
+	// of an import's progress
+	importProgressInterval = 25
+
+	// Limits of todo.CreateTodoPayload and category.CreateCategoryPayload
+	maxImportTitleLength        = 250
+	maxImportDescriptionLength  = 1000
+	maxImportCategoryNameLength = 100
+	maxImportTagLength          = 50
+	maxImportTags               = 20
+)
+
+type ImportService struct {
+	server       *server.Server
+	importRepo   *repository.ImportRepository
+	todoRepo     *repository.TodoRepository
+	categoryRepo *repository.CategoryRepository
+}
+
+func NewImportService(server *server.Server, importRepo *repository.ImportRepository,
+	todoRepo *repository.TodoRepository, categoryRepo *repository.CategoryRepository,
+) *ImportService {
+	return &ImportService{
+		server:       server,
+		importRepo:   importRepo,
+		todoRepo:     todoRepo,
+		categoryRepo: categoryRepo,
+	}
+}
+
+// CreateImport stores the uploaded file and queues the job that imports it.
+func (s *ImportService) CreateImport(ctx echo.Context, userID string,
+	payload *imports.CreateImportPayload, file *multipart.FileHeader,
+) (*imports.Import, error) {
+	logger := middleware.GetLogger(ctx)
+
+	if file.Size > maxImportFileSize {
+		code := "IMPORT_FILE_TOO_LARGE"
+		return nil, errs.NewBadRequestError("file must be at most 10 MB", false, &code, nil, nil)
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to open uploaded file")
+		return nil, errs.NewBadRequestError("failed to open uploaded file", false, nil, nil, nil)
+	}
+	defer src.Close()
+
+	data, err := io.ReadAll(io.LimitReader(src, maxImportFileSize+1))
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to read uploaded file")
+		return nil, errs.NewBadRequestError("failed to read uploaded file", false, nil, nil, nil)
+	}
+	if len(data) > maxImportFileSize {
+		code := "IMPORT_FILE_TOO_LARGE"
+		return nil, errs.NewBadRequestError("file must be at most 10 MB", false, &code, nil, nil)
+	}
+
+	importItem, err := s.importRepo.CreateImport(ctx.Request().Context(), userID, payload, file.Filename, data)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to create import")
+		return nil, err
+	}
+
+	if err := job.EnqueueImport(s.server.Job.Client, importItem.ID); err != nil {
+		logger.Error().Err(err).Str("import_id", importItem.ID.String()).Msg("failed to enqueue import")
+
+		// Nothing would ever pick the import up
+		message := "the import couldn't be queued"
+		importItem.Status = imports.StatusFailed
+		importItem.Error = &message
+		if err := s.importRepo.FinishImport(ctx.Request().Context(), importItem); err != nil {
+			logger.Error().Err(err).Str("import_id", importItem.ID.String()).Msg("failed to mark import failed")
+		}
+
+		return nil, errors.Wrap(err, "failed to enqueue import")
+	}
+
+	// Business event log
+	eventLogger := middleware.GetLogger(ctx)
+	eventLogger.Info().
+		Str("event", "import_created").
+		Str("import_id", importItem.ID.String()).
+		Str("source", string(importItem.Source)).
+		Int("size", len(data)).
+		Msg("Import created successfully")
+
+	return importItem, nil
+}
+
+func (s *ImportService) GetImportByID(ctx echo.Context, userID string, importID uuid.UUID) (*imports.Import, error) {
+	logger := middleware.GetLogger(ctx)
+
+	importItem, err := s.importRepo.GetImportByID(ctx.Request().Context(), userID, importID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch import by ID")
+		return nil, err
+	}
+
+	return importItem, nil
+}
+
+// RunImport imports the file of a pending import into the workspace it was
+// uploaded in. It implements job.ImportRunnerInterface. Rows that can't be
+// imported are reported on the import; only failing to claim or finish the
+// import is an error.
+func (s *ImportService) RunImport(ctx context.Context, importID uuid.UUID) error {
+	logger := s.server.Logger.With().Str("import_id", importID.String()).Logger()
+
+	importItem, data, err := s.importRepo.ClaimImport(ctx, importID)
+	if err != nil {
+		return err
+	}
+	if importItem == nil {
+		logger.Warn().Msg("import was already claimed")
+		return nil
+	}
+
+	if importItem.OrgID != nil {
+		ctx = workspace.WithOrgID(ctx, *importItem.OrgID)
+	}
+
+	items, err := importer.Parse(importItem.Source, importItem.FileName, data, importItem.Mapping)
+	if err != nil {
+		logger.Warn().Err(err).Msg("failed to parse import file")
+		return s.finishImport(ctx, importItem, err)
+	}
+
+	importItem.TotalRows = len(items)
+	if err := s.importRepo.UpdateImportProgress(ctx, importItem); err != nil {
+		logger.Error().Err(err).Msg("failed to save import progress")
+	}
+
+	run := &importRun{
+		service:    s,
+		importItem: importItem,
+		categories: map[string]*uuid.UUID{},
+		todos:      map[string]uuid.UUID{},
+	}
+
+	for _, item := range orderImportItems(items) {
+		if ctx.Err() != nil {
+			return s.finishImport(ctx, importItem, errors.New("the import ran out of time"))
+		}
+
+		run.add(ctx, item)
+
+		importItem.ProcessedRows++
+		if importItem.ProcessedRows%importProgressInterval == 0 {
+			if err := s.importRepo.UpdateImportProgress(ctx, importItem); err != nil {
+				logger.Error().Err(err).Msg("failed to save import progress")
+			}
+		}
+	}
+
+	if err := s.finishImport(ctx, importItem, nil); err != nil {
+		return err
+	}
+
+	// Business event log
+	logger.Info().
+		Str("event", "import_completed").
+		Int("imported", importItem.ImportedRows).
+		Int("failed", importItem.FailedRows).
+		Msg("Import completed successfully")
+
+	return nil
+}
+
+// finishImport records that an import completed, or failed with cause.
+func (s *ImportService) finishImport(ctx context.Context, importItem *imports.Import, cause error) error {
+	importItem.Status = imports.StatusCompleted
+	if cause != nil {
+		message := cause.Error()
+		importItem.Status = imports.StatusFailed
+		importItem.Error = &message
+	}
+
+	// Saved even when the job ran out of time
+	return s.importRepo.FinishImport(context.WithoutCancel(ctx), importItem)
+}
+
+// importRun is the state of an import as it goes through the file's items.
+type importRun struct {
+	service    *ImportService
+	importItem *imports.Import
+	// categories holds the ID of each category by name, nil for ones that
+	// couldn't be created
+	categories map[string]*uuid.UUID
+	// todos holds the ID of each todo imported by its item's Ref
+	todos map[string]uuid.UUID
+}
+
+func (r *importRun) add(ctx context.Context, item importer.Item) {
+	s := r.service
+	userID := r.importItem.UserID
+
+	title := strings.Join(strings.Fields(item.Title), " ")
+	if title == "" {
+		r.fail(item, "has no title")
+		return
+	}
+	if utf8.RuneCountInString(title) > maxImportTitleLength {
+		title = truncateRunes(title, maxImportTitleLength)
+		item.Problems = append(item.Problems, fmt.Sprintf("title was cut to %d characters", maxImportTitleLength))
+	}
+	item.Title = title
+
+	if item.Description != nil && utf8.RuneCountInString(*item.Description) > maxImportDescriptionLength {
+		description := truncateRunes(*item.Description, maxImportDescriptionLength)
+		item.Description = &description
+		item.Problems = append(item.Problems, fmt.Sprintf("description was cut to %d characters", maxImportDescriptionLength))
+	}
+
+	payload := &todo.CreateTodoPayload{
+		Title:       item.Title,
+		Description: item.Description,
+		DueDate:     item.DueDate,
+		Priority:    item.Priority,
+	}
+
+	if item.ParentRef != "" {
+		if parentID, ok := r.todos[item.ParentRef]; ok {
+			payload.ParentTodoID = &parentID
+		} else {
+			item.Problems = append(item.Problems, "its parent wasn't imported, so it was imported as a todo of its own")
+		}
+	}
+
+	if item.Category != "" {
+		categoryID, err := r.category(ctx, item.Category)
+		if err != nil {
+			item.Problems = append(item.Problems, fmt.Sprintf("category %q couldn't be created", item.Category))
+		}
+		payload.CategoryID = categoryID
+	}
+
+	for _, name := range item.Tags {
+		name = truncateRunes(strings.TrimSpace(name), maxImportTagLength)
+		if name == "" || slices.Contains(payload.Tags, name) {
+			continue
+		}
+		if len(payload.Tags) == maxImportTags {
+			item.Problems = append(item.Problems, fmt.Sprintf("only the first %d tags were kept", maxImportTags))
+			break
+		}
+		payload.Tags = append(payload.Tags, name)
+	}
+
+	todoItem, err := s.todoRepo.CreateTodo(ctx, userID, payload)
+	if err != nil {
+		s.server.Logger.Error().Err(err).Str("import_id", r.importItem.ID.String()).Int("row", item.Row).Msg("failed to import todo")
+		r.fail(item, "couldn't be saved")
+		return
+	}
+	if item.Ref != "" {
+		r.todos[item.Ref] = todoItem.ID
+	}
+
+	// The importer follows the todos it creates, like any other creator
+	if _, err := s.todoRepo.AddTodoWatcher(ctx, todoItem.ID, userID); err != nil {
+		s.server.Logger.Warn().Err(err).Str("todo_id", todoItem.ID.String()).Msg("failed to add watcher")
+	}
+
+	if item.Status != "" && item.Status != todoItem.Status {
+		_, err := s.todoRepo.UpdateTodo(ctx, userID, &todo.UpdateTodoPayload{
+			ID:     todoItem.ID,
+			Status: &item.Status,
+		})
+		if err != nil {
+			item.Problems = append(item.Problems, fmt.Sprintf("status couldn't be set to %s", item.Status))
+		}
+	}
+
+	r.importItem.ImportedRows++
+	if len(item.Problems) > 0 {
+		r.report(imports.RowError{
+			Row:      item.Row,
+			Title:    item.Title,
+			Message:  strings.Join(item.Problems, "; "),
+			Imported: true,
+		})
+	}
+}
+
+// category returns the ID of the category named name, creating it the first
+// time it's seen.
+func (r *importRun) category(ctx context.Context, name string) (*uuid.UUID, error) {
+	name = truncateRunes(strings.TrimSpace(name), maxImportCategoryNameLength)
+	if categoryID, ok := r.categories[name]; ok {
+		return categoryID, nil
+	}
+
+	categoryItem, err := r.service.categoryRepo.GetOrCreateCategoryByName(ctx, r.importItem.UserID, name)
+	if err != nil {
+		r.service.server.Logger.Error().Err(err).Str("import_id", r.importItem.ID.String()).Msg("failed to create category")
+		r.categories[name] = nil
+		return nil, err
+	}
+
+	r.categories[name] = &categoryItem.ID
+	return &categoryItem.ID, nil
+}
+
+func (r *importRun) fail(item importer.Item, message string) {
+	r.importItem.FailedRows++
+	r.report(imports.RowError{
+		Row:     item.Row,
+		Title:   item.Title,
+		Message: message,
+	})
+}
+
+func (r *importRun) report(rowError imports.RowError) {
+	if len(r.importItem.RowErrors) < maxImportRowErrors {
+		r.importItem.RowErrors = append(r.importItem.RowErrors, rowError)
+	}
+}
+
+// orderImportItems puts every item after its parent. Subtasks can't have
+// subtasks, so deeper items are moved under their top-level ancestor, and
+// items whose parent isn't in the file become top-level.
+func orderImportItems(items []importer.Item) []importer.Item {
+	byRef := make(map[string]int, len(items))
+	for i, item := range items {
+		if _, ok := byRef[item.Ref]; item.Ref != "" && !ok {
+			byRef[item.Ref] = i
+		}
+	}
+
+	ordered := make([]importer.Item, len(items))
+	copy(ordered, items)
+
+	for i := range ordered {
+		item := &ordered[i]
+		if item.ParentRef == "" {
+			continue
+		}
+
+		parent, ok := byRef[item.ParentRef]
+		if !ok {
+			item.Problems = append(item.Problems, "its parent isn't in the file, so it was imported as a todo of its own")
+			item.ParentRef = ""
+			continue
+		}
+
+		// Walk up to the top-level ancestor, watching for loops
+		root := parent
+		seen := map[int]bool{i: true}
+		loop := false
+		for items[root].ParentRef != "" {
+			seen[root] = true
+			next, ok := byRef[items[root].ParentRef]
+			if !ok {
+				break
+			}
+			if seen[next] {
+				loop = true
+				break
+			}
+			root = next
+		}
+
+		if loop {
+			item.Problems = append(item.Problems, "its parents form a loop, so it was imported as a todo of its own")
+			item.ParentRef = ""
+			continue
+		}
+
+		if root != parent {
+			item.Problems = append(item.Problems,
+				fmt.Sprintf("subtasks can't have subtasks, so it was added to %q instead", items[root].Title))
+		}
+		item.ParentRef = items[root].Ref
+	}
+
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].ParentRef == "" && ordered[j].ParentRef != ""
+	})
+
+	return ordered
+}
+
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/uttam282005/tasker/internal/lib/job"
+	"github.com/uttam282005/tasker/internal/middleware"
+	"github.com/uttam282005/tasker/internal/model/todo"
+	"github.com/uttam282005/tasker/internal/repository"
+	"github.com/uttam282005/tasker/internal/server"
+)
+
+type NotificationService struct {
+	server   *server.Server
+	todoRepo *repository.TodoRepository
+}
+
+func NewNotificationService(server *server.Server, todoRepo *repository.TodoRepository) *NotificationService {
+	return &NotificationService{
+		server:   server,
+		todoRepo: todoRepo,
+	}
+}
+
+// NotifyWatchers enqueues an email for every watcher of the todo except the
+// user who made the change. Like activity, it runs after the change has been
+// written, so failures are logged instead of failing the request.
+func (s *NotificationService) NotifyWatchers(ctx echo.Context, todoItem *todo.Todo, event string, fields []string) {
+	logger := middleware.GetLogger(ctx)
+	actorID := middleware.GetUserID(ctx)
+
+	watchers, err := s.todoRepo.GetTodoWatchers(ctx.Request().Context(), todoItem.ID)
+	if err != nil {
+		logger.Error().Err(err).Str("todo_id", todoItem.ID.String()).Msg("failed to fetch todo watchers")
+		return
+	}
+
+	for _, watcherID := range watchers {
+		if watcherID == actorID {
+			continue
+		}
+
+		err := job.EnqueueTodoWatcherEmail(s.server.Job.Client, &job.TodoWatcherEmailTask{
+			UserID:    watcherID,
+			TodoID:    todoItem.ID,
+			TodoTitle: todoItem.Title,
+			ActorID:   actorID,
+			Event:     event,
+			Fields:    fields,
+		})
+		if err != nil {
+			logger.Error().
+				Err(err).
+				Str("todo_id", todoItem.ID.String()).
+				Str("watcher_id", watcherID).
+				Msg("failed to enqueue watcher notification")
+		}
+	}
+}
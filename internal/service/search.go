@@ -0,0 +1,36 @@
+package service
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/uttam282005/tasker/internal/middleware"
+	"github.com/uttam282005/tasker/internal/model"
+	"github.com/uttam282005/tasker/internal/model/search"
+	"github.com/uttam282005/tasker/internal/repository"
+	"github.com/uttam282005/tasker/internal/server"
+)
+
+type SearchService struct {
+	server     *server.Server
+	searchRepo *repository.SearchRepository
+}
+
+func NewSearchService(server *server.Server, searchRepo *repository.SearchRepository) *SearchService {
+	return &SearchService{
+		server:     server,
+		searchRepo: searchRepo,
+	}
+}
+
+func (s *SearchService) Search(ctx echo.Context, userID string,
+	query *search.SearchQuery,
+) (*model.PaginatedResponse[search.Hit], error) {
+	logger := middleware.GetLogger(ctx)
+
+	result, err := s.searchRepo.Search(ctx.Request().Context(), userID, query)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to search")
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -15,6 +15,15 @@ type Services struct {
 	Todo     *TodoService
 	Comment  *CommentService
 	Category *CategoryService
+	Search   *SearchService
+	Activity *ActivityService
+	Share    *ShareService
+	Webhook  *WebhookService
+	Event    *EventService
+	Calendar *CalendarService
+	Import   *ImportService
+	Tag      *TagService
+	View     *ViewService
 }
 
 func NewServices(s *server.Server, repos *repository.Repositories) (*Services, error) {
@@ -27,11 +36,29 @@ func NewServices(s *server.Server, repos *repository.Repositories) (*Services, e
 		return nil, fmt.Errorf("failed to create AWS client: %w", err)
 	}
 
+	activityService := NewActivityService(s, repos.Activity, repos.Todo)
+	notificationService := NewNotificationService(s, repos.Todo)
+	webhookService := NewWebhookService(s, repos.Webhook)
+	eventService := NewEventService(s, repos.Todo)
+	importService := NewImportService(s, repos.Import, repos.Todo, repos.Category)
+
+	s.Job.SetWebhookDeliverer(webhookService)
+	s.Job.SetImportRunner(importService)
+
 	return &Services{
 		Job:      s.Job,
 		Auth:     authService,
-		Todo:     NewTodoService(s, repos.Todo, repos.Category, awsClient),
-		Comment:  NewCommentService(s, repos.Comment, repos.Todo),
-		Category: NewCategoryService(s, repos.Category),
+		Todo:     NewTodoService(s, repos.Todo, repos.Category, awsClient, activityService, notificationService, webhookService, eventService),
+		Comment:  NewCommentService(s, repos.Comment, repos.Todo, activityService, notificationService, webhookService, eventService),
+		Category: NewCategoryService(s, repos.Category, activityService),
+		Search:   NewSearchService(s, repos.Search),
+		Activity: activityService,
+		Share:    NewShareService(s, repos.Share, repos.Todo, repos.Category),
+		Webhook:  webhookService,
+		Event:    eventService,
+		Calendar: NewCalendarService(s, repos.Calendar, repos.Category, authService),
+		Import:   importService,
+		Tag:      NewTagService(s, repos.Tag),
+		View:     NewViewService(s, repos.View, repos.Todo),
 	}, nil
 }
@@ -0,0 +1,260 @@
+package service
+
+import (
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/uttam282005/tasker/internal/errs"
+	"github.com/uttam282005/tasker/internal/middleware"
+	"github.com/uttam282005/tasker/internal/model"
+	"github.com/uttam282005/tasker/internal/model/share"
+	"github.com/uttam282005/tasker/internal/model/todo"
+	"github.com/uttam282005/tasker/internal/repository"
+	"github.com/uttam282005/tasker/internal/server"
+)
+
+type ShareService struct {
+	server       *server.Server
+	shareRepo    *repository.ShareRepository
+	todoRepo     *repository.TodoRepository
+	categoryRepo *repository.CategoryRepository
+}
+
+func NewShareService(server *server.Server, shareRepo *repository.ShareRepository,
+	todoRepo *repository.TodoRepository, categoryRepo *repository.CategoryRepository,
+) *ShareService {
+	return &ShareService{
+		server:       server,
+		shareRepo:    shareRepo,
+		todoRepo:     todoRepo,
+		categoryRepo: categoryRepo,
+	}
+}
+
+// authorizeTodo returns the todo when the user's role on it allows required.
+// Todos the user can't see at all are reported as not found, the ones they
+// can see but not change as forbidden.
+func authorizeTodo(ctx echo.Context, todoRepo *repository.TodoRepository, userID string,
+	todoID uuid.UUID, required share.Role,
+) (*todo.Todo, error) {
+	todoItem, role, err := todoRepo.GetTodoWithRole(ctx.Request().Context(), userID, todoID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !role.Allows(required) {
+		return nil, errs.NewForbiddenError("You don't have permission to "+requiredAction(required)+" this todo", false)
+	}
+
+	return todoItem, nil
+}
+
+func requiredAction(role share.Role) string {
+	switch role {
+	case share.RoleCommenter:
+		return "comment on"
+	case share.RoleEditor:
+		return "edit"
+	case share.RoleOwner:
+		return "manage"
+	default:
+		return "view"
+	}
+}
+
+func (s *ShareService) ShareTodo(ctx echo.Context, userID string,
+	payload *share.CreateTodoSharePayload,
+) (*share.Share, error) {
+	logger := middleware.GetLogger(ctx)
+
+	todoItem, err := authorizeTodo(ctx, s.todoRepo, userID, payload.TodoID, share.RoleOwner)
+	if err != nil {
+		logger.Error().Err(err).Msg("todo validation failed")
+		return nil, err
+	}
+
+	if todoItem.OrgID != nil {
+		err := errs.NewBadRequestError("Organization todos are already shared with every member", false, nil, nil, nil)
+		logger.Warn().Msg("todo belongs to an organization")
+		return nil, err
+	}
+
+	if payload.GranteeID == userID {
+		err := errs.NewBadRequestError("You can't share a todo with yourself", false, nil, nil, nil)
+		logger.Warn().Msg("todo shared with its owner")
+		return nil, err
+	}
+
+	shareItem, err := s.shareRepo.CreateShare(ctx.Request().Context(), userID, payload.GranteeID,
+		&todoItem.ID, nil, payload.Role)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to share todo")
+		return nil, err
+	}
+
+	// Business event log
+	eventLogger := middleware.GetLogger(ctx)
+	eventLogger.Info().
+		Str("event", "todo_shared").
+		Str("share_id", shareItem.ID.String()).
+		Str("todo_id", todoItem.ID.String()).
+		Str("grantee_id", shareItem.GranteeID).
+		Str("role", string(shareItem.Role)).
+		Msg("Todo shared successfully")
+
+	return shareItem, nil
+}
+
+func (s *ShareService) ShareCategory(ctx echo.Context, userID string,
+	payload *share.CreateCategorySharePayload,
+) (*share.Share, error) {
+	logger := middleware.GetLogger(ctx)
+
+	categoryItem, err := s.categoryRepo.GetCategoryByID(ctx.Request().Context(), userID, payload.CategoryID)
+	if err != nil {
+		logger.Error().Err(err).Msg("category validation failed")
+		return nil, err
+	}
+
+	if categoryItem.OrgID != nil {
+		err := errs.NewBadRequestError("Organization categories are already shared with every member", false, nil, nil, nil)
+		logger.Warn().Msg("category belongs to an organization")
+		return nil, err
+	}
+
+	if categoryItem.UserID != userID {
+		err := errs.NewForbiddenError("Only the owner can share a category", false)
+		logger.Warn().Msg("category belongs to another user")
+		return nil, err
+	}
+
+	if payload.GranteeID == userID {
+		err := errs.NewBadRequestError("You can't share a category with yourself", false, nil, nil, nil)
+		logger.Warn().Msg("category shared with its owner")
+		return nil, err
+	}
+
+	shareItem, err := s.shareRepo.CreateShare(ctx.Request().Context(), userID, payload.GranteeID,
+		nil, &categoryItem.ID, payload.Role)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to share category")
+		return nil, err
+	}
+
+	// Business event log
+	eventLogger := middleware.GetLogger(ctx)
+	eventLogger.Info().
+		Str("event", "category_shared").
+		Str("share_id", shareItem.ID.String()).
+		Str("category_id", categoryItem.ID.String()).
+		Str("grantee_id", shareItem.GranteeID).
+		Str("role", string(shareItem.Role)).
+		Msg("Category shared successfully")
+
+	return shareItem, nil
+}
+
+func (s *ShareService) GetTodoShares(ctx echo.Context, userID string, todoID uuid.UUID) ([]share.Share, error) {
+	logger := middleware.GetLogger(ctx)
+
+	if _, err := authorizeTodo(ctx, s.todoRepo, userID, todoID, share.RoleOwner); err != nil {
+		logger.Error().Err(err).Msg("todo validation failed")
+		return nil, err
+	}
+
+	shares, err := s.shareRepo.GetTodoShares(ctx.Request().Context(), userID, todoID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch todo shares")
+		return nil, err
+	}
+
+	return shares, nil
+}
+
+func (s *ShareService) GetCategoryShares(ctx echo.Context, userID string, categoryID uuid.UUID) ([]share.Share, error) {
+	logger := middleware.GetLogger(ctx)
+
+	categoryItem, err := s.categoryRepo.GetCategoryByID(ctx.Request().Context(), userID, categoryID)
+	if err != nil {
+		logger.Error().Err(err).Msg("category validation failed")
+		return nil, err
+	}
+
+	if categoryItem.UserID != userID {
+		err := errs.NewForbiddenError("Only the owner can see who a category is shared with", false)
+		logger.Warn().Msg("category belongs to another user")
+		return nil, err
+	}
+
+	shares, err := s.shareRepo.GetCategoryShares(ctx.Request().Context(), userID, categoryID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch category shares")
+		return nil, err
+	}
+
+	return shares, nil
+}
+
+func (s *ShareService) UpdateShare(ctx echo.Context, userID string, payload *share.UpdateSharePayload) (*share.Share, error) {
+	logger := middleware.GetLogger(ctx)
+
+	currentShare, err := s.shareRepo.GetShareByID(ctx.Request().Context(), userID, payload.ID)
+	if err != nil {
+		logger.Error().Err(err).Msg("share validation failed")
+		return nil, err
+	}
+
+	if currentShare.OwnerID != userID {
+		err := errs.NewForbiddenError("Only the owner can change a share's role", false)
+		logger.Warn().Msg("share granted by another user")
+		return nil, err
+	}
+
+	shareItem, err := s.shareRepo.UpdateShareRole(ctx.Request().Context(), userID, payload.ID, payload.Role)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to update share")
+		return nil, err
+	}
+
+	// Business event log
+	eventLogger := middleware.GetLogger(ctx)
+	eventLogger.Info().
+		Str("event", "share_updated").
+		Str("share_id", shareItem.ID.String()).
+		Str("role", string(shareItem.Role)).
+		Msg("Share updated successfully")
+
+	return shareItem, nil
+}
+
+func (s *ShareService) DeleteShare(ctx echo.Context, userID string, shareID uuid.UUID) error {
+	logger := middleware.GetLogger(ctx)
+
+	err := s.shareRepo.DeleteShare(ctx.Request().Context(), userID, shareID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to delete share")
+		return err
+	}
+
+	// Business event log
+	eventLogger := middleware.GetLogger(ctx)
+	eventLogger.Info().
+		Str("event", "share_deleted").
+		Str("share_id", shareID.String()).
+		Msg("Share deleted successfully")
+
+	return nil
+}
+
+func (s *ShareService) GetSharedWithMe(ctx echo.Context, userID string,
+	query *share.GetSharedWithMeQuery,
+) (*model.PaginatedResponse[share.SharedItem], error) {
+	logger := middleware.GetLogger(ctx)
+
+	result, err := s.shareRepo.GetSharedWithMe(ctx.Request().Context(), userID, query)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch shared items")
+		return nil, err
+	}
+
+	return result, nil
+}
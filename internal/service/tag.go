@@ -0,0 +1,106 @@
+package service
+
+import (
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/uttam282005/tasker/internal/middleware"
+	"github.com/uttam282005/tasker/internal/model/tag"
+	"github.com/uttam282005/tasker/internal/repository"
+	"github.com/uttam282005/tasker/internal/server"
+)
+
+type TagService struct {
+	server  *server.Server
+	tagRepo *repository.TagRepository
+}
+
+func NewTagService(server *server.Server, tagRepo *repository.TagRepository) *TagService {
+	return &TagService{
+		server:  server,
+		tagRepo: tagRepo,
+	}
+}
+
+func (s *TagService) CreateTag(ctx echo.Context, userID string, payload *tag.CreateTagPayload) (*tag.Tag, error) {
+	logger := middleware.GetLogger(ctx)
+
+	tagItem, err := s.tagRepo.CreateTag(ctx.Request().Context(), userID, payload)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to create tag")
+		return nil, err
+	}
+
+	// Business event log
+	eventLogger := middleware.GetLogger(ctx)
+	eventLogger.Info().
+		Str("event", "tag_created").
+		Str("tag_id", tagItem.ID.String()).
+		Str("name", tagItem.Name).
+		Msg("Tag created successfully")
+
+	return tagItem, nil
+}
+
+func (s *TagService) GetTags(ctx echo.Context, userID string, query *tag.GetTagsQuery) ([]tag.TagUsage, error) {
+	logger := middleware.GetLogger(ctx)
+
+	tags, err := s.tagRepo.GetTags(ctx.Request().Context(), userID, query)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch tags")
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+func (s *TagService) GetTagByID(ctx echo.Context, userID string, tagID uuid.UUID) (*tag.TagUsage, error) {
+	logger := middleware.GetLogger(ctx)
+
+	tagItem, err := s.tagRepo.GetTagByID(ctx.Request().Context(), userID, tagID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch tag by ID")
+		return nil, err
+	}
+
+	return tagItem, nil
+}
+
+// UpdateTag renames or recolors the tag on every todo it's on. Renaming it to
+// the name of another tag is refused rather than merging the two.
+func (s *TagService) UpdateTag(ctx echo.Context, userID string, payload *tag.UpdateTagPayload) (*tag.Tag, error) {
+	logger := middleware.GetLogger(ctx)
+
+	tagItem, err := s.tagRepo.UpdateTag(ctx.Request().Context(), userID, payload)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to update tag")
+		return nil, err
+	}
+
+	// Business event log
+	eventLogger := middleware.GetLogger(ctx)
+	eventLogger.Info().
+		Str("event", "tag_updated").
+		Str("tag_id", tagItem.ID.String()).
+		Str("name", tagItem.Name).
+		Msg("Tag updated successfully")
+
+	return tagItem, nil
+}
+
+func (s *TagService) DeleteTag(ctx echo.Context, userID string, tagID uuid.UUID) error {
+	logger := middleware.GetLogger(ctx)
+
+	if err := s.tagRepo.DeleteTag(ctx.Request().Context(), userID, tagID); err != nil {
+		logger.Error().Err(err).Msg("failed to delete tag")
+		return err
+	}
+
+	// Business event log
+	eventLogger := middleware.GetLogger(ctx)
+	eventLogger.Info().
+		Str("event", "tag_deleted").
+		Str("tag_id", tagID.String()).
+		Msg("Tag deleted successfully")
+
+	return nil
+}
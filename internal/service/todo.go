@@ -1,45 +1,68 @@
 package service
 
 import (
+	"io"
 	"mime/multipart"
 	"net/http"
+	"slices"
 
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"github.com/pkg/errors"
 	"github.com/uttam282005/tasker/internal/errs"
 	"github.com/uttam282005/tasker/internal/lib/aws"
+	"github.com/uttam282005/tasker/internal/lib/etag"
+	"github.com/uttam282005/tasker/internal/lib/job"
+	"github.com/uttam282005/tasker/internal/lib/workspace"
 	"github.com/uttam282005/tasker/internal/middleware"
 	"github.com/uttam282005/tasker/internal/model"
+	"github.com/uttam282005/tasker/internal/model/activity"
+	"github.com/uttam282005/tasker/internal/model/event"
+	"github.com/uttam282005/tasker/internal/model/share"
 	"github.com/uttam282005/tasker/internal/model/todo"
+	"github.com/uttam282005/tasker/internal/model/webhook"
 	"github.com/uttam282005/tasker/internal/repository"
 	"github.com/uttam282005/tasker/internal/server"
+	"github.com/uttam282005/tasker/internal/sqlerr"
 )
 
 type TodoService struct {
-	server       *server.Server
-	todoRepo     *repository.TodoRepository
-	categoryRepo *repository.CategoryRepository
-	awsClient    *aws.AWS
+	server              *server.Server
+	todoRepo            *repository.TodoRepository
+	categoryRepo        *repository.CategoryRepository
+	awsClient           *aws.AWS
+	activityService     *ActivityService
+	notificationService *NotificationService
+	webhookService      *WebhookService
+	eventService        *EventService
 }
 
 func NewTodoService(server *server.Server, todoRepo *repository.TodoRepository,
-	categoryRepo *repository.CategoryRepository, awsClient *aws.AWS,
+	categoryRepo *repository.CategoryRepository, awsClient *aws.AWS, activityService *ActivityService,
+	notificationService *NotificationService, webhookService *WebhookService, eventService *EventService,
 ) *TodoService {
 	return &TodoService{
-		server:       server,
-		todoRepo:     todoRepo,
-		categoryRepo: categoryRepo,
-		awsClient:    awsClient,
+		server:              server,
+		todoRepo:            todoRepo,
+		categoryRepo:        categoryRepo,
+		awsClient:           awsClient,
+		activityService:     activityService,
+		notificationService: notificationService,
+		webhookService:      webhookService,
+		eventService:        eventService,
 	}
 }
 
 func (s *TodoService) CreateTodo(ctx echo.Context, userID string, payload *todo.CreateTodoPayload) (*todo.Todo, error) {
 	logger := middleware.GetLogger(ctx)
 
-	// Validate parent todo exists and belongs to user (if provided)
+	// Personal subtasks belong to the parent's owner, even when an editor
+	// adds them
+	ownerID := userID
+
+	// Validate parent todo exists and the user can edit it (if provided)
 	if payload.ParentTodoID != nil {
-		parentTodo, err := s.todoRepo.CheckTodoExists(ctx.Request().Context(), userID, *payload.ParentTodoID)
+		parentTodo, err := authorizeTodo(ctx, s.todoRepo, userID, *payload.ParentTodoID, share.RoleEditor)
 		if err != nil {
 			logger.Error().Err(err).Msg("parent todo validation failed")
 			return nil, err
@@ -50,23 +73,53 @@ func (s *TodoService) CreateTodo(ctx echo.Context, userID string, payload *todo.
 			logger.Warn().Msg("parent todo cannot have children")
 			return nil, err
 		}
+
+		if parentTodo.OrgID == nil {
+			ownerID = parentTodo.UserID
+		}
 	}
 
-	// Validate category exists and belongs to user (if provided)
+	// Validate category exists and belongs to the todo's owner (if provided)
 	if payload.CategoryID != nil {
-		_, err := s.categoryRepo.GetCategoryByID(ctx.Request().Context(), userID, *payload.CategoryID)
-		if err != nil {
+		if err := s.validateCategoryOwner(ctx, userID, ownerID, *payload.CategoryID); err != nil {
 			logger.Error().Err(err).Msg("category validation failed")
 			return nil, err
 		}
 	}
 
-	todoItem, err := s.todoRepo.CreateTodo(ctx.Request().Context(), userID, payload)
+	// Validate the assignee can see the new todo (if provided)
+	if payload.AssigneeID != nil {
+		if err := s.validateAssignee(ctx, ownerID, *payload.AssigneeID, payload.ParentTodoID, payload.CategoryID); err != nil {
+			logger.Error().Err(err).Msg("assignee validation failed")
+			return nil, err
+		}
+	}
+
+	todoItem, err := s.todoRepo.CreateTodo(ctx.Request().Context(), ownerID, payload)
 	if err != nil {
 		logger.Error().Err(err).Msg("failed to create todo")
 		return nil, err
 	}
 
+	// The creator, the owner and the assignee follow the todo from the start
+	watcherIDs := []string{userID, todoItem.UserID}
+	if todoItem.AssigneeID != nil {
+		watcherIDs = append(watcherIDs, *todoItem.AssigneeID)
+	}
+	s.addWatchers(ctx, todoItem.ID, watcherIDs...)
+
+	s.webhookService.Dispatch(ctx, todoItem, webhook.EventTodoCreated, todoItem)
+	s.eventService.Publish(ctx, todoItem, event.TypeTodoCreated, todoItem)
+
+	s.activityService.Record(ctx, &activity.Activity{
+		TodoID:     &todoItem.ID,
+		UserID:     todoItem.UserID,
+		EntityType: activity.EntityTypeTodo,
+		EntityID:   todoItem.ID,
+		Action:     activity.ActionCreated,
+		Changes:    activity.Diff(nil, todoItem),
+	})
+
 	// Business event log
 	eventLogger := middleware.GetLogger(ctx)
 	eventLogger.Info().
@@ -85,6 +138,64 @@ func (s *TodoService) CreateTodo(ctx echo.Context, userID string, payload *todo.
 	return todoItem, nil
 }
 
+// validateCategoryOwner makes sure the category is visible to the user and,
+// outside organizations, belongs to ownerID so shared categories can't collect
+// other people's todos.
+func (s *TodoService) validateCategoryOwner(ctx echo.Context, userID, ownerID string, categoryID uuid.UUID) error {
+	categoryItem, err := s.categoryRepo.GetCategoryByID(ctx.Request().Context(), userID, categoryID)
+	if err != nil {
+		return err
+	}
+
+	if categoryItem.OrgID == nil && categoryItem.UserID != ownerID {
+		code := "CATEGORY_OWNER_MISMATCH"
+		return errs.NewBadRequestError("Category must belong to the todo's owner", false, &code, nil, nil)
+	}
+
+	return nil
+}
+
+// validateAssignee makes sure the assignee can see the todo, either through
+// todoID itself or, for a todo that doesn't exist yet, through its parent or
+// category. Every member of an organization can see its todos.
+func (s *TodoService) validateAssignee(ctx echo.Context, ownerID, assigneeID string, todoID, categoryID *uuid.UUID) error {
+	if assigneeID == ownerID || workspace.OrgID(ctx.Request().Context()) != nil {
+		return nil
+	}
+
+	var hasAccess bool
+	var err error
+	switch {
+	case todoID != nil:
+		hasAccess, err = s.todoRepo.CanAccessTodo(ctx.Request().Context(), assigneeID, *todoID)
+	case categoryID != nil:
+		hasAccess, err = s.categoryRepo.CanAccessCategory(ctx.Request().Context(), assigneeID, *categoryID)
+	}
+	if err != nil {
+		return err
+	}
+
+	if !hasAccess {
+		code := "ASSIGNEE_NO_ACCESS"
+		return errs.NewBadRequestError("Assignee must have access to the todo", false, &code, nil, nil)
+	}
+
+	return nil
+}
+
+// addWatchers subscribes users to a todo as a side effect of another change,
+// so failures are logged instead of failing the request.
+func (s *TodoService) addWatchers(ctx echo.Context, todoID uuid.UUID, userIDs ...string) {
+	logger := middleware.GetLogger(ctx)
+
+	slices.Sort(userIDs)
+	for _, watcherID := range slices.Compact(userIDs) {
+		if _, err := s.todoRepo.AddTodoWatcher(ctx.Request().Context(), todoID, watcherID); err != nil {
+			logger.Error().Err(err).Str("watcher_id", watcherID).Msg("failed to add todo watcher")
+		}
+	}
+}
+
 func (s *TodoService) GetTodoByID(ctx echo.Context, userID string, todoID uuid.UUID) (*todo.PopulatedTodo, error) {
 	logger := middleware.GetLogger(ctx)
 
@@ -97,7 +208,7 @@ func (s *TodoService) GetTodoByID(ctx echo.Context, userID string, todoID uuid.U
 	return todoItem, nil
 }
 
-func (s *TodoService) GetTodos(ctx echo.Context, userID string, query *todo.GetTodosQuery) (*model.PaginatedResponse[todo.PopulatedTodo], error) {
+func (s *TodoService) GetTodos(ctx echo.Context, userID string, query *todo.GetTodosQuery) (*model.CursorPaginatedResponse[todo.PopulatedTodo], error) {
 	logger := middleware.GetLogger(ctx)
 
 	result, err := s.todoRepo.GetTodos(ctx.Request().Context(), userID, query)
@@ -109,17 +220,89 @@ func (s *TodoService) GetTodos(ctx echo.Context, userID string, query *todo.GetT
 	return result, nil
 }
 
+// ExportTodos writes every todo matching the query to w in the query's
+// format. Nothing is written when reading the todos fails straight away, so
+// the error can still be answered normally.
+func (s *TodoService) ExportTodos(ctx echo.Context, userID string, query *todo.ExportTodosQuery, w io.Writer) error {
+	logger := middleware.GetLogger(ctx)
+
+	exporter := newTodoExporter(query.Format, w)
+	started := false
+	count := 0
+
+	err := s.todoRepo.ExportTodos(ctx.Request().Context(), userID, query,
+		func(t *todo.PopulatedTodo, subtasks []todo.PopulatedTodo) error {
+			if !started {
+				if err := exporter.begin(); err != nil {
+					return err
+				}
+				started = true
+			}
+
+			exported := todo.NewExportedTodo(t)
+			for i := range subtasks {
+				exported.Subtasks = append(exported.Subtasks, todo.NewExportedTodo(&subtasks[i]))
+			}
+			count += 1 + len(subtasks)
+
+			return exporter.write(exported)
+		})
+	if err == nil && !started {
+		err = exporter.begin()
+	}
+	if err == nil {
+		err = exporter.end()
+	}
+	if err != nil {
+		logger.Error().Err(err).Int("exported", count).Msg("failed to export todos")
+		return err
+	}
+
+	// Business event log
+	eventLogger := middleware.GetLogger(ctx)
+	eventLogger.Info().
+		Str("event", "todos_exported").
+		Str("format", string(query.Format)).
+		Int("count", count).
+		Msg("Todos exported successfully")
+
+	return nil
+}
+
 func (s *TodoService) UpdateTodo(ctx echo.Context, userID string, payload *todo.UpdateTodoPayload) (*todo.Todo, error) {
 	logger := middleware.GetLogger(ctx)
 
-	// Validate parent todo exists and belongs to user (if provided)
+	currentTodo, err := authorizeTodo(ctx, s.todoRepo, userID, payload.ID, share.RoleEditor)
+	if err != nil {
+		logger.Error().Err(err).Msg("todo validation failed")
+		return nil, err
+	}
+
+	if err := validateRecurrenceUpdate(currentTodo, payload); err != nil {
+		logger.Warn().Err(err).Msg("recurrence validation failed")
+		return nil, err
+	}
+
+	if payload.Status != nil && *payload.Status == todo.StatusCompleted && currentTodo.Status != todo.StatusCompleted {
+		if err := s.validateCompletion(ctx, payload.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	// Validate parent todo exists and the user can edit it (if provided)
 	if payload.ParentTodoID != nil {
-		parentTodo, err := s.todoRepo.CheckTodoExists(ctx.Request().Context(), userID, *payload.ParentTodoID)
+		parentTodo, err := authorizeTodo(ctx, s.todoRepo, userID, *payload.ParentTodoID, share.RoleEditor)
 		if err != nil {
 			logger.Error().Err(err).Msg("parent todo validation failed")
 			return nil, err
 		}
 
+		if parentTodo.OrgID == nil && parentTodo.UserID != currentTodo.UserID {
+			err := errs.NewBadRequestError("Parent todo must belong to the same owner", false, nil, nil, nil)
+			logger.Warn().Msg("parent todo has a different owner")
+			return nil, err
+		}
+
 		if parentTodo.ID == payload.ID {
 			err := errs.NewBadRequestError("Todo cannot be its own parent", false, nil, nil, nil)
 			logger.Warn().Msg("todo cannot be its own parent")
@@ -135,10 +318,9 @@ func (s *TodoService) UpdateTodo(ctx echo.Context, userID string, payload *todo.
 		logger.Debug().Msg("parent todo validation passed")
 	}
 
-	// Validate category exists and belongs to user (if provided)
+	// Validate category exists and belongs to the todo's owner (if provided)
 	if payload.CategoryID != nil {
-		_, err := s.categoryRepo.GetCategoryByID(ctx.Request().Context(), userID, *payload.CategoryID)
-		if err != nil {
+		if err := s.validateCategoryOwner(ctx, userID, currentTodo.UserID, *payload.CategoryID); err != nil {
 			logger.Error().Err(err).Msg("category validation failed")
 			return nil, err
 		}
@@ -146,12 +328,22 @@ func (s *TodoService) UpdateTodo(ctx echo.Context, userID string, payload *todo.
 		logger.Debug().Msg("category validation passed")
 	}
 
+	// Validate the new assignee can see the todo (if provided)
+	if payload.AssigneeID != nil && *payload.AssigneeID != "" {
+		if err := s.validateAssignee(ctx, currentTodo.UserID, *payload.AssigneeID, &payload.ID, nil); err != nil {
+			logger.Error().Err(err).Msg("assignee validation failed")
+			return nil, err
+		}
+	}
+
 	updatedTodo, err := s.todoRepo.UpdateTodo(ctx.Request().Context(), userID, payload)
 	if err != nil {
 		logger.Error().Err(err).Msg("failed to update todo")
 		return nil, err
 	}
 
+	s.todoUpdated(ctx, currentTodo, updatedTodo)
+
 	// Business event log
 	eventLogger := middleware.GetLogger(ctx)
 	eventLogger.Info().
@@ -171,15 +363,153 @@ func (s *TodoService) UpdateTodo(ctx echo.Context, userID string, payload *todo.
 	return updatedTodo, nil
 }
 
-func (s *TodoService) DeleteTodo(ctx echo.Context, userID string, todoID uuid.UUID) error {
+// validateCompletion refuses to complete a todo while anything blocking it is
+// still open.
+func (s *TodoService) validateCompletion(ctx echo.Context, todoID uuid.UUID) error {
 	logger := middleware.GetLogger(ctx)
 
-	err := s.todoRepo.DeleteTodo(ctx.Request().Context(), userID, todoID)
+	blockers, err := s.todoRepo.CountIncompleteBlockers(ctx.Request().Context(), todoID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to count incomplete blockers")
+		return err
+	}
+
+	if blockers > 0 {
+		code := "TODO_BLOCKED"
+		logger.Warn().Int("blockers", blockers).Msg("todo is blocked")
+		return errs.NewBadRequestError("Todo has incomplete blockers and cannot be completed", false, &code, nil, nil)
+	}
+
+	return nil
+}
+
+// todoUpdated records an update and tells everyone following the todo.
+func (s *TodoService) todoUpdated(ctx echo.Context, currentTodo, updatedTodo *todo.Todo) {
+	changes := activity.Diff(currentTodo, updatedTodo)
+	s.activityService.Record(ctx, &activity.Activity{
+		TodoID:     &updatedTodo.ID,
+		UserID:     updatedTodo.UserID,
+		EntityType: activity.EntityTypeTodo,
+		EntityID:   updatedTodo.ID,
+		Action:     activity.ActionUpdated,
+		Changes:    changes,
+	})
+
+	if _, reassigned := changes["assigneeId"]; reassigned && updatedTodo.AssigneeID != nil {
+		s.addWatchers(ctx, updatedTodo.ID, *updatedTodo.AssigneeID)
+	}
+
+	completed := currentTodo.Status != todo.StatusCompleted && updatedTodo.Status == todo.StatusCompleted
+
+	s.webhookService.Dispatch(ctx, updatedTodo, webhook.EventTodoUpdated, updatedTodo)
+	if completed {
+		s.webhookService.Dispatch(ctx, updatedTodo, webhook.EventTodoCompleted, updatedTodo)
+	}
+	s.eventService.Publish(ctx, updatedTodo, event.TypeTodoUpdated, updatedTodo)
+
+	if len(changes) > 0 {
+		event := job.WatcherEventUpdated
+		if completed {
+			event = job.WatcherEventCompleted
+		}
+
+		fields := make([]string, 0, len(changes))
+		for field := range changes {
+			fields = append(fields, field)
+		}
+		slices.Sort(fields)
+
+		s.notificationService.NotifyWatchers(ctx, updatedTodo, event, fields)
+	}
+}
+
+func (s *TodoService) MoveTodo(ctx echo.Context, userID string, payload *todo.MoveTodoPayload) (*todo.Todo, error) {
+	logger := middleware.GetLogger(ctx)
+
+	currentTodo, err := authorizeTodo(ctx, s.todoRepo, userID, payload.ID, share.RoleEditor)
+	if err != nil {
+		logger.Error().Err(err).Msg("todo validation failed")
+		return nil, err
+	}
+
+	movedTodo, err := s.todoRepo.MoveTodo(ctx.Request().Context(), userID, payload)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to move todo")
+		return nil, err
+	}
+
+	s.activityService.Record(ctx, &activity.Activity{
+		TodoID:     &movedTodo.ID,
+		UserID:     movedTodo.UserID,
+		EntityType: activity.EntityTypeTodo,
+		EntityID:   movedTodo.ID,
+		Action:     activity.ActionUpdated,
+		Changes:    activity.Diff(currentTodo, movedTodo),
+	})
+
+	s.eventService.Publish(ctx, movedTodo, event.TypeTodoUpdated, movedTodo)
+
+	logger.Info().
+		Str("event", "todo_moved").
+		Str("todo_id", movedTodo.ID.String()).
+		Int64("sort_order", movedTodo.SortOrder).
+		Msg("Todo moved successfully")
+
+	return movedTodo, nil
+}
+
+// validateRecurrenceUpdate enforces the series-level rules: the rule itself
+// belongs to the whole series, and only root todos with a due date can repeat.
+func validateRecurrenceUpdate(currentTodo *todo.Todo, payload *todo.UpdateTodoPayload) error {
+	startsOrChangesRule := payload.Recurrence != nil && *payload.Recurrence != ""
+
+	if currentTodo.IsRecurring() && payload.Recurrence != nil && *payload.Scope != todo.RecurrenceScopeFuture {
+		return errs.NewBadRequestError("Recurrence can only be changed for all future occurrences (scope=future)", false, nil, nil, nil)
+	}
+
+	isSubtask := payload.ParentTodoID != nil || (currentTodo.ParentTodoID != nil && !payload.Clears(todo.FieldParentTodoID))
+	if startsOrChangesRule && isSubtask {
+		return errs.NewBadRequestError("Subtasks cannot repeat", false, nil, nil, nil)
+	}
+
+	if currentTodo.IsRecurring() && payload.ParentTodoID != nil {
+		return errs.NewBadRequestError("Recurring todos cannot become subtasks", false, nil, nil, nil)
+	}
+
+	// A series keeps repeating unless the update stops it
+	repeats := startsOrChangesRule || (currentTodo.IsRecurring() && payload.Recurrence == nil)
+	hasDueDate := payload.DueDate != nil || (currentTodo.DueDate != nil && !payload.Clears(todo.FieldDueDate))
+	if repeats && !hasDueDate {
+		return errs.NewBadRequestError("Due date is required for recurring todos", false, nil, nil, nil)
+	}
+
+	return nil
+}
+
+func (s *TodoService) DeleteTodo(ctx echo.Context, userID string, todoID uuid.UUID, ifMatch *string) error {
+	logger := middleware.GetLogger(ctx)
+
+	// Only the owner can trash a todo, editors included
+	currentTodo, err := authorizeTodo(ctx, s.todoRepo, userID, todoID, share.RoleOwner)
+	if err != nil {
+		logger.Error().Err(err).Msg("todo validation failed")
+		return err
+	}
+
+	if err := etag.Check(ifMatch, currentTodo); err != nil {
+		logger.Warn().Msg("todo changed since the client read it")
+		return err
+	}
+
+	// Checked again under the todo's lock, in case it changed since
+	err = s.todoRepo.DeleteTodo(ctx.Request().Context(), userID, todoID, ifMatch)
 	if err != nil {
 		logger.Error().Err(err).Msg("failed to delete todo")
 		return err
 	}
 
+	s.todoDeleted(ctx, currentTodo)
+
 	// Business event log
 	eventLogger := middleware.GetLogger(ctx)
 	eventLogger.Info().
@@ -190,6 +520,222 @@ func (s *TodoService) DeleteTodo(ctx echo.Context, userID string, todoID uuid.UU
 	return nil
 }
 
+// todoDeleted records a todo going to the trash and tells everyone
+// following it.
+func (s *TodoService) todoDeleted(ctx echo.Context, currentTodo *todo.Todo) {
+	s.activityService.Record(ctx, &activity.Activity{
+		TodoID:     &currentTodo.ID,
+		UserID:     currentTodo.UserID,
+		EntityType: activity.EntityTypeTodo,
+		EntityID:   currentTodo.ID,
+		Action:     activity.ActionDeleted,
+		Changes:    activity.Diff(currentTodo, nil),
+	})
+
+	s.eventService.Publish(ctx, currentTodo, event.TypeTodoDeleted, currentTodo)
+}
+
+// BulkUpdateTodos applies one action to several todos. Each todo is first
+// checked against the rules of a single update or delete; in atomic mode one
+// failing todo leaves all of them unchanged.
+func (s *TodoService) BulkUpdateTodos(ctx echo.Context, userID string,
+	payload *todo.BulkTodosPayload,
+) (*todo.BulkResponse, error) {
+	logger := middleware.GetLogger(ctx)
+
+	response := &todo.BulkResponse{
+		Results: make([]todo.BulkResult, len(payload.IDs)),
+	}
+	current := make(map[uuid.UUID]*todo.Todo, len(payload.IDs))
+	validIDs := make([]uuid.UUID, 0, len(payload.IDs))
+
+	for i, todoID := range payload.IDs {
+		response.Results[i].ID = todoID
+
+		currentTodo, err := s.validateBulkItem(ctx, userID, todoID, payload)
+		if err != nil {
+			logger.Warn().Err(err).Str("todo_id", todoID.String()).Msg("bulk item validation failed")
+			response.Results[i].Status = todo.BulkResultFailed
+			response.Results[i].Error = bulkError(err)
+			continue
+		}
+
+		current[todoID] = currentTodo
+		validIDs = append(validIDs, todoID)
+	}
+
+	atomic := *payload.Mode == todo.BulkModeAtomic
+	updated := map[uuid.UUID]*todo.Todo{}
+
+	if len(validIDs) > 0 && (!atomic || len(validIDs) == len(payload.IDs)) {
+		todos, err := s.todoRepo.BulkUpdateTodos(ctx.Request().Context(), userID, validIDs, payload, atomic)
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to bulk update todos")
+			return nil, err
+		}
+
+		for i := range todos {
+			updated[todos[i].ID] = &todos[i]
+		}
+		response.Committed = true
+	}
+
+	for i := range response.Results {
+		result := &response.Results[i]
+
+		switch {
+		case result.Status == todo.BulkResultFailed:
+			response.Failed++
+		case !response.Committed:
+			result.Status = todo.BulkResultSkipped
+		case updated[result.ID] == nil:
+			// Deleted or unshared between the check and the update
+			result.Status = todo.BulkResultFailed
+			result.Error = &todo.BulkError{Code: "TODO_NOT_FOUND", Message: "todo not found"}
+			response.Failed++
+		default:
+			result.Status = todo.BulkResultSucceeded
+			result.Todo = updated[result.ID]
+			response.Succeeded++
+
+			if payload.Action == todo.BulkActionDelete {
+				s.todoDeleted(ctx, current[result.ID])
+			} else {
+				s.todoUpdated(ctx, current[result.ID], result.Todo)
+			}
+		}
+	}
+
+	// Business event log
+	eventLogger := middleware.GetLogger(ctx)
+	eventLogger.Info().
+		Str("event", "todos_bulk_updated").
+		Str("action", string(payload.Action)).
+		Str("mode", string(*payload.Mode)).
+		Bool("committed", response.Committed).
+		Int("succeeded", response.Succeeded).
+		Int("failed", response.Failed).
+		Msg("Todos bulk updated")
+
+	return response, nil
+}
+
+// validateBulkItem applies the checks UpdateTodo and DeleteTodo make to one
+// todo of a bulk action and returns the todo as it is now.
+func (s *TodoService) validateBulkItem(ctx echo.Context, userID string, todoID uuid.UUID,
+	payload *todo.BulkTodosPayload,
+) (*todo.Todo, error) {
+	// Only the owner can trash a todo, editors included
+	if payload.Action == todo.BulkActionDelete {
+		return authorizeTodo(ctx, s.todoRepo, userID, todoID, share.RoleOwner)
+	}
+
+	currentTodo, err := authorizeTodo(ctx, s.todoRepo, userID, todoID, share.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	switch payload.Action {
+	case todo.BulkActionSetStatus:
+		if *payload.Status == todo.StatusCompleted && currentTodo.Status != todo.StatusCompleted {
+			if err := s.validateCompletion(ctx, todoID); err != nil {
+				return nil, err
+			}
+		}
+
+	case todo.BulkActionMoveCategory:
+		if payload.CategoryID != nil {
+			if err := s.validateCategoryOwner(ctx, userID, currentTodo.UserID, *payload.CategoryID); err != nil {
+				return nil, err
+			}
+		}
+
+	case todo.BulkActionSetDueDate:
+		scope := todo.RecurrenceScopeThis
+		update := &todo.UpdateTodoPayload{ID: todoID, DueDate: payload.DueDate, Scope: &scope}
+		if payload.DueDate == nil {
+			update.Clear = []string{todo.FieldDueDate}
+		}
+
+		if err := validateRecurrenceUpdate(currentTodo, update); err != nil {
+			return nil, err
+		}
+	}
+
+	return currentTodo, nil
+}
+
+// bulkError describes why a todo was left out of a bulk action, in the terms
+// the error handler would have used for a single request.
+func bulkError(err error) *todo.BulkError {
+	var httpErr *errs.HTTPError
+	if !errors.As(sqlerr.HandleError(err), &httpErr) {
+		httpErr = errs.NewInternalServerError()
+	}
+
+	return &todo.BulkError{Code: httpErr.Code, Message: httpErr.Message}
+}
+
+func (s *TodoService) GetTrashedTodos(ctx echo.Context, userID string,
+	query *todo.GetTrashedTodosQuery,
+) (*model.PaginatedResponse[todo.Todo], error) {
+	logger := middleware.GetLogger(ctx)
+
+	result, err := s.todoRepo.GetTrashedTodos(ctx.Request().Context(), userID, query)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch trashed todos")
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (s *TodoService) RestoreTodo(ctx echo.Context, userID string, todoID uuid.UUID) (*todo.Todo, error) {
+	logger := middleware.GetLogger(ctx)
+
+	trashedTodo, err := s.todoRepo.GetTrashedTodo(ctx.Request().Context(), userID, todoID)
+	if err != nil {
+		logger.Error().Err(err).Msg("trashed todo validation failed")
+		return nil, err
+	}
+
+	// A subtask can't come back on its own while its parent is in the trash
+	if trashedTodo.ParentTodoID != nil {
+		if _, err := s.todoRepo.CheckTodoExists(ctx.Request().Context(), userID, *trashedTodo.ParentTodoID); err != nil {
+			code := "PARENT_TODO_TRASHED"
+			err := errs.NewBadRequestError("Restore the parent todo first", false, &code, nil, nil)
+			logger.Warn().Msg("parent todo is in the trash")
+			return nil, err
+		}
+	}
+
+	restoredTodo, err := s.todoRepo.RestoreTodo(ctx.Request().Context(), userID, trashedTodo)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to restore todo")
+		return nil, err
+	}
+
+	s.activityService.Record(ctx, &activity.Activity{
+		TodoID:     &restoredTodo.ID,
+		UserID:     restoredTodo.UserID,
+		EntityType: activity.EntityTypeTodo,
+		EntityID:   restoredTodo.ID,
+		Action:     activity.ActionRestored,
+		Changes:    activity.Diff(trashedTodo, restoredTodo),
+	})
+
+	s.eventService.Publish(ctx, restoredTodo, event.TypeTodoRestored, restoredTodo)
+
+	// Business event log
+	eventLogger := middleware.GetLogger(ctx)
+	eventLogger.Info().
+		Str("event", "todo_restored").
+		Str("todo_id", restoredTodo.ID.String()).
+		Msg("Todo restored successfully")
+
+	return restoredTodo, nil
+}
+
 func (s *TodoService) GetTodoStats(ctx echo.Context, userID string) (*todo.TodoStats, error) {
 	logger := middleware.GetLogger(ctx)
 
@@ -202,6 +748,148 @@ func (s *TodoService) GetTodoStats(ctx echo.Context, userID string) (*todo.TodoS
 	return stats, nil
 }
 
+func (s *TodoService) AddTodoBlocker(ctx echo.Context, userID string, payload *todo.AddTodoBlockerPayload) (*todo.TodoDependency, error) {
+	logger := middleware.GetLogger(ctx)
+
+	if payload.TodoID == payload.BlockerID {
+		err := errs.NewBadRequestError("Todo cannot block itself", false, nil, nil, nil)
+		logger.Warn().Msg("todo cannot block itself")
+		return nil, err
+	}
+
+	// The blocked todo is the one being changed; the blocker only has to be visible
+	if _, err := authorizeTodo(ctx, s.todoRepo, userID, payload.TodoID, share.RoleEditor); err != nil {
+		logger.Error().Err(err).Msg("todo validation failed")
+		return nil, err
+	}
+
+	if _, err := s.todoRepo.CheckTodoExists(ctx.Request().Context(), userID, payload.BlockerID); err != nil {
+		logger.Error().Err(err).Msg("blocker todo validation failed")
+		return nil, err
+	}
+
+	// Refused when the blocker already waits on the todo
+	dependency, err := s.todoRepo.AddTodoDependency(ctx.Request().Context(), userID, payload.BlockerID, payload.TodoID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to add todo dependency")
+		return nil, err
+	}
+
+	logger.Info().
+		Str("event", "todo_dependency_added").
+		Str("blocking_todo_id", dependency.BlockingTodoID.String()).
+		Str("blocked_todo_id", dependency.BlockedTodoID.String()).
+		Msg("Todo dependency added successfully")
+
+	return dependency, nil
+}
+
+func (s *TodoService) RemoveTodoBlocker(ctx echo.Context, userID string, payload *todo.RemoveTodoBlockerPayload) error {
+	logger := middleware.GetLogger(ctx)
+
+	if _, err := authorizeTodo(ctx, s.todoRepo, userID, payload.TodoID, share.RoleEditor); err != nil {
+		logger.Error().Err(err).Msg("todo validation failed")
+		return err
+	}
+
+	err := s.todoRepo.DeleteTodoDependency(ctx.Request().Context(), userID, payload.BlockerID, payload.TodoID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to remove todo dependency")
+		return err
+	}
+
+	logger.Info().
+		Str("event", "todo_dependency_removed").
+		Str("blocking_todo_id", payload.BlockerID.String()).
+		Str("blocked_todo_id", payload.TodoID.String()).
+		Msg("Todo dependency removed successfully")
+
+	return nil
+}
+
+func (s *TodoService) AddTodoWatcher(ctx echo.Context, userID string, payload *todo.AddTodoWatcherPayload) (*todo.TodoWatcher, error) {
+	logger := middleware.GetLogger(ctx)
+
+	watcherID := userID
+	if payload.UserID != nil && *payload.UserID != "me" {
+		watcherID = *payload.UserID
+	}
+
+	// Anyone who can see a todo may watch it; subscribing others takes an editor
+	required := share.RoleViewer
+	if watcherID != userID {
+		required = share.RoleEditor
+	}
+
+	todoItem, err := authorizeTodo(ctx, s.todoRepo, userID, payload.TodoID, required)
+	if err != nil {
+		logger.Error().Err(err).Msg("todo validation failed")
+		return nil, err
+	}
+
+	if watcherID != userID {
+		hasAccess, err := s.todoRepo.CanAccessTodo(ctx.Request().Context(), watcherID, todoItem.ID)
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to check watcher access")
+			return nil, err
+		}
+
+		if !hasAccess {
+			code := "WATCHER_NO_ACCESS"
+			err := errs.NewBadRequestError("Watcher must have access to the todo", false, &code, nil, nil)
+			logger.Warn().Str("watcher_id", watcherID).Msg("watcher can't see the todo")
+			return nil, err
+		}
+	}
+
+	watcher, err := s.todoRepo.AddTodoWatcher(ctx.Request().Context(), todoItem.ID, watcherID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to add todo watcher")
+		return nil, err
+	}
+
+	logger.Info().
+		Str("event", "todo_watcher_added").
+		Str("todo_id", todoItem.ID.String()).
+		Str("watcher_id", watcherID).
+		Msg("Todo watcher added successfully")
+
+	return watcher, nil
+}
+
+func (s *TodoService) RemoveTodoWatcher(ctx echo.Context, userID string, payload *todo.RemoveTodoWatcherPayload) error {
+	logger := middleware.GetLogger(ctx)
+
+	watcherID := payload.UserID
+	if watcherID == "me" {
+		watcherID = userID
+	}
+
+	required := share.RoleViewer
+	if watcherID != userID {
+		required = share.RoleEditor
+	}
+
+	if _, err := authorizeTodo(ctx, s.todoRepo, userID, payload.TodoID, required); err != nil {
+		logger.Error().Err(err).Msg("todo validation failed")
+		return err
+	}
+
+	err := s.todoRepo.DeleteTodoWatcher(ctx.Request().Context(), payload.TodoID, watcherID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to remove todo watcher")
+		return err
+	}
+
+	logger.Info().
+		Str("event", "todo_watcher_removed").
+		Str("todo_id", payload.TodoID.String()).
+		Str("watcher_id", watcherID).
+		Msg("Todo watcher removed successfully")
+
+	return nil
+}
+
 func (s *TodoService) UploadTodoAttachment(
 	ctx echo.Context,
 	userID string,
@@ -210,8 +898,8 @@ func (s *TodoService) UploadTodoAttachment(
 ) (*todo.TodoAttachment, error) {
 	logger := middleware.GetLogger(ctx)
 
-	// Verify todo exists and belongs to user
-	_, err := s.todoRepo.CheckTodoExists(ctx.Request().Context(), userID, todoID)
+	// Verify todo exists and the user can edit it
+	todoItem, err := authorizeTodo(ctx, s.todoRepo, userID, todoID, share.RoleEditor)
 	if err != nil {
 		logger.Error().Err(err).Msg("todo validation failed")
 		return nil, err
@@ -268,6 +956,18 @@ func (s *TodoService) UploadTodoAttachment(
 		return nil, err
 	}
 
+	s.activityService.Record(ctx, &activity.Activity{
+		TodoID:     &todoID,
+		UserID:     todoItem.UserID,
+		EntityType: activity.EntityTypeAttachment,
+		EntityID:   attachment.ID,
+		Action:     activity.ActionCreated,
+		Changes:    activity.Diff(nil, attachment),
+	})
+
+	s.webhookService.Dispatch(ctx, todoItem, webhook.EventAttachmentUploaded, attachment)
+	s.eventService.Publish(ctx, todoItem, event.TypeAttachmentUploaded, attachment)
+
 	logger.Info().
 		Str("attachment_id", attachment.ID.String()).
 		Str("s3_key", s3Key).
@@ -284,8 +984,8 @@ func (s *TodoService) DeleteTodoAttachment(
 ) error {
 	logger := middleware.GetLogger(ctx)
 
-	// Verify todo exists and belongs to user
-	_, err := s.todoRepo.CheckTodoExists(ctx.Request().Context(), userID, todoID)
+	// Verify todo exists and the user can edit it
+	todoItem, err := authorizeTodo(ctx, s.todoRepo, userID, todoID, share.RoleEditor)
 	if err != nil {
 		logger.Error().Err(err).Msg("todo validation failed")
 		return err
@@ -313,6 +1013,17 @@ func (s *TodoService) DeleteTodoAttachment(
 		return err
 	}
 
+	s.activityService.Record(ctx, &activity.Activity{
+		TodoID:     &todoID,
+		UserID:     todoItem.UserID,
+		EntityType: activity.EntityTypeAttachment,
+		EntityID:   attachment.ID,
+		Action:     activity.ActionDeleted,
+		Changes:    activity.Diff(attachment, nil),
+	})
+
+	s.eventService.Publish(ctx, todoItem, event.TypeAttachmentDeleted, attachment)
+
 	// Delete from S3 asynchronously
 	go func() {
 		err := s.awsClient.S3.DeleteObject(
@@ -341,7 +1052,7 @@ func (s *TodoService) GetAttachmentPresignedURL(
 ) (string, error) {
 	logger := middleware.GetLogger(ctx)
 
-	// Verify todo exists and belongs to user
+	// Verify todo exists and is visible to the user
 	_, err := s.todoRepo.CheckTodoExists(ctx.Request().Context(), userID, todoID)
 	if err != nil {
 		logger.Error().Err(err).Msg("todo validation failed")
@@ -0,0 +1,221 @@
+package service
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/uttam282005/tasker/internal/model/todo"
+)
+
+// todoExporter writes an export one todo at a time, so exports never have
+// to be held in memory.
+type todoExporter interface {
+	begin() error
+	write(t todo.ExportedTodo) error
+	end() error
+}
+
+func newTodoExporter(format todo.ExportFormat, w io.Writer) todoExporter {
+	switch format {
+	case todo.ExportFormatCSV:
+		return &csvTodoExporter{w: csv.NewWriter(w)}
+	case todo.ExportFormatMarkdown:
+		return &markdownTodoExporter{w: w}
+	default:
+		return &jsonTodoExporter{w: w}
+	}
+}
+
+// ------------------------------------------------------------
+
+// csvTodoExporter writes a row per todo, subtasks right after their parent
+// with parentId set.
+type csvTodoExporter struct {
+	w *csv.Writer
+}
+
+var csvTodoHeader = []string{
+	"id", "parentId", "title", "description", "status", "priority", "dueDate", "completedAt",
+	"category", "tags", "assigneeId", "comments", "createdAt", "updatedAt",
+}
+
+func (e *csvTodoExporter) begin() error {
+	return e.w.Write(csvTodoHeader)
+}
+
+func (e *csvTodoExporter) write(t todo.ExportedTodo) error {
+	if err := e.w.Write(csvTodoRecord(t, "")); err != nil {
+		return err
+	}
+	for _, subtask := range t.Subtasks {
+		if err := e.w.Write(csvTodoRecord(subtask, t.ID.String())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *csvTodoExporter) end() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func csvTodoRecord(t todo.ExportedTodo, parentID string) []string {
+	comments := make([]string, 0, len(t.Comments))
+	for _, c := range t.Comments {
+		comments = append(comments, fmt.Sprintf("%s (%s): %s", c.UserID, c.CreatedAt.Format(time.RFC3339), c.Content))
+	}
+
+	return []string{
+		t.ID.String(),
+		parentID,
+		t.Title,
+		stringOrEmpty(t.Description),
+		string(t.Status),
+		string(t.Priority),
+		timeOrEmpty(t.DueDate),
+		timeOrEmpty(t.CompletedAt),
+		stringOrEmpty(t.Category),
+		strings.Join(t.Tags, ", "),
+		stringOrEmpty(t.AssigneeID),
+		strings.Join(comments, "\n"),
+		t.CreatedAt.Format(time.RFC3339),
+		t.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// ------------------------------------------------------------
+
+// jsonTodoExporter writes a JSON array of todos, each with its subtasks.
+type jsonTodoExporter struct {
+	w     io.Writer
+	count int
+}
+
+func (e *jsonTodoExporter) begin() error {
+	_, err := io.WriteString(e.w, "[")
+	return err
+}
+
+func (e *jsonTodoExporter) write(t todo.ExportedTodo) error {
+	data, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+
+	separator := "\n"
+	if e.count > 0 {
+		separator = ",\n"
+	}
+	e.count++
+
+	if _, err := io.WriteString(e.w, separator); err != nil {
+		return err
+	}
+	_, err = e.w.Write(data)
+	return err
+}
+
+func (e *jsonTodoExporter) end() error {
+	_, err := io.WriteString(e.w, "\n]\n")
+	return err
+}
+
+// ------------------------------------------------------------
+
+// markdownTodoExporter writes a section per todo, with its subtasks as a
+// checklist.
+type markdownTodoExporter struct {
+	w io.Writer
+}
+
+func (e *markdownTodoExporter) begin() error {
+	_, err := io.WriteString(e.w, "# Todos\n")
+	return err
+}
+
+func (e *markdownTodoExporter) write(t todo.ExportedTodo) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "\n## %s\n\n", markdownLine(t.Title))
+	writeMarkdownDetails(&b, t, "")
+
+	if t.Description != nil && *t.Description != "" {
+		fmt.Fprintf(&b, "\n%s\n", *t.Description)
+	}
+
+	if len(t.Subtasks) > 0 {
+		b.WriteString("\n### Subtasks\n\n")
+		for _, subtask := range t.Subtasks {
+			fmt.Fprintf(&b, "- [%s] %s\n", markdownCheck(subtask), markdownLine(subtask.Title))
+			writeMarkdownDetails(&b, subtask, "  ")
+			writeMarkdownComments(&b, subtask.Comments, "  ")
+		}
+	}
+
+	if len(t.Comments) > 0 {
+		b.WriteString("\n### Comments\n\n")
+		writeMarkdownComments(&b, t.Comments, "")
+	}
+
+	_, err := io.WriteString(e.w, b.String())
+	return err
+}
+
+func (e *markdownTodoExporter) end() error {
+	return nil
+}
+
+func writeMarkdownDetails(b *strings.Builder, t todo.ExportedTodo, indent string) {
+	fmt.Fprintf(b, "%s- Status: %s\n", indent, t.Status)
+	fmt.Fprintf(b, "%s- Priority: %s\n", indent, t.Priority)
+	if t.DueDate != nil {
+		fmt.Fprintf(b, "%s- Due: %s\n", indent, t.DueDate.Format(time.RFC3339))
+	}
+	if t.Category != nil {
+		fmt.Fprintf(b, "%s- Category: %s\n", indent, markdownLine(*t.Category))
+	}
+	if len(t.Tags) > 0 {
+		fmt.Fprintf(b, "%s- Tags: %s\n", indent, markdownLine(strings.Join(t.Tags, ", ")))
+	}
+	if t.AssigneeID != nil {
+		fmt.Fprintf(b, "%s- Assignee: %s\n", indent, *t.AssigneeID)
+	}
+}
+
+func writeMarkdownComments(b *strings.Builder, comments []todo.ExportedComment, indent string) {
+	for _, c := range comments {
+		fmt.Fprintf(b, "%s> **%s**, %s: %s\n", indent, c.UserID, c.CreatedAt.Format(time.RFC3339),
+			strings.ReplaceAll(c.Content, "\n", "\n"+indent+"> "))
+	}
+}
+
+func markdownCheck(t todo.ExportedTodo) string {
+	if t.Status == todo.StatusCompleted {
+		return "x"
+	}
+	return " "
+}
+
+// markdownLine keeps a value on the line it's written on.
+func markdownLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func stringOrEmpty(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func timeOrEmpty(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,263 @@
+package service
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/uttam282005/tasker/internal/errs"
+	"github.com/uttam282005/tasker/internal/lib/workspace"
+	"github.com/uttam282005/tasker/internal/middleware"
+	"github.com/uttam282005/tasker/internal/model"
+	"github.com/uttam282005/tasker/internal/model/todo"
+	"github.com/uttam282005/tasker/internal/model/view"
+	"github.com/uttam282005/tasker/internal/repository"
+	"github.com/uttam282005/tasker/internal/server"
+)
+
+type ViewService struct {
+	server   *server.Server
+	viewRepo *repository.ViewRepository
+	todoRepo *repository.TodoRepository
+}
+
+func NewViewService(server *server.Server, viewRepo *repository.ViewRepository, todoRepo *repository.TodoRepository) *ViewService {
+	return &ViewService{
+		server:   server,
+		viewRepo: viewRepo,
+		todoRepo: todoRepo,
+	}
+}
+
+func (s *ViewService) CreateView(ctx echo.Context, userID string, payload *view.CreateViewPayload) (*view.View, error) {
+	logger := middleware.GetLogger(ctx)
+
+	if payload.Shared != nil && *payload.Shared {
+		if err := checkViewSharing(ctx); err != nil {
+			logger.Warn().Msg("view shared outside an organization")
+			return nil, err
+		}
+	}
+
+	viewItem, err := s.viewRepo.CreateView(ctx.Request().Context(), userID, payload)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to create view")
+		return nil, err
+	}
+
+	// Business event log
+	eventLogger := middleware.GetLogger(ctx)
+	eventLogger.Info().
+		Str("event", "view_created").
+		Str("view_id", viewItem.ID.String()).
+		Str("name", viewItem.Name).
+		Bool("shared", viewItem.Shared).
+		Msg("View created successfully")
+
+	return viewItem, nil
+}
+
+func (s *ViewService) GetViews(ctx echo.Context, userID string) ([]view.View, error) {
+	logger := middleware.GetLogger(ctx)
+
+	views, err := s.viewRepo.GetViews(ctx.Request().Context(), userID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch views")
+		return nil, err
+	}
+
+	return views, nil
+}
+
+func (s *ViewService) GetSystemViews(ctx echo.Context) []view.SystemView {
+	return view.SystemViews
+}
+
+func (s *ViewService) GetViewByID(ctx echo.Context, userID string, viewID uuid.UUID) (*view.View, error) {
+	logger := middleware.GetLogger(ctx)
+
+	viewItem, err := s.viewRepo.GetViewByID(ctx.Request().Context(), userID, viewID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch view by ID")
+		return nil, err
+	}
+
+	return viewItem, nil
+}
+
+func (s *ViewService) UpdateView(ctx echo.Context, userID string, payload *view.UpdateViewPayload) (*view.View, error) {
+	logger := middleware.GetLogger(ctx)
+
+	currentView, err := s.viewRepo.GetViewByID(ctx.Request().Context(), userID, payload.ID)
+	if err != nil {
+		logger.Error().Err(err).Msg("view validation failed")
+		return nil, err
+	}
+
+	// Members can open a shared view but only its creator can change it
+	if currentView.UserID != userID {
+		err := errs.NewForbiddenError("Only the creator can change a view", false)
+		logger.Warn().Msg("view belongs to another user")
+		return nil, err
+	}
+
+	if payload.Shared != nil && *payload.Shared {
+		if err := checkViewSharing(ctx); err != nil {
+			logger.Warn().Msg("view shared outside an organization")
+			return nil, err
+		}
+	}
+
+	viewItem, err := s.viewRepo.UpdateView(ctx.Request().Context(), userID, payload)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to update view")
+		return nil, err
+	}
+
+	// Business event log
+	eventLogger := middleware.GetLogger(ctx)
+	eventLogger.Info().
+		Str("event", "view_updated").
+		Str("view_id", viewItem.ID.String()).
+		Str("name", viewItem.Name).
+		Bool("shared", viewItem.Shared).
+		Msg("View updated successfully")
+
+	return viewItem, nil
+}
+
+func (s *ViewService) DeleteView(ctx echo.Context, userID string, viewID uuid.UUID) error {
+	logger := middleware.GetLogger(ctx)
+
+	currentView, err := s.viewRepo.GetViewByID(ctx.Request().Context(), userID, viewID)
+	if err != nil {
+		logger.Error().Err(err).Msg("view validation failed")
+		return err
+	}
+
+	if currentView.UserID != userID {
+		err := errs.NewForbiddenError("Only the creator can delete a view", false)
+		logger.Warn().Msg("view belongs to another user")
+		return err
+	}
+
+	if err := s.viewRepo.DeleteView(ctx.Request().Context(), userID, viewID); err != nil {
+		logger.Error().Err(err).Msg("failed to delete view")
+		return err
+	}
+
+	// Business event log
+	eventLogger := middleware.GetLogger(ctx)
+	eventLogger.Info().
+		Str("event", "view_deleted").
+		Str("view_id", viewID.String()).
+		Msg("View deleted successfully")
+
+	return nil
+}
+
+// PinView pins a view the user can open, shared views included.
+func (s *ViewService) PinView(ctx echo.Context, userID string, viewID uuid.UUID) (*view.View, error) {
+	logger := middleware.GetLogger(ctx)
+
+	viewItem, err := s.viewRepo.GetViewByID(ctx.Request().Context(), userID, viewID)
+	if err != nil {
+		logger.Error().Err(err).Msg("view validation failed")
+		return nil, err
+	}
+
+	if err := s.viewRepo.PinView(ctx.Request().Context(), userID, viewID); err != nil {
+		logger.Error().Err(err).Msg("failed to pin view")
+		return nil, err
+	}
+	viewItem.Pinned = true
+
+	return viewItem, nil
+}
+
+func (s *ViewService) UnpinView(ctx echo.Context, userID string, viewID uuid.UUID) error {
+	logger := middleware.GetLogger(ctx)
+
+	if _, err := s.viewRepo.GetViewByID(ctx.Request().Context(), userID, viewID); err != nil {
+		logger.Error().Err(err).Msg("view validation failed")
+		return err
+	}
+
+	if err := s.viewRepo.UnpinView(ctx.Request().Context(), userID, viewID); err != nil {
+		logger.Error().Err(err).Msg("failed to unpin view")
+		return err
+	}
+
+	return nil
+}
+
+// GetViewTodos runs a saved view through the todo list, for the requesting
+// user rather than the view's creator.
+func (s *ViewService) GetViewTodos(ctx echo.Context, userID string, query *view.GetViewTodosQuery,
+) (*model.CursorPaginatedResponse[todo.PopulatedTodo], error) {
+	logger := middleware.GetLogger(ctx)
+
+	viewItem, err := s.viewRepo.GetViewByID(ctx.Request().Context(), userID, query.ID)
+	if err != nil {
+		logger.Error().Err(err).Msg("view validation failed")
+		return nil, err
+	}
+
+	todosQuery, err := query.TodosQuery(viewItem.Filters, viewItem.Sort, viewItem.Order)
+	if err != nil {
+		logger.Warn().Err(err).Msg("invalid view paging")
+		return nil, err
+	}
+
+	result, err := s.todoRepo.GetTodos(ctx.Request().Context(), userID, todosQuery)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch view todos")
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (s *ViewService) GetSystemViewTodos(ctx echo.Context, userID string, query *view.GetSystemViewTodosQuery,
+) (*model.CursorPaginatedResponse[todo.PopulatedTodo], error) {
+	logger := middleware.GetLogger(ctx)
+
+	systemView := view.GetSystemView(query.Key)
+	if systemView == nil {
+		code := "VIEW_NOT_FOUND"
+		return nil, errs.NewNotFoundError("view not found", false, &code)
+	}
+
+	location := time.UTC
+	if query.Timezone != nil {
+		// The timezone validator has loaded it already
+		location, _ = time.LoadLocation(*query.Timezone)
+	}
+
+	filters := systemView.Filters(time.Now().In(location))
+	todosQuery, err := query.TodosQuery(filters, systemView.Sort, systemView.Order)
+	if err != nil {
+		logger.Warn().Err(err).Msg("invalid view paging")
+		return nil, err
+	}
+
+	result, err := s.todoRepo.GetTodos(ctx.Request().Context(), userID, todosQuery)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch system view todos")
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// checkViewSharing refuses to share a view outside an organization, where
+// there's no one to share it with.
+func checkViewSharing(ctx echo.Context) error {
+	if workspace.OrgID(ctx.Request().Context()) != nil {
+		return nil
+	}
+
+	code := "VIEW_SHARING_REQUIRES_ORGANIZATION"
+	return errs.NewBadRequestError("Only views in an organization can be shared", false, &code,
+		[]errs.FieldError{{Field: "shared", Error: "requires an organization"}}, nil)
+}
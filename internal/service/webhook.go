@@ -0,0 +1,426 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"github.com/uttam282005/tasker/internal/lib/job"
+	"github.com/uttam282005/tasker/internal/lib/signature"
+	"github.com/uttam282005/tasker/internal/middleware"
+	"github.com/uttam282005/tasker/internal/model"
+	"github.com/uttam282005/tasker/internal/model/todo"
+	"github.com/uttam282005/tasker/internal/model/webhook"
+	"github.com/uttam282005/tasker/internal/repository"
+	"github.com/uttam282005/tasker/internal/server"
+)
+
+// Headers sent with every delivery. Receivers check the signature with the
+// webhook's secret over the timestamp header and the raw body.
+const (
+	webhookHeaderEvent     = "X-Tasker-Event"
+	webhookHeaderDelivery  = "X-Tasker-Delivery"
+	webhookHeaderTimestamp = "X-Tasker-Timestamp"
+	webhookHeaderSignature = "X-Tasker-Signature"
+)
+
+// webhookResponseLimit caps how much of an endpoint's response is logged.
+// The log is readable through the API, so it only keeps enough to tell why
+// a delivery failed.
+const webhookResponseLimit = 256
+
+type WebhookService struct {
+	server      *server.Server
+	webhookRepo *repository.WebhookRepository
+	httpClient  *http.Client
+}
+
+func NewWebhookService(server *server.Server, webhookRepo *repository.WebhookRepository) *WebhookService {
+	return &WebhookService{
+		server:      server,
+		webhookRepo: webhookRepo,
+		httpClient:  newWebhookClient(),
+	}
+}
+
+// newWebhookClient returns the client deliveries are sent with. Webhook URLs
+// are chosen by users, so it only connects to public addresses, checked on
+// the address actually dialed so DNS can't point it elsewhere, and it doesn't
+// follow redirects, which could lead anywhere.
+func newWebhookClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 5 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			addr, err := netip.ParseAddr(host)
+			if err != nil {
+				return err
+			}
+			if !isPublicAddr(addr) {
+				return fmt.Errorf("webhook address %s is not public", addr)
+			}
+			return nil
+		},
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// A proxy would be dialed instead of the endpoint
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   10 * time.Second,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// nonPublicPrefixes are unicast ranges that netip doesn't count as private
+// but that don't lead to the internet either.
+var nonPublicPrefixes = []netip.Prefix{
+	// Shared address space for carrier-grade NAT
+	netip.MustParsePrefix("100.64.0.0/10"),
+	// IETF protocol assignments
+	netip.MustParsePrefix("192.0.0.0/24"),
+	// Benchmarking
+	netip.MustParsePrefix("198.18.0.0/15"),
+	// NAT64, which a gateway translates to any IPv4 address, private ones too
+	netip.MustParsePrefix("64:ff9b::/96"),
+	netip.MustParsePrefix("64:ff9b:1::/48"),
+}
+
+// isPublicAddr reports whether addr is routable on the internet, as opposed
+// to loopback, private, link-local, multicast, unspecified or one of
+// nonPublicPrefixes.
+func isPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return false
+	}
+	for _, prefix := range nonPublicPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *WebhookService) CreateWebhook(ctx echo.Context, userID string,
+	payload *webhook.CreateWebhookPayload,
+) (*webhook.CreatedWebhook, error) {
+	logger := middleware.GetLogger(ctx)
+
+	secret := ""
+	if payload.Secret != nil {
+		secret = *payload.Secret
+	} else {
+		generated, err := signature.NewSecret()
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to generate webhook secret")
+			return nil, errors.Wrap(err, "failed to generate webhook secret")
+		}
+		secret = generated
+	}
+
+	webhookItem, err := s.webhookRepo.CreateWebhook(ctx.Request().Context(), userID, payload, secret)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to create webhook")
+		return nil, err
+	}
+
+	// Business event log
+	eventLogger := middleware.GetLogger(ctx)
+	eventLogger.Info().
+		Str("event", "webhook_created").
+		Str("webhook_id", webhookItem.ID.String()).
+		Str("url", webhookItem.URL).
+		Msg("Webhook created successfully")
+
+	return &webhook.CreatedWebhook{
+		Webhook: *webhookItem,
+		Secret:  webhookItem.Secret,
+	}, nil
+}
+
+func (s *WebhookService) GetWebhooks(ctx echo.Context, userID string) ([]webhook.Webhook, error) {
+	logger := middleware.GetLogger(ctx)
+
+	webhooks, err := s.webhookRepo.GetWebhooks(ctx.Request().Context(), userID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch webhooks")
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+func (s *WebhookService) GetWebhookByID(ctx echo.Context, userID string, webhookID uuid.UUID) (*webhook.Webhook, error) {
+	logger := middleware.GetLogger(ctx)
+
+	webhookItem, err := s.webhookRepo.GetWebhookByID(ctx.Request().Context(), userID, webhookID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch webhook by ID")
+		return nil, err
+	}
+
+	return webhookItem, nil
+}
+
+func (s *WebhookService) UpdateWebhook(ctx echo.Context, userID string,
+	payload *webhook.UpdateWebhookPayload,
+) (*webhook.Webhook, error) {
+	logger := middleware.GetLogger(ctx)
+
+	webhookItem, err := s.webhookRepo.UpdateWebhook(ctx.Request().Context(), userID, payload)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to update webhook")
+		return nil, err
+	}
+
+	// Business event log
+	eventLogger := middleware.GetLogger(ctx)
+	eventLogger.Info().
+		Str("event", "webhook_updated").
+		Str("webhook_id", webhookItem.ID.String()).
+		Bool("active", webhookItem.Active).
+		Msg("Webhook updated successfully")
+
+	return webhookItem, nil
+}
+
+func (s *WebhookService) DeleteWebhook(ctx echo.Context, userID string, webhookID uuid.UUID) error {
+	logger := middleware.GetLogger(ctx)
+
+	if err := s.webhookRepo.DeleteWebhook(ctx.Request().Context(), userID, webhookID); err != nil {
+		logger.Error().Err(err).Msg("failed to delete webhook")
+		return err
+	}
+
+	// Business event log
+	eventLogger := middleware.GetLogger(ctx)
+	eventLogger.Info().
+		Str("event", "webhook_deleted").
+		Str("webhook_id", webhookID.String()).
+		Msg("Webhook deleted successfully")
+
+	return nil
+}
+
+func (s *WebhookService) GetDeliveries(ctx echo.Context, userID string,
+	query *webhook.GetDeliveriesQuery,
+) (*model.PaginatedResponse[webhook.Delivery], error) {
+	logger := middleware.GetLogger(ctx)
+
+	// Verify the webhook belongs to the user's workspace
+	if _, err := s.webhookRepo.GetWebhookByID(ctx.Request().Context(), userID, query.WebhookID); err != nil {
+		logger.Error().Err(err).Msg("webhook validation failed")
+		return nil, err
+	}
+
+	result, err := s.webhookRepo.GetDeliveries(ctx.Request().Context(), query)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch webhook deliveries")
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Redeliver sends a past delivery's payload again as a new delivery, leaving
+// the original's log untouched.
+func (s *WebhookService) Redeliver(ctx echo.Context, userID string,
+	payload *webhook.RedeliverPayload,
+) (*webhook.Delivery, error) {
+	logger := middleware.GetLogger(ctx)
+
+	// Verify the webhook belongs to the user's workspace
+	if _, err := s.webhookRepo.GetWebhookByID(ctx.Request().Context(), userID, payload.WebhookID); err != nil {
+		logger.Error().Err(err).Msg("webhook validation failed")
+		return nil, err
+	}
+
+	original, err := s.webhookRepo.GetWebhookDelivery(ctx.Request().Context(), payload.WebhookID, payload.DeliveryID)
+	if err != nil {
+		logger.Error().Err(err).Msg("delivery validation failed")
+		return nil, err
+	}
+
+	delivery, err := s.webhookRepo.CreateDelivery(ctx.Request().Context(), original.WebhookID, original.Event, original.Payload)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to create delivery")
+		return nil, err
+	}
+
+	if err := job.EnqueueWebhookDelivery(s.server.Job.Client, delivery.ID); err != nil {
+		logger.Error().Err(err).Msg("failed to enqueue webhook delivery")
+		return nil, errors.Wrap(err, "failed to enqueue webhook delivery")
+	}
+
+	// Business event log
+	eventLogger := middleware.GetLogger(ctx)
+	eventLogger.Info().
+		Str("event", "webhook_redelivered").
+		Str("webhook_id", payload.WebhookID.String()).
+		Str("original_delivery_id", original.ID.String()).
+		Str("delivery_id", delivery.ID.String()).
+		Msg("Webhook delivery queued again")
+
+	return delivery, nil
+}
+
+// Dispatch queues a delivery of data to every webhook in the todo's workspace
+// that subscribes to event. Like activity, it runs after the change has been
+// written, so failures are logged instead of failing the request.
+func (s *WebhookService) Dispatch(ctx echo.Context, todoItem *todo.Todo, event webhook.Event, data any) {
+	logger := middleware.GetLogger(ctx).With().
+		Str("webhook_event", string(event)).
+		Str("todo_id", todoItem.ID.String()).
+		Logger()
+
+	webhooks, err := s.webhookRepo.GetSubscribedWebhooks(ctx.Request().Context(), todoItem.UserID, todoItem.OrgID, event)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch subscribed webhooks")
+		return
+	}
+
+	if len(webhooks) == 0 {
+		return
+	}
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to encode webhook payload")
+		return
+	}
+
+	for _, webhookItem := range webhooks {
+		delivery, err := s.webhookRepo.CreateDelivery(ctx.Request().Context(), webhookItem.ID, event, payload)
+		if err != nil {
+			logger.Error().Err(err).Str("webhook_id", webhookItem.ID.String()).Msg("failed to create delivery")
+			continue
+		}
+
+		if err := job.EnqueueWebhookDelivery(s.server.Job.Client, delivery.ID); err != nil {
+			logger.Error().Err(err).Str("delivery_id", delivery.ID.String()).Msg("failed to enqueue webhook delivery")
+		}
+	}
+}
+
+// DeliverWebhook makes one attempt at sending a delivery and records the
+// outcome. It implements job.WebhookDelivererInterface: network errors,
+// timeouts, 408, 429 and 5xx responses are worth retrying, any other
+// non-2xx response fails the delivery immediately.
+func (s *WebhookService) DeliverWebhook(ctx context.Context, deliveryID uuid.UUID, final bool) (bool, error) {
+	delivery, err := s.webhookRepo.GetDeliveryByID(ctx, deliveryID)
+	if err != nil {
+		// The webhook, and its deliveries with it, may have been deleted since
+		return !errors.Is(err, pgx.ErrNoRows), err
+	}
+
+	webhookItem, err := s.webhookRepo.GetWebhookForDelivery(ctx, delivery.WebhookID)
+	if err != nil {
+		return !errors.Is(err, pgx.ErrNoRows), err
+	}
+
+	if !webhookItem.Active {
+		err := errors.New("webhook is disabled")
+		s.recordAttempt(ctx, delivery.ID, webhook.DeliveryStatusFailed, nil, nil, err, 0)
+		return false, err
+	}
+
+	body, err := json.Marshal(webhook.Envelope{
+		ID:        delivery.ID,
+		Event:     delivery.Event,
+		CreatedAt: delivery.CreatedAt,
+		Data:      delivery.Payload,
+	})
+	if err != nil {
+		return false, errors.Wrap(err, "failed to encode webhook envelope")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookItem.URL, bytes.NewReader(body))
+	if err != nil {
+		s.recordAttempt(ctx, delivery.ID, webhook.DeliveryStatusFailed, nil, nil, err, 0)
+		return false, err
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Tasker-Webhooks/1.0")
+	req.Header.Set(webhookHeaderEvent, string(delivery.Event))
+	req.Header.Set(webhookHeaderDelivery, delivery.ID.String())
+	req.Header.Set(webhookHeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(webhookHeaderSignature, signature.Sign(webhookItem.Secret, timestamp, body))
+
+	// Until retries run out the delivery stays pending
+	failedStatus := webhook.DeliveryStatusPending
+	if final {
+		failedStatus = webhook.DeliveryStatusFailed
+	}
+
+	start := time.Now()
+	resp, err := s.httpClient.Do(req)
+	duration := time.Since(start)
+	if err != nil {
+		s.recordAttempt(ctx, delivery.ID, failedStatus, nil, nil, err, duration)
+		return true, err
+	}
+	defer resp.Body.Close()
+
+	responseBytes, _ := io.ReadAll(io.LimitReader(resp.Body, webhookResponseLimit))
+	// The cut may fall inside a character
+	responseBody := strings.ToValidUTF8(string(responseBytes), "")
+	responseCode := resp.StatusCode
+
+	if responseCode >= 200 && responseCode < 300 {
+		s.recordAttempt(ctx, delivery.ID, webhook.DeliveryStatusSucceeded, &responseCode, &responseBody, nil, duration)
+		return false, nil
+	}
+
+	retryable := responseCode >= 500 || responseCode == http.StatusRequestTimeout || responseCode == http.StatusTooManyRequests
+	if !retryable {
+		failedStatus = webhook.DeliveryStatusFailed
+	}
+
+	err = fmt.Errorf("endpoint responded with status %d", responseCode)
+	s.recordAttempt(ctx, delivery.ID, failedStatus, &responseCode, &responseBody, err, duration)
+	return retryable, err
+}
+
+// recordAttempt logs an attempt's outcome. Losing a log entry shouldn't send
+// the delivery again, so failures are only logged.
+func (s *WebhookService) recordAttempt(ctx context.Context, deliveryID uuid.UUID, status webhook.DeliveryStatus,
+	responseCode *int, responseBody *string, attemptErr error, duration time.Duration,
+) {
+	var errMsg *string
+	if attemptErr != nil {
+		msg := attemptErr.Error()
+		errMsg = &msg
+	}
+
+	err := s.webhookRepo.RecordDeliveryAttempt(ctx, deliveryID, status, responseCode, responseBody, errMsg, duration)
+	if err != nil {
+		s.server.Logger.Error().
+			Err(err).
+			Str("delivery_id", deliveryID.String()).
+			Msg("failed to record webhook delivery attempt")
+	}
+}
@@ -0,0 +1,274 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uttam282005/tasker/internal/lib/signature"
+	"github.com/uttam282005/tasker/internal/model/webhook"
+	"github.com/uttam282005/tasker/internal/repository"
+	"github.com/uttam282005/tasker/internal/service"
+	testhelpers "github.com/uttam282005/tasker/internal/testing"
+)
+
+const testWebhookSecret = "whsec_test_0123456789"
+
+// webhookFixture is a webhook pointed at a local receiver, with one delivery
+// waiting to be sent.
+type webhookFixture struct {
+	service  *service.WebhookService
+	repo     *repository.WebhookRepository
+	delivery *webhook.Delivery
+}
+
+func setupWebhookFixture(t *testing.T, receiver http.HandlerFunc) *webhookFixture {
+	t.Helper()
+
+	_, srv, cleanup := testhelpers.SetupTest(t)
+	t.Cleanup(cleanup)
+
+	server := httptest.NewServer(receiver)
+	t.Cleanup(server.Close)
+
+	ctx := context.Background()
+	repo := repository.NewWebhookRepository(srv)
+
+	webhookItem, err := repo.CreateWebhook(ctx, "user_alice", &webhook.CreateWebhookPayload{
+		URL:    server.URL,
+		Events: []webhook.Event{webhook.EventTodoCreated},
+	}, testWebhookSecret)
+	require.NoError(t, err)
+
+	delivery, err := repo.CreateDelivery(ctx, webhookItem.ID, webhook.EventTodoCreated, json.RawMessage(`{"title":"Water the plants"}`))
+	require.NoError(t, err)
+
+	// Keep the real client's policies, minus the address check
+	client := service.NewWebhookClient()
+	client.Transport = server.Client().Transport
+
+	svc := service.NewWebhookService(srv, repo)
+	svc.SetHTTPClient(client)
+
+	return &webhookFixture{
+		service:  svc,
+		repo:     repo,
+		delivery: delivery,
+	}
+}
+
+func (f *webhookFixture) deliver(t *testing.T, final bool) (bool, error) {
+	t.Helper()
+	return f.service.DeliverWebhook(context.Background(), f.delivery.ID, final)
+}
+
+func (f *webhookFixture) reload(t *testing.T) *webhook.Delivery {
+	t.Helper()
+
+	delivery, err := f.repo.GetDeliveryByID(context.Background(), f.delivery.ID)
+	require.NoError(t, err)
+	return delivery
+}
+
+func respondWith(status int, body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		_, _ = io.WriteString(w, body)
+	}
+}
+
+func TestDeliverWebhookSignsRequest(t *testing.T) {
+	var received *http.Request
+	var receivedBody []byte
+
+	fixture := setupWebhookFixture(t, func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		receivedBody, _ = io.ReadAll(r.Body)
+		_, _ = io.WriteString(w, "ok")
+	})
+
+	retryable, err := fixture.deliver(t, false)
+	require.NoError(t, err)
+	assert.False(t, retryable)
+
+	require.NotNil(t, received)
+	assert.Equal(t, http.MethodPost, received.Method)
+	assert.Equal(t, "application/json", received.Header.Get("Content-Type"))
+	assert.Equal(t, string(webhook.EventTodoCreated), received.Header.Get("X-Tasker-Event"))
+	assert.Equal(t, fixture.delivery.ID.String(), received.Header.Get("X-Tasker-Delivery"))
+
+	timestamp, err := strconv.ParseInt(received.Header.Get("X-Tasker-Timestamp"), 10, 64)
+	require.NoError(t, err)
+	assert.True(t, signature.Verify(testWebhookSecret, timestamp, receivedBody, received.Header.Get("X-Tasker-Signature")),
+		"signature should verify with the webhook's secret")
+	assert.False(t, signature.Verify("whsec_someone_else", timestamp, receivedBody, received.Header.Get("X-Tasker-Signature")))
+
+	var envelope webhook.Envelope
+	require.NoError(t, json.Unmarshal(receivedBody, &envelope))
+	assert.Equal(t, fixture.delivery.ID, envelope.ID)
+	assert.Equal(t, webhook.EventTodoCreated, envelope.Event)
+	assert.JSONEq(t, `{"title":"Water the plants"}`, string(envelope.Data))
+
+	delivery := fixture.reload(t)
+	assert.Equal(t, webhook.DeliveryStatusSucceeded, delivery.Status)
+	assert.Equal(t, 1, delivery.Attempts)
+	require.NotNil(t, delivery.ResponseCode)
+	assert.Equal(t, http.StatusOK, *delivery.ResponseCode)
+	require.NotNil(t, delivery.ResponseBody)
+	assert.Equal(t, "ok", *delivery.ResponseBody)
+	assert.Nil(t, delivery.Error)
+	assert.NotNil(t, delivery.DeliveredAt)
+}
+
+func TestDeliverWebhookRetries(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		retryable bool
+	}{
+		{"server error", http.StatusInternalServerError, true},
+		{"unavailable", http.StatusServiceUnavailable, true},
+		{"timeout", http.StatusRequestTimeout, true},
+		{"rate limited", http.StatusTooManyRequests, true},
+		{"bad request", http.StatusBadRequest, false},
+		{"not found", http.StatusNotFound, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fixture := setupWebhookFixture(t, respondWith(tt.status, "nope"))
+
+			retryable, err := fixture.deliver(t, false)
+			require.Error(t, err)
+			assert.Equal(t, tt.retryable, retryable)
+
+			delivery := fixture.reload(t)
+			assert.Equal(t, 1, delivery.Attempts)
+			require.NotNil(t, delivery.ResponseCode)
+			assert.Equal(t, tt.status, *delivery.ResponseCode)
+			require.NotNil(t, delivery.Error)
+			assert.Nil(t, delivery.DeliveredAt)
+
+			// A retryable failure stays pending until the last attempt
+			if tt.retryable {
+				assert.Equal(t, webhook.DeliveryStatusPending, delivery.Status)
+			} else {
+				assert.Equal(t, webhook.DeliveryStatusFailed, delivery.Status)
+			}
+		})
+	}
+}
+
+func TestDeliverWebhookDoesNotFollowRedirects(t *testing.T) {
+	followed := false
+	fixture := setupWebhookFixture(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/elsewhere" {
+			followed = true
+			return
+		}
+		http.Redirect(w, r, "/elsewhere", http.StatusTemporaryRedirect)
+	})
+
+	retryable, err := fixture.deliver(t, false)
+	require.Error(t, err)
+	assert.False(t, retryable)
+	assert.False(t, followed)
+
+	delivery := fixture.reload(t)
+	assert.Equal(t, webhook.DeliveryStatusFailed, delivery.Status)
+	require.NotNil(t, delivery.ResponseCode)
+	assert.Equal(t, http.StatusTemporaryRedirect, *delivery.ResponseCode)
+}
+
+func TestDeliverWebhookFailsOnLastAttempt(t *testing.T) {
+	fixture := setupWebhookFixture(t, respondWith(http.StatusInternalServerError, "down"))
+
+	_, err := fixture.deliver(t, false)
+	require.Error(t, err)
+	assert.Equal(t, webhook.DeliveryStatusPending, fixture.reload(t).Status)
+
+	retryable, err := fixture.deliver(t, true)
+	require.Error(t, err)
+	assert.True(t, retryable)
+
+	delivery := fixture.reload(t)
+	assert.Equal(t, webhook.DeliveryStatusFailed, delivery.Status)
+	assert.Equal(t, 2, delivery.Attempts)
+}
+
+func TestDeliverWebhookTruncatesResponse(t *testing.T) {
+	fixture := setupWebhookFixture(t, respondWith(http.StatusOK, "a"+strings.Repeat("é", 1000)))
+
+	_, err := fixture.deliver(t, false)
+	require.NoError(t, err)
+
+	delivery := fixture.reload(t)
+	require.NotNil(t, delivery.ResponseBody)
+	// 256 bytes end halfway through a character, which is dropped
+	assert.Equal(t, "a"+strings.Repeat("é", 127), *delivery.ResponseBody)
+}
+
+func TestWebhookClientRefusesLocalAddresses(t *testing.T) {
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	resp, err := service.NewWebhookClient().Post(server.URL, "application/json", strings.NewReader("{}"))
+	if resp != nil {
+		resp.Body.Close()
+	}
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "is not public")
+	assert.False(t, called)
+}
+
+func TestIsPublicAddr(t *testing.T) {
+	tests := []struct {
+		addr   string
+		public bool
+	}{
+		{"8.8.8.8", true},
+		{"2606:4700:4700::1111", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"10.0.0.1", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		{"fc00::1", false},
+		{"0.0.0.0", false},
+		{"::", false},
+		{"224.0.0.1", false},
+		{"::ffff:127.0.0.1", false},
+		{"::ffff:8.8.8.8", true},
+		{"100.64.0.1", false},
+		{"100.127.255.254", false},
+		{"100.128.0.1", true},
+		{"192.0.0.8", false},
+		{"192.0.1.1", true},
+		{"198.18.0.1", false},
+		{"198.19.255.254", false},
+		{"198.20.0.1", true},
+		{"64:ff9b::a00:1", false},
+		{"64:ff9b::808:808", false},
+		{"64:ff9b:1::1", false},
+		{"::ffff:100.64.0.1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			assert.Equal(t, tt.public, service.IsPublicAddr(netip.MustParseAddr(tt.addr)))
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package testing
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+	"github.com/uttam282005/tasker/internal/handler"
+	"github.com/uttam282005/tasker/internal/lib/job"
+	"github.com/uttam282005/tasker/internal/repository"
+	"github.com/uttam282005/tasker/internal/router"
+	"github.com/uttam282005/tasker/internal/server"
+	"github.com/uttam282005/tasker/internal/service"
+)
+
+// TestAPI is the whole application wired against a test database, the way
+// cmd/tasker wires it. Redis, the job queue, S3 and email aren't running;
+// the side effects that need them log their failures without failing
+// requests.
+type TestAPI struct {
+	DB     *TestDB
+	Server *server.Server
+	Router *echo.Echo
+	Auth   *TestAuth
+
+	requests atomic.Int64
+}
+
+// SetupTestAPI starts a test database and builds the application's router
+// on top of it.
+func SetupTestAPI(t testing.TB) *TestAPI {
+	t.Helper()
+
+	testDB, srv, cleanup := SetupTest(t)
+	t.Cleanup(cleanup)
+
+	srv.Job = job.NewJobService(srv.Logger, srv.Config)
+	// Nothing listens there, so don't wait on retries
+	srv.Redis = redis.NewClient(&redis.Options{
+		Addr:       srv.Config.Redis.Address,
+		MaxRetries: -1,
+	})
+	t.Cleanup(func() {
+		srv.Redis.Close()
+		srv.Job.Client.Close()
+	})
+
+	repos := repository.NewRepositories(srv)
+	services, err := service.NewServices(srv, repos)
+	require.NoError(t, err, "failed to create services")
+
+	return &TestAPI{
+		DB:     testDB,
+		Server: srv,
+		Router: router.NewRouter(srv, handler.NewHandlers(srv, services), services),
+		Auth:   SetupTestAuth(t),
+	}
+}
+
+// Request sends a request with body encoded as JSON, unless it is nil, and
+// token as its bearer token, unless it is empty.
+func (a *TestAPI) Request(t testing.TB, method, path, token string, body any) *httptest.ResponseRecorder {
+	t.Helper()
+
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(MustMarshalJSON(t, body))
+	}
+
+	req := httptest.NewRequest(method, path, reader)
+	if body != nil {
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	}
+	if token != "" {
+		req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+	}
+
+	return a.Do(req)
+}
+
+// Do serves req. Every request comes from an address of its own, so the
+// router's rate limit never kicks in.
+func (a *TestAPI) Do(req *http.Request) *httptest.ResponseRecorder {
+	n := a.requests.Add(1)
+	req.Header.Set(echo.HeaderXRealIP, "10."+strconv.FormatInt(n>>16&255, 10)+"."+
+		strconv.FormatInt(n>>8&255, 10)+"."+strconv.FormatInt(n&255, 10))
+
+	rec := httptest.NewRecorder()
+	a.Router.ServeHTTP(rec, req)
+	return rec
+}
+
+// DecodeJSON decodes a response body into v or fails the test.
+func DecodeJSON[T any](t testing.TB, rec *httptest.ResponseRecorder) T {
+	t.Helper()
+
+	var v T
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &v), "failed to decode response: %s", rec.Body.String())
+	return v
+}
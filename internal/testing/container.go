@@ -22,9 +22,11 @@ type TestDB struct {
 	Config    *config.Config
 }
 
-// SetupTestDB creates a Postgres container and applies migrations
-func SetupTestDB(t *testing.T) (*TestDB, func()) {
+// SetupTestDB creates a Postgres container and applies migrations. Without
+// a container runtime the test is skipped.
+func SetupTestDB(t testing.TB) (*TestDB, func()) {
 	t.Helper()
+	skipWithoutDocker(t)
 
 	ctx := context.Background()
 	dbName := fmt.Sprintf("test_db_%s", uuid.New().String()[:8])
@@ -141,6 +143,24 @@ func SetupTestDB(t *testing.T) (*TestDB, func()) {
 	return testDB, cleanup
 }
 
+// skipWithoutDocker skips the test, or benchmark, when Docker isn't running.
+func skipWithoutDocker(t testing.TB) {
+	t.Helper()
+	defer func() {
+		if r := recover(); r != nil {
+			t.Skipf("Docker is not running: %v", r)
+		}
+	}()
+
+	provider, err := testcontainers.ProviderDocker.GetProvider()
+	if err != nil {
+		t.Skipf("Docker is not running: %v", err)
+	}
+	if err := provider.Health(context.Background()); err != nil {
+		t.Skipf("Docker is not running: %v", err)
+	}
+}
+
 // CleanupTestDB closes the database connection and terminates the container
 func (db *TestDB) CleanupTestDB(ctx context.Context, logger *zerolog.Logger) error {
 	logger.Info().Msg("cleaning up test database")
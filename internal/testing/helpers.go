@@ -12,7 +12,7 @@ import (
 )
 
 // SetupTest prepares a test environment with a database and server
-func SetupTest(t *testing.T) (*TestDB, *server.Server, func()) {
+func SetupTest(t testing.TB) (*TestDB, *server.Server, func()) {
 	t.Helper()
 
 	logger := zerolog.New(zerolog.ConsoleWriter{Out: os.Stdout}).
@@ -37,7 +37,7 @@ func SetupTest(t *testing.T) (*TestDB, *server.Server, func()) {
 }
 
 // MustMarshalJSON marshals an object to JSON or fails the test
-func MustMarshalJSON(t *testing.T, v interface{}) []byte {
+func MustMarshalJSON(t testing.TB, v interface{}) []byte {
 	t.Helper()
 
 	jsonBytes, err := json.Marshal(v)
@@ -47,7 +47,7 @@ func MustMarshalJSON(t *testing.T, v interface{}) []byte {
 }
 
 // ProjectRoot returns the absolute path to the project root
-func ProjectRoot(t *testing.T) string {
+func ProjectRoot(t testing.TB) string {
 	t.Helper()
 
 	dir, err := os.Getwd()
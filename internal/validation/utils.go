@@ -1,7 +1,10 @@
 package validation
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"mime"
 	"reflect"
 	"regexp"
 	"strings"
@@ -9,6 +12,7 @@ import (
 	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
 	"github.com/uttam282005/tasker/internal/errs"
+	"github.com/uttam282005/tasker/internal/lib/mergepatch"
 )
 
 type Validatable interface {
@@ -18,6 +22,8 @@ type Validatable interface {
 type CustomValidationError struct {
 	Field   string
 	Message string
+	// Position is where in the field's value the error is, if known
+	Position *int
 }
 
 type CustomValidationErrors []CustomValidationError
@@ -27,7 +33,17 @@ func (c CustomValidationErrors) Error() string {
 }
 
 func BindAndValidate(c echo.Context, payload Validatable) error {
-	if err := c.Bind(payload); err != nil {
+	if isMergePatch(c) {
+		if err := bindMergePatch(c, payload); err != nil {
+			return err
+		}
+	} else if err := c.Bind(payload); err != nil {
+		message := strings.Split(strings.Split(err.Error(), ",")[1], "message=")[1]
+		return errs.NewBadRequestError(message, false, nil, nil, nil)
+	}
+
+	// Bind leaves headers alone; conditional requests carry their preconditions there
+	if err := (&echo.DefaultBinder{}).BindHeaders(c, payload); err != nil {
 		message := strings.Split(strings.Split(err.Error(), ",")[1], "message=")[1]
 		return errs.NewBadRequestError(message, false, nil, nil, nil)
 	}
@@ -39,6 +55,29 @@ func BindAndValidate(c echo.Context, payload Validatable) error {
 	return nil
 }
 
+func isMergePatch(c echo.Context) bool {
+	mediaType, _, err := mime.ParseMediaType(c.Request().Header.Get(echo.HeaderContentType))
+	return err == nil && mediaType == mergepatch.MIMEApplicationMergePatchJSON
+}
+
+// bindMergePatch binds path params and a merge patch body, which Bind turns
+// away as an unsupported media type.
+func bindMergePatch(c echo.Context, payload Validatable) error {
+	if err := (&echo.DefaultBinder{}).BindPathParams(c, payload); err != nil {
+		message := strings.Split(strings.Split(err.Error(), ",")[1], "message=")[1]
+		return errs.NewBadRequestError(message, false, nil, nil, nil)
+	}
+
+	if err := json.NewDecoder(c.Request().Body).Decode(payload); err != nil {
+		if errors.Is(err, mergepatch.ErrNotObject) {
+			return errs.NewBadRequestError("Request body must be a JSON object", false, nil, nil, nil)
+		}
+		return errs.NewBadRequestError("Malformed merge patch: "+err.Error(), false, nil, nil, nil)
+	}
+
+	return nil
+}
+
 func validateStruct(v Validatable) (string, []errs.FieldError) {
 	if err := v.Validate(); err != nil {
 		return extractValidationErrors(err)
@@ -53,8 +92,9 @@ func extractValidationErrors(err error) (string, []errs.FieldError) {
 		customValidationErrors := err.(CustomValidationErrors)
 		for _, err := range customValidationErrors {
 			fieldErrors = append(fieldErrors, errs.FieldError{
-				Field: err.Field,
-				Error: err.Message,
+				Field:    err.Field,
+				Error:    err.Message,
+				Position: err.Position,
 			})
 		}
 	}